@@ -52,7 +52,7 @@ func printRoleEntries(log *audit.Log) {
 		case *audit.RoleUpdate:
 			fmt.Printf("role with ID %q was updated\n", e.ID)
 
-			// Fields that are of type *StringValues, *IntValues, *BoolValues
+			// Fields that are of type *StringValues, *IntValues, *Int64Values, *BoolValues
 			// are settings that have potentially been modified. If they are non-nil,
 			// it means they were and they will hold the old as well as the new value.
 			if e.Name != nil {
@@ -0,0 +1,12 @@
+#!/bin/bash
+
+# Builds and vets the module for a 32-bit architecture, where int is 32 bits wide
+# and can not hold permissions such as discord.PermissionModerateMembers.
+# The voice/voiceutil package relies on cgo and is skipped.
+
+set -eu -o pipefail
+
+pkgs=$(go list ./... | grep -v /voice/voiceutil)
+
+GOARCH=386 go build ${pkgs}
+GOARCH=386 go vet ${pkgs}
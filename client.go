@@ -81,8 +81,24 @@ type Client struct {
 	// See WithGatewayIntents for more information.
 	intents discord.GatewayIntent
 
-	userID    string
-	sessionID string
+	userID string
+	// ID of the current Gateway session. It is set by the goroutine
+	// listening for Gateway events and can be read from anywhere.
+	sessionID *atomic.String
+
+	// Pending guild members requests, by nonce.
+	// See FetchGuildMembers for more information.
+	membersRequestsMu  sync.Mutex
+	membersRequests    map[string]*membersRequest
+	membersRequestsSeq *atomic.Int64
+
+	// Guilds announced in the Ready event that are
+	// still loading. See OnGuildsReady for more information.
+	guilds *guildsTracker
+	// See WithGuildsReadyTimeout for more information.
+	guildsReadyTimeout time.Duration
+	// See WithConnectWaitForGuilds for more information.
+	connectWaitForGuilds bool
 
 	// Sequence number of the last Dispatch event
 	// we received from the Gateway.
@@ -107,6 +123,13 @@ type Client struct {
 	// Gateway connection.
 	stop chan struct{}
 
+	// done is closed when the connection to the Gateway
+	// is terminated for good. doneErr holds the error
+	// that caused it, if any. See Done and Err.
+	doneMu  sync.Mutex
+	done    chan struct{}
+	doneErr error
+
 	// Shared context used for sending and receiving websocket
 	// payloads. Will be canceled when the client disconnects
 	// or an error occurs.
@@ -117,6 +140,15 @@ type Client struct {
 	handlersMu sync.RWMutex
 	handlers   map[string]handler
 
+	// See WithDispatchMode for more information.
+	dispatchMode    DispatchMode
+	dispatchWorkers int
+	// See WithHandlerPanicHook for more information.
+	onHandlerPanic HandlerPanicFunc
+	// dispatcher calls registered handlers according
+	// to the dispatch mode.
+	dispatcher *dispatcher
+
 	// Exponential strategy used when trying to reconnect to
 	// the Gateway after an error.
 	backoff *backoff.Exponential
@@ -126,6 +158,15 @@ type Client struct {
 	// are received from the Discord Gateway.
 	withStateTracking bool
 	State             *State
+	// See WithStateStore for more information.
+	stateStore StateStore
+	// See WithStateCache, WithMemberCachePolicy
+	// and WithStateGuilds for more information.
+	cachePolicy cachePolicy
+	// See WithMessageCache for more information.
+	messageCachePerChannel int
+	messageCacheMax        int
+	messageCacheTTL        time.Duration
 
 	// voice connections that were established by
 	// this client.
@@ -153,10 +194,17 @@ func NewClient(token string, opts ...ClientOption) (*Client, error) {
 		guildSubscriptions: true,
 		intents:            discord.GatewayIntentUnprivileged,
 		handlers:           make(map[string]handler),
+		done:               make(chan struct{}),
+		membersRequests:    make(map[string]*membersRequest),
+		membersRequestsSeq: atomic.NewInt64(0),
+		guilds:             &guildsTracker{},
+		guildsReadyTimeout: defaultGuildsReadyTimeout,
 		backoff:            defaultBackoff,
 		withStateTracking:  true,
+		cachePolicy:        defaultCachePolicy(),
 		voiceConnections:   make(map[string]*voice.Connection),
 		logger:             log.NewStd(os.Stderr, log.LevelInfo),
+		sessionID:          atomic.NewString(""),
 		sequence:           atomic.NewInt64(0),
 		lastHeartbeatSent:  atomic.NewInt64(0),
 		lastHeartbeatAck:   atomic.NewInt64(0),
@@ -177,8 +225,16 @@ func NewClient(token string, opts ...ClientOption) (*Client, error) {
 		c.logger,
 	)
 
+	c.dispatcher = newDispatcher(c.dispatchMode, c.dispatchWorkers, c.onHandlerPanic, c.logger)
+
 	if c.withStateTracking {
-		c.State = newState()
+		if c.stateStore == nil {
+			c.stateStore = newMemoryStore()
+		}
+		c.State = newState(c.stateStore, c.cachePolicy, c.logger)
+		if c.messageCachePerChannel > 0 {
+			c.State.messages = newMessageCache(c.messageCachePerChannel, c.messageCacheMax, c.messageCacheTTL)
+		}
 	}
 
 	return c, nil
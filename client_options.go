@@ -79,6 +79,84 @@ func WithStateTracking(y bool) ClientOption {
 	}
 }
 
+// WithStateStore sets the storage backend used by the State. It can be used to keep
+// the State out of the Go heap, for example on disk with the diskstore package.
+// A store must not be shared between multiple Clients: each State keeps its own
+// indexes and accounting of the entities it stores.
+// It has no effect if state tracking is disabled.
+// Defaults to an in-memory store.
+func WithStateStore(store StateStore) ClientOption {
+	return func(c *Client) {
+		c.stateStore = store
+	}
+}
+
+// WithStateCache selects which kind of entities are cached by the State.
+// Guilds are always cached, other entities can be selected with flags.
+// For example, to only cache guilds, channels and roles:
+//
+//	harmony.WithStateCache(harmony.CacheChannels | harmony.CacheRoles)
+//
+// Defaults to CacheAll.
+func WithStateCache(flags CacheFlag) ClientOption {
+	return func(c *Client) {
+		c.cachePolicy.flags = flags
+	}
+}
+
+// WithMemberCachePolicy selects which guild members are cached by the State, when
+// members are cached. activeTTL is how long members are kept after sending a message
+// when using the MemberCacheActive policy, it is ignored otherwise.
+// Defaults to MemberCacheAll.
+func WithMemberCachePolicy(policy MemberCachePolicy, activeTTL time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cachePolicy.members = policy
+		if activeTTL > 0 {
+			c.cachePolicy.activeTTL = activeTTL
+		}
+	}
+}
+
+// WithStateGuilds restricts the State to the given guilds. Entities that
+// belong to other guilds are not cached.
+// Defaults to caching all guilds.
+func WithStateGuilds(ids ...string) ClientOption {
+	return func(c *Client) {
+		c.cachePolicy.guilds = make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			c.cachePolicy.guilds[id] = struct{}{}
+		}
+	}
+}
+
+// WithStateMemoryBudget sets an approximate memory budget for the State, in bytes.
+// When it is exceeded, the least recently used guild members and presences, meaning
+// the ones that were neither updated nor looked up for the longest time, are
+// evicted until the State fits in it again. The current user is never evicted and
+// cached messages do not count towards this budget, see WithMessageCache to bound
+// them. See State.Stats for the memory the State currently uses.
+// Defaults to no budget.
+func WithStateMemoryBudget(bytes int64) ClientOption {
+	return func(c *Client) {
+		c.cachePolicy.memoryBudget = bytes
+	}
+}
+
+// WithMessageCache enables caching messages in the State, so handlers of message
+// update and delete events can know what those messages looked like before.
+// At most perChannel messages are kept for each channel and at most max messages
+// overall, the oldest messages being evicted first. Messages are also evicted once
+// they have been cached for longer than ttl. A max or ttl of 0 means no limit.
+// It has no effect if state tracking is disabled.
+// Defaults to disabled.
+func WithMessageCache(perChannel, max int, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.messageCachePerChannel = perChannel
+		c.messageCacheMax = max
+		c.messageCacheTTL = ttl
+	}
+}
+
 // WithLargeThreshold allows you to set the large threshold when connecting to the Gateway.
 // This threshold will dictate the number of offline guild members are returned with a guild.
 // See: https://discord.com/developers/docs/topics/gateway#request-guild-members for more details.
@@ -113,3 +191,46 @@ func WithLogger(l log.Logger) ClientOption {
 		c.logger = l
 	}
 }
+
+// WithDispatchMode allows you to customize how event handlers are called.
+// workers is the number of workers to start when using DispatchModeGuildPool
+// or DispatchModeChannelPool and is ignored otherwise. If set to 0 or less,
+// the number of CPUs available is used.
+// See DispatchMode for more information on the available modes.
+// Defaults to DispatchModeAsync.
+func WithDispatchMode(mode DispatchMode, workers int) ClientOption {
+	return func(c *Client) {
+		c.dispatchMode = mode
+		c.dispatchWorkers = workers
+	}
+}
+
+// WithHandlerPanicHook sets a function that will be called when an event handler
+// panics. Panics are always recovered, whether a hook is set or not.
+// Defaults to logging the panic and its stack trace as an error.
+func WithHandlerPanicHook(f HandlerPanicFunc) ClientOption {
+	return func(c *Client) {
+		c.onHandlerPanic = f
+	}
+}
+
+// WithGuildsReadyTimeout sets the maximum time to wait for guilds announced in the
+// Ready event to be received before firing the GuildsReady event anyway.
+// See OnGuildsReady for more information.
+// Defaults to 30s.
+func WithGuildsReadyTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.guildsReadyTimeout = timeout
+	}
+}
+
+// WithConnectWaitForGuilds allows to set whether Connect should block until all
+// guilds announced in the Ready event have been received (or until the timeout set
+// with WithGuildsReadyTimeout expired) when starting a new session. This ensures the
+// State is fully populated when Connect returns.
+// Defaults to false.
+func WithConnectWaitForGuilds(y bool) ClientOption {
+	return func(c *Client) {
+		c.connectWaitForGuilds = y
+	}
+}
@@ -28,6 +28,8 @@ func (d *httpDebugger) index(w http.ResponseWriter, _ *http.Request) {
 		DMsCount               int `json:"dms_count"`
 		GroupsCount            int `json:"groups_count"`
 		UnavailableGuildsCount int `json:"unavailable_guilds_count"`
+		// Detailed counts, memory usage and evictions.
+		Stats *harmony.StateStats `json:"stats"`
 	}{
 		UsersCount:             len(d.state.Users()),
 		GuildsCount:            len(d.state.Guilds()),
@@ -36,6 +38,7 @@ func (d *httpDebugger) index(w http.ResponseWriter, _ *http.Request) {
 		DMsCount:               len(d.state.DMs()),
 		GroupsCount:            len(d.state.GroupDMs()),
 		UnavailableGuildsCount: len(d.state.UnavailableGuilds()),
+		Stats:                  d.state.Stats(),
 	}
 
 	enc := json.NewEncoder(w)
@@ -46,25 +49,32 @@ func (d *httpDebugger) index(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+// all writes a snapshot of the whole state. It has the same format as
+// snapshots saved with Client.SaveStateSnapshot, except it is not gzipped,
+// with DMs and group DMs listed on their own as well.
 func (d *httpDebugger) all(w http.ResponseWriter, _ *http.Request) {
+	snap, err := d.state.Snapshot()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	state := struct {
-		CurrentUser       *discord.User                        `json:"current_user"`
-		Users             map[string]*discord.User             `json:"users"`
-		Guilds            map[string]*discord.Guild            `json:"guilds"`
-		Presences         map[string]*discord.Presence         `json:"presences"`
-		Channels          map[string]*discord.Channel          `json:"channels"`
-		DMs               map[string]*discord.Channel          `json:"dms"`
-		Groups            map[string]*discord.Channel          `json:"groups"`
-		UnavailableGuilds map[string]*discord.UnavailableGuild `json:"unavailable_guilds"`
+		*harmony.StateSnapshot
+		DMs    map[string]*discord.Channel `json:"dms"`
+		Groups map[string]*discord.Channel `json:"groups"`
 	}{
-		CurrentUser:       d.state.Me(),
-		Users:             d.state.Users(),
-		Guilds:            d.state.Guilds(),
-		Presences:         d.state.Presences(),
-		Channels:          d.state.Channels(),
-		DMs:               d.state.DMs(),
-		Groups:            d.state.GroupDMs(),
-		UnavailableGuilds: d.state.UnavailableGuilds(),
+		StateSnapshot: snap,
+		DMs:           make(map[string]*discord.Channel),
+		Groups:        make(map[string]*discord.Channel),
+	}
+	for id, ch := range snap.Channels {
+		switch ch.Type {
+		case discord.ChannelTypeDM:
+			state.DMs[id] = ch
+		case discord.ChannelTypeGroupDM:
+			state.Groups[id] = ch
+		}
 	}
 
 	enc := json.NewEncoder(w)
@@ -46,11 +46,20 @@ const (
 	changeKeyMaxAge    changeKey = "max_age"
 	changeKeyTemporary changeKey = "temporary"
 
-	changeKeyDeaf       changeKey = "deaf"
-	changeKeyMute       changeKey = "mute"
-	changeKeyNick       changeKey = "nick"
-	changeKeyAvatarHash changeKey = "avatar_hash"
+	changeKeyDeaf                       changeKey = "deaf"
+	changeKeyMute                       changeKey = "mute"
+	changeKeyNick                       changeKey = "nick"
+	changeKeyAvatarHash                 changeKey = "avatar_hash"
+	changeKeyCommunicationDisabledUntil changeKey = "communication_disabled_until"
 
 	changeKeyID   changeKey = "id"
 	changeKeyType changeKey = "type"
+
+	changeKeyEventType       changeKey = "event_type"
+	changeKeyTriggerType     changeKey = "trigger_type"
+	changeKeyTriggerMetadata changeKey = "trigger_metadata"
+	changeKeyActions         changeKey = "actions"
+	changeKeyEnabled         changeKey = "enabled"
+	changeKeyExemptRoles     changeKey = "exempt_roles"
+	changeKeyExemptChannels  changeKey = "exempt_channels"
 )
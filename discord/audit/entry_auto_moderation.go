@@ -0,0 +1,212 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func autoModerationRuleCreateFromEntry(e *rawEntry) (*AutoModerationRuleCreate, error) {
+	ruleCreate := &AutoModerationRuleCreate{
+		BaseEntry: baseEntryFromRaw(e),
+	}
+
+	var err error
+	for _, ch := range e.Changes {
+		switch changeKey(ch.Key) {
+		case changeKeyName:
+			ruleCreate.Name, err = stringValue(ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyName, err)
+			}
+		case changeKeyEventType:
+			ruleCreate.EventType, err = intValue(ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEventType, err)
+			}
+		case changeKeyTriggerType:
+			ruleCreate.TriggerType, err = intValue(ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyTriggerType, err)
+			}
+		case changeKeyTriggerMetadata:
+			if err = jsonValue(ch.New, &ruleCreate.TriggerMetadata); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyTriggerMetadata, err)
+			}
+		case changeKeyActions:
+			if err = jsonValue(ch.New, &ruleCreate.Actions); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyActions, err)
+			}
+		case changeKeyEnabled:
+			ruleCreate.Enabled, err = boolValue(ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEnabled, err)
+			}
+		case changeKeyExemptRoles:
+			if err = jsonValue(ch.New, &ruleCreate.ExemptRoles); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptRoles, err)
+			}
+		case changeKeyExemptChannels:
+			if err = jsonValue(ch.New, &ruleCreate.ExemptChannels); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptChannels, err)
+			}
+		}
+	}
+
+	return ruleCreate, nil
+}
+
+func autoModerationRuleUpdateFromEntry(e *rawEntry) (*AutoModerationRuleUpdate, error) {
+	ruleUpdate := &AutoModerationRuleUpdate{
+		BaseEntry: baseEntryFromRaw(e),
+	}
+
+	for _, ch := range e.Changes {
+		switch changeKey(ch.Key) {
+		case changeKeyName:
+			oldValue, newValue, err := stringValues(ch.Old, ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyName, err)
+			}
+			ruleUpdate.Name = &StringValues{Old: oldValue, New: newValue}
+		case changeKeyEventType:
+			oldValue, newValue, err := intValues(ch.Old, ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEventType, err)
+			}
+			ruleUpdate.EventType = &IntValues{Old: oldValue, New: newValue}
+		case changeKeyTriggerMetadata:
+			var values AutoModerationTriggerMetadataValues
+			if err := jsonValues(ch.Old, ch.New, &values.Old, &values.New); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyTriggerMetadata, err)
+			}
+			ruleUpdate.TriggerMetadata = &values
+		case changeKeyActions:
+			var values AutoModerationActionsValues
+			if err := jsonValues(ch.Old, ch.New, &values.Old, &values.New); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyActions, err)
+			}
+			ruleUpdate.Actions = &values
+		case changeKeyEnabled:
+			oldValue, newValue, err := boolValues(ch.Old, ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEnabled, err)
+			}
+			ruleUpdate.Enabled = &BoolValues{Old: oldValue, New: newValue}
+		case changeKeyExemptRoles:
+			var values StringsValues
+			if err := jsonValues(ch.Old, ch.New, &values.Old, &values.New); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptRoles, err)
+			}
+			ruleUpdate.ExemptRoles = &values
+		case changeKeyExemptChannels:
+			var values StringsValues
+			if err := jsonValues(ch.Old, ch.New, &values.Old, &values.New); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptChannels, err)
+			}
+			ruleUpdate.ExemptChannels = &values
+		}
+	}
+
+	return ruleUpdate, nil
+}
+
+func autoModerationRuleDeleteFromEntry(e *rawEntry) (*AutoModerationRuleDelete, error) {
+	ruleDelete := &AutoModerationRuleDelete{
+		BaseEntry: baseEntryFromRaw(e),
+	}
+
+	var err error
+	for _, ch := range e.Changes {
+		switch changeKey(ch.Key) {
+		case changeKeyName:
+			ruleDelete.Name, err = stringValue(ch.Old)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyName, err)
+			}
+		case changeKeyEventType:
+			ruleDelete.EventType, err = intValue(ch.Old)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEventType, err)
+			}
+		case changeKeyTriggerType:
+			ruleDelete.TriggerType, err = intValue(ch.Old)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyTriggerType, err)
+			}
+		case changeKeyTriggerMetadata:
+			if err = jsonValue(ch.Old, &ruleDelete.TriggerMetadata); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyTriggerMetadata, err)
+			}
+		case changeKeyActions:
+			if err = jsonValue(ch.Old, &ruleDelete.Actions); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyActions, err)
+			}
+		case changeKeyEnabled:
+			ruleDelete.Enabled, err = boolValue(ch.Old)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyEnabled, err)
+			}
+		case changeKeyExemptRoles:
+			if err = jsonValue(ch.Old, &ruleDelete.ExemptRoles); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptRoles, err)
+			}
+		case changeKeyExemptChannels:
+			if err = jsonValue(ch.Old, &ruleDelete.ExemptChannels); err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyExemptChannels, err)
+			}
+		}
+	}
+
+	return ruleDelete, nil
+}
+
+// autoModerationRuleTriggerType returns the trigger type of the rule that
+// caused an auto moderation action entry, which is sent as a string.
+func autoModerationRuleTriggerType(e *rawEntry) (int, error) {
+	if e.Options.AutoModerationRuleTriggerType == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(e.Options.AutoModerationRuleTriggerType)
+}
+
+func autoModerationBlockMessageFromEntry(e *rawEntry) (*AutoModerationBlockMessage, error) {
+	triggerType, err := autoModerationRuleTriggerType(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutoModerationBlockMessage{
+		BaseEntry:       baseEntryFromRaw(e),
+		ChannelID:       e.Options.ChannelID,
+		RuleName:        e.Options.AutoModerationRuleName,
+		RuleTriggerType: triggerType,
+	}, nil
+}
+
+func autoModerationFlagToChannelFromEntry(e *rawEntry) (*AutoModerationFlagToChannel, error) {
+	triggerType, err := autoModerationRuleTriggerType(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutoModerationFlagToChannel{
+		BaseEntry:       baseEntryFromRaw(e),
+		ChannelID:       e.Options.ChannelID,
+		RuleName:        e.Options.AutoModerationRuleName,
+		RuleTriggerType: triggerType,
+	}, nil
+}
+
+func autoModerationUserCommunicationDisabledFromEntry(e *rawEntry) (*AutoModerationUserCommunicationDisabled, error) {
+	triggerType, err := autoModerationRuleTriggerType(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutoModerationUserCommunicationDisabled{
+		BaseEntry:       baseEntryFromRaw(e),
+		ChannelID:       e.Options.ChannelID,
+		RuleName:        e.Options.AutoModerationRuleName,
+		RuleTriggerType: triggerType,
+	}, nil
+}
@@ -179,7 +179,7 @@ func channelOverwriteCreateFromEntry(e *rawEntry) (*ChannelOverwriteCreate, erro
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (as string): %w", changeKeyAllow, err)
 			}
-			overwriteCreate.Allow, err = strconv.Atoi(allowStr)
+			overwriteCreate.Allow, err = strconv.ParseInt(allowStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyAllow, err)
 			}
@@ -190,7 +190,7 @@ func channelOverwriteCreateFromEntry(e *rawEntry) (*ChannelOverwriteCreate, erro
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (as string): %w", changeKeyAllow, err)
 			}
-			overwriteCreate.Deny, err = strconv.Atoi(denyStr)
+			overwriteCreate.Deny, err = strconv.ParseInt(denyStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyDeny, err)
 			}
@@ -221,16 +221,16 @@ func channelOverwriteUpdateFromEntry(e *rawEntry) (*ChannelOverwriteUpdate, erro
 				return nil, fmt.Errorf("change key %q: %w", changeKeyAllow, err)
 			}
 
-			var oldValue, newValue int
-			oldValue, err = strconv.Atoi(oldValueStr)
+			var oldValue, newValue int64
+			oldValue, err = strconv.ParseInt(oldValueStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (old value): %w", changeKeyAllow, err)
 			}
-			newValue, err = strconv.Atoi(newValueStr)
+			newValue, err = strconv.ParseInt(newValueStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (new value): %w", changeKeyAllow, err)
 			}
-			overwriteUpdate.Allow = &IntValues{Old: oldValue, New: newValue}
+			overwriteUpdate.Allow = &Int64Values{Old: oldValue, New: newValue}
 
 		case changeKeyDeny:
 			oldValueStr, newValueStr, err := stringValues(ch.Old, ch.New)
@@ -238,16 +238,16 @@ func channelOverwriteUpdateFromEntry(e *rawEntry) (*ChannelOverwriteUpdate, erro
 				return nil, fmt.Errorf("change key %q: %w", changeKeyDeny, err)
 			}
 
-			var oldValue, newValue int
-			oldValue, err = strconv.Atoi(oldValueStr)
+			var oldValue, newValue int64
+			oldValue, err = strconv.ParseInt(oldValueStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (old value): %w", changeKeyDeny, err)
 			}
-			newValue, err = strconv.Atoi(newValueStr)
+			newValue, err = strconv.ParseInt(newValueStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (new value): %w", changeKeyDeny, err)
 			}
-			overwriteUpdate.Deny = &IntValues{Old: oldValue, New: newValue}
+			overwriteUpdate.Deny = &Int64Values{Old: oldValue, New: newValue}
 		}
 	}
 
@@ -281,7 +281,7 @@ func channelOverwriteDeleteFromEntry(e *rawEntry) (*ChannelOverwriteDelete, erro
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (as string): %w", changeKeyAllow, err)
 			}
-			overwriteDelete.Allow, err = strconv.Atoi(allowStr)
+			overwriteDelete.Allow, err = strconv.ParseInt(allowStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyAllow, err)
 			}
@@ -292,7 +292,7 @@ func channelOverwriteDeleteFromEntry(e *rawEntry) (*ChannelOverwriteDelete, erro
 			if err != nil {
 				return nil, fmt.Errorf("change key %q (as string): %w", changeKeyDeny, err)
 			}
-			overwriteDelete.Deny, err = strconv.Atoi(denyStr)
+			overwriteDelete.Deny, err = strconv.ParseInt(denyStr, 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyDeny, err)
 			}
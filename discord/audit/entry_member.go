@@ -75,6 +75,13 @@ func memberUpdateFromEntry(e *rawEntry) (*MemberUpdate, error) {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyMute, err)
 			}
 			memberUpdate.Mute = &BoolValues{Old: oldValue, New: newValue}
+
+		case changeKeyCommunicationDisabledUntil:
+			oldValue, newValue, err := timeValues(ch.Old, ch.New)
+			if err != nil {
+				return nil, fmt.Errorf("change key %q: %w", changeKeyCommunicationDisabledUntil, err)
+			}
+			memberUpdate.CommunicationDisabledUntil = &TimeValues{Old: oldValue, New: newValue}
 		}
 	}
 
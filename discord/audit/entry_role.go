@@ -19,7 +19,7 @@ func roleCreateFromEntry(e *rawEntry) (*RoleCreate, error) {
 			}
 
 		case changeKeyPermissions:
-			roleCreate.Permissions, err = intValue(ch.New)
+			roleCreate.Permissions, err = permissionsValue(ch.New)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyPermissions, err)
 			}
@@ -62,11 +62,11 @@ func roleUpdateFromEntry(e *rawEntry) (*RoleUpdate, error) {
 			roleUpdate.Name = &StringValues{Old: oldValue, New: newValue}
 
 		case changeKeyPermissions:
-			oldValue, newValue, err := intValues(ch.Old, ch.New)
+			oldValue, newValue, err := permissionsValues(ch.Old, ch.New)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyPermissions, err)
 			}
-			roleUpdate.Permissions = &IntValues{Old: oldValue, New: newValue}
+			roleUpdate.Permissions = &Int64Values{Old: oldValue, New: newValue}
 
 		case changeKeyColor:
 			oldValue, newValue, err := intValues(ch.Old, ch.New)
@@ -109,7 +109,7 @@ func roleDeleteFromEntry(e *rawEntry) (*RoleDelete, error) {
 			}
 
 		case changeKeyPermissions:
-			roleDelete.Permissions, err = intValue(ch.Old)
+			roleDelete.Permissions, err = permissionsValue(ch.Old)
 			if err != nil {
 				return nil, fmt.Errorf("change key %q: %w", changeKeyPermissions, err)
 			}
@@ -10,32 +10,38 @@ type EntryType int
 // Possible entry type values, as defined here:
 // https://discord.com/developers/docs/resources/audit-log#audit-log-entry-object-audit-log-events
 const (
-	EntryTypeGuildUpdate            EntryType = 1
-	EntryTypeChannelCreate          EntryType = 10
-	EntryTypeChannelUpdate          EntryType = 11
-	EntryTypeChannelDelete          EntryType = 12
-	EntryTypeChannelOverwriteCreate EntryType = 13
-	EntryTypeChannelOverwriteUpdate EntryType = 14
-	EntryTypeChannelOverwriteDelete EntryType = 15
-	EntryTypeMemberKick             EntryType = 20
-	EntryTypeMemberPrune            EntryType = 21
-	EntryTypeMemberBanAdd           EntryType = 22
-	EntryTypeMemberBanRemove        EntryType = 23
-	EntryTypeMemberUpdate           EntryType = 24
-	EntryTypeMemberRoleUpdate       EntryType = 25
-	EntryTypeRoleCreate             EntryType = 30
-	EntryTypeRoleUpdate             EntryType = 31
-	EntryTypeRoleDelete             EntryType = 32
-	EntryTypeInviteCreate           EntryType = 40
-	EntryTypeInviteUpdate           EntryType = 41
-	EntryTypeInviteDelete           EntryType = 42
-	EntryTypeWebhookCreate          EntryType = 50
-	EntryTypeWebhookUpdate          EntryType = 51
-	EntryTypeWebhookDelete          EntryType = 52
-	EntryTypeEmojiCreate            EntryType = 60
-	EntryTypeEmojiUpdate            EntryType = 61
-	EntryTypeEmojiDelete            EntryType = 62
-	EntryTypeMessageDelete          EntryType = 72
+	EntryTypeGuildUpdate                             EntryType = 1
+	EntryTypeChannelCreate                           EntryType = 10
+	EntryTypeChannelUpdate                           EntryType = 11
+	EntryTypeChannelDelete                           EntryType = 12
+	EntryTypeChannelOverwriteCreate                  EntryType = 13
+	EntryTypeChannelOverwriteUpdate                  EntryType = 14
+	EntryTypeChannelOverwriteDelete                  EntryType = 15
+	EntryTypeMemberKick                              EntryType = 20
+	EntryTypeMemberPrune                             EntryType = 21
+	EntryTypeMemberBanAdd                            EntryType = 22
+	EntryTypeMemberBanRemove                         EntryType = 23
+	EntryTypeMemberUpdate                            EntryType = 24
+	EntryTypeMemberRoleUpdate                        EntryType = 25
+	EntryTypeRoleCreate                              EntryType = 30
+	EntryTypeRoleUpdate                              EntryType = 31
+	EntryTypeRoleDelete                              EntryType = 32
+	EntryTypeInviteCreate                            EntryType = 40
+	EntryTypeInviteUpdate                            EntryType = 41
+	EntryTypeInviteDelete                            EntryType = 42
+	EntryTypeWebhookCreate                           EntryType = 50
+	EntryTypeWebhookUpdate                           EntryType = 51
+	EntryTypeWebhookDelete                           EntryType = 52
+	EntryTypeEmojiCreate                             EntryType = 60
+	EntryTypeEmojiUpdate                             EntryType = 61
+	EntryTypeEmojiDelete                             EntryType = 62
+	EntryTypeMessageDelete                           EntryType = 72
+	EntryTypeAutoModerationRuleCreate                EntryType = 140
+	EntryTypeAutoModerationRuleUpdate                EntryType = 141
+	EntryTypeAutoModerationRuleDelete                EntryType = 142
+	EntryTypeAutoModerationBlockMessage              EntryType = 143
+	EntryTypeAutoModerationFlagToChannel             EntryType = 144
+	EntryTypeAutoModerationUserCommunicationDisabled EntryType = 145
 )
 
 // GuildUpdate is the audit log entry that describes how a guild was updated.
@@ -121,8 +127,8 @@ type ChannelOverwriteCreate struct {
 
 	Type  int
 	ID    string
-	Allow int
-	Deny  int
+	Allow int64
+	Deny  int64
 
 	RoleName string // Name of the role if Type is "role".
 }
@@ -137,8 +143,8 @@ func (ChannelOverwriteCreate) EntryType() EntryType { return EntryTypeChannelOve
 type ChannelOverwriteUpdate struct {
 	BaseEntry
 
-	Allow *IntValues
-	Deny  *IntValues
+	Allow *Int64Values
+	Deny  *Int64Values
 
 	Type     int
 	ID       string
@@ -155,8 +161,8 @@ type ChannelOverwriteDelete struct {
 
 	Type  int
 	ID    string
-	Allow int
-	Deny  int
+	Allow int64
+	Deny  int64
 
 	RoleName string // Name of the role if Type is "role".
 }
@@ -206,9 +212,10 @@ func (MemberBanRemove) EntryType() EntryType { return EntryTypeMemberBanRemove }
 type MemberUpdate struct {
 	BaseEntry
 
-	Nick *StringValues
-	Deaf *BoolValues
-	Mute *BoolValues
+	Nick                       *StringValues
+	Deaf                       *BoolValues
+	Mute                       *BoolValues
+	CommunicationDisabledUntil *TimeValues
 }
 
 // EntryType implements the LogEntry interface.
@@ -232,7 +239,7 @@ type RoleCreate struct {
 	BaseEntry
 
 	Name        string
-	Permissions int
+	Permissions int64
 	Color       int
 	Mentionable bool
 	Hoist       bool
@@ -249,7 +256,7 @@ type RoleUpdate struct {
 	BaseEntry
 
 	Name        *StringValues
-	Permissions *IntValues
+	Permissions *Int64Values
 	Color       *IntValues
 	Mentionable *BoolValues
 	Hoist       *BoolValues
@@ -264,7 +271,7 @@ type RoleDelete struct {
 	BaseEntry
 
 	Name        string
-	Permissions int
+	Permissions int64
 	Color       int
 	Mentionable bool
 	Hoist       bool
@@ -412,3 +419,99 @@ type MessageDelete struct {
 
 // EntryType implements the LogEntry interface.
 func (MessageDelete) EntryType() EntryType { return EntryTypeMessageDelete }
+
+// AutoModerationRuleCreate is the audit log entry that describes an auto moderation
+// rule creation. It contains the settings the rule was created with.
+type AutoModerationRuleCreate struct {
+	BaseEntry
+
+	Name            string
+	EventType       int
+	TriggerType     int
+	TriggerMetadata *discord.AutoModerationTriggerMetadata
+	Actions         []discord.AutoModerationAction
+	Enabled         bool
+	ExemptRoles     []string
+	ExemptChannels  []string
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationRuleCreate) EntryType() EntryType { return EntryTypeAutoModerationRuleCreate }
+
+// AutoModerationRuleUpdate is the audit log entry that describes how an auto moderation
+// rule was updated. It contains a list of settings that can be updated on a rule.
+// Settings that are not nil are those which were modified. They contain both
+// their old value as well as the new one.
+type AutoModerationRuleUpdate struct {
+	BaseEntry
+
+	Name            *StringValues
+	EventType       *IntValues
+	TriggerMetadata *AutoModerationTriggerMetadataValues
+	Actions         *AutoModerationActionsValues
+	Enabled         *BoolValues
+	ExemptRoles     *StringsValues
+	ExemptChannels  *StringsValues
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationRuleUpdate) EntryType() EntryType { return EntryTypeAutoModerationRuleUpdate }
+
+// AutoModerationRuleDelete is the audit log entry that describes an auto moderation
+// rule deletion. It contains settings this rule had before being deleted.
+type AutoModerationRuleDelete struct {
+	BaseEntry
+
+	Name            string
+	EventType       int
+	TriggerType     int
+	TriggerMetadata *discord.AutoModerationTriggerMetadata
+	Actions         []discord.AutoModerationAction
+	Enabled         bool
+	ExemptRoles     []string
+	ExemptChannels  []string
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationRuleDelete) EntryType() EntryType { return EntryTypeAutoModerationRuleDelete }
+
+// AutoModerationBlockMessage is the audit log entry that describes a message
+// blocked by auto moderation. TargetID is the ID of the user who sent it.
+type AutoModerationBlockMessage struct {
+	BaseEntry
+
+	ChannelID       string
+	RuleName        string
+	RuleTriggerType int
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationBlockMessage) EntryType() EntryType { return EntryTypeAutoModerationBlockMessage }
+
+// AutoModerationFlagToChannel is the audit log entry that describes a message
+// flagged by auto moderation. TargetID is the ID of the user who sent it.
+type AutoModerationFlagToChannel struct {
+	BaseEntry
+
+	ChannelID       string
+	RuleName        string
+	RuleTriggerType int
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationFlagToChannel) EntryType() EntryType { return EntryTypeAutoModerationFlagToChannel }
+
+// AutoModerationUserCommunicationDisabled is the audit log entry that describes a member
+// timed out by auto moderation. TargetID is the ID of this member.
+type AutoModerationUserCommunicationDisabled struct {
+	BaseEntry
+
+	ChannelID       string
+	RuleName        string
+	RuleTriggerType int
+}
+
+// EntryType implements the LogEntry interface.
+func (AutoModerationUserCommunicationDisabled) EntryType() EntryType {
+	return EntryTypeAutoModerationUserCommunicationDisabled
+}
@@ -31,6 +31,11 @@ type rawEntry struct {
 		ChannelID string `json:"channel_id"` // ID of the channel in which the messages were deleted.
 		Count     string `json:"count"`      // Number of deleted messages.
 
+		// AUTO_MODERATION_BLOCK_MESSAGE, AUTO_MODERATION_FLAG_TO_CHANNEL and
+		// AUTO_MODERATION_USER_COMMUNICATION_DISABLED actions also set ChannelID.
+		AutoModerationRuleName        string `json:"auto_moderation_rule_name"`         // Name of the rule that was triggered.
+		AutoModerationRuleTriggerType string `json:"auto_moderation_rule_trigger_type"` // Trigger type of the rule that was triggered.
+
 		// CHANNEL_OVERWRITE_* actions.
 		ID       string `json:"id"`        // ID of the overwritten entity.
 		Type     string `json:"type"`      // Type of the overwritten entity ("member" or "role").
@@ -223,6 +228,42 @@ func ParseRaw(raw json.RawMessage) (*Log, error) {
 			if err != nil {
 				return nil, fmt.Errorf("message delete: %w", err)
 			}
+
+		case EntryTypeAutoModerationRuleCreate:
+			entry, err = autoModerationRuleCreateFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation rule create: %w", err)
+			}
+
+		case EntryTypeAutoModerationRuleUpdate:
+			entry, err = autoModerationRuleUpdateFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation rule update: %w", err)
+			}
+
+		case EntryTypeAutoModerationRuleDelete:
+			entry, err = autoModerationRuleDeleteFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation rule delete: %w", err)
+			}
+
+		case EntryTypeAutoModerationBlockMessage:
+			entry, err = autoModerationBlockMessageFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation block message: %w", err)
+			}
+
+		case EntryTypeAutoModerationFlagToChannel:
+			entry, err = autoModerationFlagToChannelFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation flag to channel: %w", err)
+			}
+
+		case EntryTypeAutoModerationUserCommunicationDisabled:
+			entry, err = autoModerationUserCommunicationDisabledFromEntry(&e)
+			if err != nil {
+				return nil, fmt.Errorf("auto moderation user communication disabled: %w", err)
+			}
 		}
 
 		res.Entries = append(res.Entries, entry)
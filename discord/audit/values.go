@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/skwair/harmony/discord"
 )
@@ -18,11 +19,36 @@ type IntValues struct {
 	Old, New int
 }
 
+// Int64Values holds a pair of 64-bit integer values, such as permissions.
+type Int64Values struct {
+	Old, New int64
+}
+
 // BoolValues holds a pair of boolean values.
 type BoolValues struct {
 	Old, New bool
 }
 
+// StringsValues holds a pair of string slices.
+type StringsValues struct {
+	Old, New []string
+}
+
+// AutoModerationTriggerMetadataValues holds a pair of auto moderation trigger metadata.
+type AutoModerationTriggerMetadataValues struct {
+	Old, New *discord.AutoModerationTriggerMetadata
+}
+
+// AutoModerationActionsValues holds a pair of auto moderation action lists.
+type AutoModerationActionsValues struct {
+	Old, New []discord.AutoModerationAction
+}
+
+// TimeValues holds a pair of time values.
+type TimeValues struct {
+	Old, New discord.Time
+}
+
 func stringValues(oldValue, newValue json.RawMessage) (old string, new string, err error) {
 	if len(oldValue) != 0 {
 		if err = json.Unmarshal(oldValue, &old); err != nil {
@@ -79,6 +105,36 @@ func intValue(val json.RawMessage) (int, error) {
 	return i, nil
 }
 
+func permissionsValues(oldValue, newValue json.RawMessage) (old int64, new int64, err error) {
+	if old, err = permissionsValue(oldValue); err != nil {
+		return 0, 0, fmt.Errorf("old value: %w", err)
+	}
+
+	if new, err = permissionsValue(newValue); err != nil {
+		return 0, 0, fmt.Errorf("new value: %w", err)
+	}
+
+	return old, new, nil
+}
+
+// permissionsValue decodes permissions, which are sent as
+// strings but may be sent as numbers in older entries.
+func permissionsValue(val json.RawMessage) (int64, error) {
+	var p int64
+
+	if len(val) != 0 {
+		var s string
+		if err := json.Unmarshal(val, &s); err == nil {
+			return strconv.ParseInt(s, 10, 64)
+		}
+		if err := json.Unmarshal(val, &p); err != nil {
+			return 0, err
+		}
+	}
+
+	return p, nil
+}
+
 func boolValues(oldValue, newValue json.RawMessage) (old bool, new bool, err error) {
 	if len(oldValue) != 0 {
 		if err = json.Unmarshal(oldValue, &old); err != nil {
@@ -107,6 +163,43 @@ func boolValue(val json.RawMessage) (bool, error) {
 	return b, nil
 }
 
+func timeValues(oldValue, newValue json.RawMessage) (old discord.Time, new discord.Time, err error) {
+	if len(oldValue) != 0 {
+		if err = json.Unmarshal(oldValue, &old); err != nil {
+			return discord.Time{}, discord.Time{}, fmt.Errorf("old value: %w", err)
+		}
+	}
+
+	if len(newValue) != 0 {
+		if err = json.Unmarshal(newValue, &new); err != nil {
+			return discord.Time{}, discord.Time{}, fmt.Errorf("new value: %w", err)
+		}
+	}
+
+	return old, new, nil
+}
+
+// jsonValues decodes the old and new values of a change into old and new.
+func jsonValues(oldValue, newValue json.RawMessage, old, new interface{}) error {
+	if err := jsonValue(oldValue, old); err != nil {
+		return fmt.Errorf("old value: %w", err)
+	}
+
+	if err := jsonValue(newValue, new); err != nil {
+		return fmt.Errorf("new value: %w", err)
+	}
+
+	return nil
+}
+
+// jsonValue decodes the value of a change into v, if it is set.
+func jsonValue(val json.RawMessage, v interface{}) error {
+	if len(val) != 0 {
+		return json.Unmarshal(val, v)
+	}
+	return nil
+}
+
 func permissionOverwritesValue(val json.RawMessage) ([]discord.PermissionOverwrite, error) {
 	var perm []discord.PermissionOverwrite
 
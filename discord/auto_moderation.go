@@ -0,0 +1,109 @@
+package discord
+
+// AutoModerationEventType is the type of event that triggers the
+// evaluation of an AutoModerationRule.
+type AutoModerationEventType int
+
+const (
+	// AutoModerationEventTypeMessageSend is when a member sends or edits a message in the guild.
+	AutoModerationEventTypeMessageSend AutoModerationEventType = 1
+)
+
+// AutoModerationTriggerType is the type of content that can trigger an AutoModerationRule.
+type AutoModerationTriggerType int
+
+const (
+	// AutoModerationTriggerTypeKeyword checks if content contains words from a user defined list of keywords.
+	AutoModerationTriggerTypeKeyword AutoModerationTriggerType = 1
+	// AutoModerationTriggerTypeSpam checks if content represents generic spam.
+	AutoModerationTriggerTypeSpam AutoModerationTriggerType = 3
+	// AutoModerationTriggerTypeKeywordPreset checks if content contains words from internal pre-defined wordsets.
+	AutoModerationTriggerTypeKeywordPreset AutoModerationTriggerType = 4
+	// AutoModerationTriggerTypeMentionSpam checks if content contains more unique mentions than allowed.
+	AutoModerationTriggerTypeMentionSpam AutoModerationTriggerType = 5
+)
+
+// AutoModerationKeywordPreset is an internal pre-defined wordset
+// used by rules of type AutoModerationTriggerTypeKeywordPreset.
+type AutoModerationKeywordPreset int
+
+const (
+	AutoModerationKeywordPresetProfanity     AutoModerationKeywordPreset = 1
+	AutoModerationKeywordPresetSexualContent AutoModerationKeywordPreset = 2
+	AutoModerationKeywordPresetSlurs         AutoModerationKeywordPreset = 3
+)
+
+// AutoModerationTriggerMetadata is additional data used to determine whether a rule should be
+// triggered. Which fields are relevant depends on the trigger type of the rule.
+type AutoModerationTriggerMetadata struct {
+	// Substrings which will be searched for in content (max 1000),
+	// for AutoModerationTriggerTypeKeyword.
+	KeywordFilter []string `json:"keyword_filter,omitempty"`
+	// Regular expression patterns which will be matched against content (max 10),
+	// for AutoModerationTriggerTypeKeyword.
+	RegexPatterns []string `json:"regex_patterns,omitempty"`
+	// Internal pre-defined wordsets which will be searched for in content,
+	// for AutoModerationTriggerTypeKeywordPreset.
+	Presets []AutoModerationKeywordPreset `json:"presets,omitempty"`
+	// Substrings which should not trigger the rule, for
+	// AutoModerationTriggerTypeKeyword and AutoModerationTriggerTypeKeywordPreset.
+	AllowList []string `json:"allow_list,omitempty"`
+	// Total number of unique role and user mentions allowed per message (max 50),
+	// for AutoModerationTriggerTypeMentionSpam.
+	MentionTotalLimit int `json:"mention_total_limit,omitempty"`
+	// Whether to automatically detect mention raids, for AutoModerationTriggerTypeMentionSpam.
+	MentionRaidProtectionEnabled bool `json:"mention_raid_protection_enabled,omitempty"`
+}
+
+// AutoModerationActionType is the type of an AutoModerationAction.
+type AutoModerationActionType int
+
+const (
+	// AutoModerationActionTypeBlockMessage blocks a member's message and prevents it from
+	// being posted. A custom explanation can be specified and shown to members whenever
+	// their message is blocked.
+	AutoModerationActionTypeBlockMessage AutoModerationActionType = 1
+	// AutoModerationActionTypeSendAlertMessage logs user content to a specified channel.
+	AutoModerationActionTypeSendAlertMessage AutoModerationActionType = 2
+	// AutoModerationActionTypeTimeout times out a user for a specified duration. Can only be
+	// set up for AutoModerationTriggerTypeKeyword and AutoModerationTriggerTypeMentionSpam rules
+	// and requires the 'MODERATE_MEMBERS' permission.
+	AutoModerationActionTypeTimeout AutoModerationActionType = 3
+)
+
+// AutoModerationAction is an action which will execute whenever a rule is triggered.
+type AutoModerationAction struct {
+	Type     AutoModerationActionType      `json:"type"`
+	Metadata *AutoModerationActionMetadata `json:"metadata,omitempty"`
+}
+
+// AutoModerationActionMetadata is additional data used when an action is executed.
+// Which fields are relevant depends on the type of the action.
+type AutoModerationActionMetadata struct {
+	// Channel to which user content should be logged,
+	// for AutoModerationActionTypeSendAlertMessage.
+	ChannelID string `json:"channel_id,omitempty"`
+	// Timeout duration in seconds (max 2419200, i.e. 4 weeks),
+	// for AutoModerationActionTypeTimeout.
+	DurationSeconds int `json:"duration_seconds,omitempty"`
+	// Additional explanation that will be shown to members whenever their message
+	// is blocked (max 150 characters), for AutoModerationActionTypeBlockMessage.
+	CustomMessage string `json:"custom_message,omitempty"`
+}
+
+// AutoModerationRule is a rule of the auto moderation feature of a guild.
+type AutoModerationRule struct {
+	ID      string `json:"id"`
+	GuildID string `json:"guild_id"`
+	Name    string `json:"name"`
+	// ID of the user who first created this rule.
+	CreatorID       string                        `json:"creator_id"`
+	EventType       AutoModerationEventType       `json:"event_type"`
+	TriggerType     AutoModerationTriggerType     `json:"trigger_type"`
+	TriggerMetadata AutoModerationTriggerMetadata `json:"trigger_metadata"`
+	Actions         []AutoModerationAction        `json:"actions"`
+	Enabled         bool                          `json:"enabled"`
+	// IDs of roles and channels that are not affected by this rule.
+	ExemptRoles    []string `json:"exempt_roles"`
+	ExemptChannels []string `json:"exempt_channels"`
+}
@@ -0,0 +1,92 @@
+package discord
+
+import "github.com/skwair/harmony/optional"
+
+// AutoModerationRuleSettings describes an auto moderation rule creation or update.
+type AutoModerationRuleSettings struct {
+	Name            *optional.String               `json:"name,omitempty"`
+	EventType       *optional.Int                  `json:"event_type,omitempty"`
+	TriggerType     *optional.Int                  `json:"trigger_type,omitempty"` // Can not be modified.
+	TriggerMetadata *AutoModerationTriggerMetadata `json:"trigger_metadata,omitempty"`
+	Actions         []AutoModerationAction         `json:"actions,omitempty"`
+	Enabled         *optional.Bool                 `json:"enabled,omitempty"`
+	ExemptRoles     *optional.StringSlice          `json:"exempt_roles,omitempty"`
+	ExemptChannels  *optional.StringSlice          `json:"exempt_channels,omitempty"`
+}
+
+// AutoModerationRuleSetting is a function that configures an auto moderation rule.
+type AutoModerationRuleSetting func(*AutoModerationRuleSettings)
+
+// NewAutoModerationRuleSettings returns new AutoModerationRuleSettings to create or modify an
+// auto moderation rule. Creating a rule requires at least a name, an event type, a trigger
+// type and an action.
+func NewAutoModerationRuleSettings(opts ...AutoModerationRuleSetting) *AutoModerationRuleSettings {
+	s := &AutoModerationRuleSettings{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithAutoModerationRuleName sets the name of an auto moderation rule.
+func WithAutoModerationRuleName(name string) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.Name = optional.NewString(name)
+	}
+}
+
+// WithAutoModerationRuleEventType sets the event type of an auto moderation rule.
+func WithAutoModerationRuleEventType(t AutoModerationEventType) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.EventType = optional.NewInt(int(t))
+	}
+}
+
+// WithAutoModerationRuleTrigger sets the trigger type and metadata of an auto moderation
+// rule. metadata can be nil for trigger types that do not need any. The trigger type of
+// a rule can not be modified once it is created, only its metadata.
+func WithAutoModerationRuleTrigger(t AutoModerationTriggerType, metadata *AutoModerationTriggerMetadata) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.TriggerType = optional.NewInt(int(t))
+		s.TriggerMetadata = metadata
+	}
+}
+
+// WithAutoModerationRuleTriggerMetadata sets the trigger metadata of an auto moderation rule.
+func WithAutoModerationRuleTriggerMetadata(metadata *AutoModerationTriggerMetadata) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.TriggerMetadata = metadata
+	}
+}
+
+// WithAutoModerationRuleActions sets the actions executed when an auto moderation rule is triggered.
+func WithAutoModerationRuleActions(actions ...AutoModerationAction) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.Actions = actions
+	}
+}
+
+// WithAutoModerationRuleEnabled sets whether an auto moderation rule is enabled.
+func WithAutoModerationRuleEnabled(enabled bool) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.Enabled = optional.NewBool(enabled)
+	}
+}
+
+// WithAutoModerationRuleExemptRoles sets roles that are not affected by an
+// auto moderation rule (max 20).
+func WithAutoModerationRuleExemptRoles(ids ...string) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.ExemptRoles = optional.NewStringSlice(ids)
+	}
+}
+
+// WithAutoModerationRuleExemptChannels sets channels that are not affected by an
+// auto moderation rule (max 50).
+func WithAutoModerationRuleExemptChannels(ids ...string) AutoModerationRuleSetting {
+	return func(s *AutoModerationRuleSettings) {
+		s.ExemptChannels = optional.NewStringSlice(ids)
+	}
+}
@@ -13,6 +13,7 @@ const (
 	ChannelTypeGuildCategory
 	ChannelTypeGuildNews
 	ChannelTypeGuildStore
+	ChannelTypeGuildStageVoice ChannelType = 13
 )
 
 // ChannelUserRateLimit is the set of allowed values for Channel.RateLimitPerUser.
@@ -73,6 +73,18 @@ func (g *Guild) Clone() *Guild {
 		guild.Emojis = append(guild.Emojis, *emoji)
 	}
 
+	for i := 0; i < len(g.Stickers); i++ {
+		sticker := g.Stickers[i].Clone()
+		guild.Stickers = append(guild.Stickers, *sticker)
+	}
+
+	for i := 0; i < len(g.ScheduledEvents); i++ {
+		event := g.ScheduledEvents[i].Clone()
+		guild.ScheduledEvents = append(guild.ScheduledEvents, *event)
+	}
+
+	guild.StageInstances = append(guild.StageInstances, g.StageInstances...)
+
 	for i := 0; i < len(g.Features); i++ {
 		guild.Features = append(guild.Features, g.Features[i])
 	}
@@ -120,6 +132,59 @@ func (r *Role) Clone() *Role {
 	}
 }
 
+// Clone returns a clone of this Sticker.
+func (s *Sticker) Clone() *Sticker {
+	if s == nil {
+		return nil
+	}
+
+	return &Sticker{
+		ID:          s.ID,
+		PackID:      s.PackID,
+		Name:        s.Name,
+		Description: s.Description,
+		Tags:        s.Tags,
+		Type:        s.Type,
+		FormatType:  s.FormatType,
+		Available:   s.Available,
+		GuildID:     s.GuildID,
+		User:        s.User.Clone(),
+		SortValue:   s.SortValue,
+	}
+}
+
+// Clone returns a clone of this ScheduledEvent.
+func (e *ScheduledEvent) Clone() *ScheduledEvent {
+	if e == nil {
+		return nil
+	}
+
+	event := &ScheduledEvent{
+		ID:                 e.ID,
+		GuildID:            e.GuildID,
+		ChannelID:          e.ChannelID,
+		CreatorID:          e.CreatorID,
+		Name:               e.Name,
+		Description:        e.Description,
+		ScheduledStartTime: e.ScheduledStartTime,
+		ScheduledEndTime:   e.ScheduledEndTime,
+		PrivacyLevel:       e.PrivacyLevel,
+		Status:             e.Status,
+		EntityType:         e.EntityType,
+		EntityID:           e.EntityID,
+		Creator:            e.Creator.Clone(),
+		UserCount:          e.UserCount,
+		Image:              e.Image,
+	}
+
+	if e.EntityMetadata != nil {
+		metadata := *e.EntityMetadata
+		event.EntityMetadata = &metadata
+	}
+
+	return event
+}
+
 // Clone returns a clone of this Emoji.
 func (e *Emoji) Clone() *Emoji {
 	if e == nil {
@@ -152,11 +217,12 @@ func (m *GuildMember) Clone() *GuildMember {
 	gm := &GuildMember{
 		User:         m.User.Clone(),
 		Nick:         m.Nick,
-		Roles:        m.Roles,
 		JoinedAt:     m.JoinedAt,
 		PremiumSince: m.PremiumSince,
 		Deaf:         m.Deaf,
 		Mute:         m.Mute,
+
+		CommunicationDisabledUntil: m.CommunicationDisabledUntil,
 	}
 
 	for i := 0; i < len(m.Roles); i++ {
@@ -247,3 +313,96 @@ func (o *PermissionOverwrite) Clone() *PermissionOverwrite {
 		Deny:  o.Deny,
 	}
 }
+
+// Clone returns a clone of this Message.
+func (m *Message) Clone() *Message {
+	if m == nil {
+		return nil
+	}
+
+	msg := &Message{
+		ID:                m.ID,
+		ChannelID:         m.ChannelID,
+		GuildID:           m.GuildID,
+		Author:            *m.Author.Clone(),
+		Member:            *m.Member.Clone(),
+		Content:           m.Content,
+		Timestamp:         m.Timestamp,
+		EditedTimestamp:   m.EditedTimestamp,
+		TTS:               m.TTS,
+		MentionEveryone:   m.MentionEveryone,
+		Nonce:             m.Nonce,
+		Pinned:            m.Pinned,
+		WebhookID:         m.WebhookID,
+		Type:              m.Type,
+		Activity:          m.Activity,
+		Application:       m.Application,
+		MessageReference:  m.MessageReference,
+		Flags:             m.Flags,
+		ReferencedMessage: m.ReferencedMessage.Clone(),
+	}
+
+	for i := 0; i < len(m.Mentions); i++ {
+		mention := m.Mentions[i].Clone()
+		msg.Mentions = append(msg.Mentions, *mention)
+	}
+
+	for i := 0; i < len(m.Embeds); i++ {
+		embed := m.Embeds[i].Clone()
+		msg.Embeds = append(msg.Embeds, *embed)
+	}
+
+	msg.MentionRoles = append(msg.MentionRoles, m.MentionRoles...)
+	msg.MentionChannels = append(msg.MentionChannels, m.MentionChannels...)
+	msg.Attachments = append(msg.Attachments, m.Attachments...)
+	msg.Reactions = append(msg.Reactions, m.Reactions...)
+	msg.Stickers = append(msg.Stickers, m.Stickers...)
+	msg.StickerItems = append(msg.StickerItems, m.StickerItems...)
+
+	return msg
+}
+
+// Clone returns a clone of this MessageEmbed.
+func (e *MessageEmbed) Clone() *MessageEmbed {
+	if e == nil {
+		return nil
+	}
+
+	embed := &MessageEmbed{
+		Title:       e.Title,
+		Type:        e.Type,
+		Description: e.Description,
+		URL:         e.URL,
+		Timestamp:   e.Timestamp,
+		Color:       e.Color,
+	}
+
+	if e.Footer != nil {
+		footer := *e.Footer
+		embed.Footer = &footer
+	}
+	if e.Image != nil {
+		image := *e.Image
+		embed.Image = &image
+	}
+	if e.Thumbnail != nil {
+		thumbnail := *e.Thumbnail
+		embed.Thumbnail = &thumbnail
+	}
+	if e.Video != nil {
+		video := *e.Video
+		embed.Video = &video
+	}
+	if e.Provider != nil {
+		provider := *e.Provider
+		embed.Provider = &provider
+	}
+	if e.Author != nil {
+		author := *e.Author
+		embed.Author = &author
+	}
+
+	embed.Fields = append(embed.Fields, e.Fields...)
+
+	return embed
+}
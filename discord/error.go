@@ -14,14 +14,20 @@ var (
 	ErrGatewayNotConnected = errors.New("gateway is not connected")
 	// ErrGatewayAlreadyConnected is returned by Connect when a connection to the Gateway already exists.
 	ErrGatewayAlreadyConnected = errors.New("already connected to the Gateway")
-	// ErrInvalidMessageSend is returned by Send when no files are provided.
-	ErrInvalidMessageSend = errors.New("no content, embed nor file provided")
+	// ErrInvalidMessageSend is returned by Send when no content, embed, file nor sticker is provided.
+	ErrInvalidMessageSend = errors.New("no content, embed, file nor sticker provided")
 	// ErrAlreadyConnectedToVoice is returned when trying to join a voice channel in
 	// a guild where you are already have an active voice connection.
 	ErrAlreadyConnectedToVoice = errors.New("already connected to a voice channel in this guild, consider using the SwitchVoiceChannel method")
 	// ErrNotConnectedToVoice is returned when trying to switch to a different voice
 	// channel in a guild where you are not yet connected to a voice channel.
 	ErrNotConnectedToVoice = errors.New("not connected to a voice channel in this guild, use the JoinVoiceChannel method first")
+	// ErrStateNotTracked is returned when using a feature that requires
+	// state tracking while it is disabled.
+	ErrStateNotTracked = errors.New("state tracking is disabled")
+	// ErrNotInState is returned by State methods that need objects
+	// that are not in the state.
+	ErrNotInState = errors.New("not found in the state")
 	// ErrNotCurrentUser is returned for user endpoints used with an ID different than "@me".
 	ErrNotCurrentUser = errors.New("endpoint only available for current user (@me)")
 )
@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"fmt"
+)
+
+// GatewayCloseCode is a code sent by the Gateway when it closes a connection.
+// See https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-close-event-codes.
+type GatewayCloseCode int
+
+// Gateway close codes:
+const (
+	GatewayCloseCodeUnknownError         GatewayCloseCode = 4000
+	GatewayCloseCodeUnknownOpcode        GatewayCloseCode = 4001
+	GatewayCloseCodeDecodeError          GatewayCloseCode = 4002
+	GatewayCloseCodeNotAuthenticated     GatewayCloseCode = 4003
+	GatewayCloseCodeAuthenticationFailed GatewayCloseCode = 4004
+	GatewayCloseCodeAlreadyAuthenticated GatewayCloseCode = 4005
+	GatewayCloseCodeInvalidSequence      GatewayCloseCode = 4007
+	GatewayCloseCodeRateLimited          GatewayCloseCode = 4008
+	GatewayCloseCodeSessionTimedOut      GatewayCloseCode = 4009
+	GatewayCloseCodeInvalidShard         GatewayCloseCode = 4010
+	GatewayCloseCodeShardingRequired     GatewayCloseCode = 4011
+	GatewayCloseCodeInvalidAPIVersion    GatewayCloseCode = 4012
+	GatewayCloseCodeInvalidIntents       GatewayCloseCode = 4013
+	GatewayCloseCodeDisallowedIntents    GatewayCloseCode = 4014
+)
+
+var closeCodeDescriptions = map[GatewayCloseCode]string{
+	GatewayCloseCodeUnknownError:         "unknown error",
+	GatewayCloseCodeUnknownOpcode:        "unknown opcode",
+	GatewayCloseCodeDecodeError:          "decode error",
+	GatewayCloseCodeNotAuthenticated:     "not authenticated",
+	GatewayCloseCodeAuthenticationFailed: "authentication failed, the token is invalid",
+	GatewayCloseCodeAlreadyAuthenticated: "already authenticated",
+	GatewayCloseCodeInvalidSequence:      "invalid sequence number",
+	GatewayCloseCodeRateLimited:          "rate limited",
+	GatewayCloseCodeSessionTimedOut:      "session timed out",
+	GatewayCloseCodeInvalidShard:         "invalid shard",
+	GatewayCloseCodeShardingRequired:     "sharding required",
+	GatewayCloseCodeInvalidAPIVersion:    "invalid API version",
+	GatewayCloseCodeInvalidIntents:       "invalid intents",
+	GatewayCloseCodeDisallowedIntents:    "disallowed intents",
+}
+
+// String implements fmt.Stringer.
+func (c GatewayCloseCode) String() string {
+	if desc, ok := closeCodeDescriptions[c]; ok {
+		return desc
+	}
+	return "undocumented close code"
+}
+
+// Recoverable reports whether a new connection can be established after the
+// Gateway closed a connection with this code.
+func (c GatewayCloseCode) Recoverable() bool {
+	switch c {
+	case GatewayCloseCodeUnknownOpcode, GatewayCloseCodeDecodeError, GatewayCloseCodeNotAuthenticated,
+		GatewayCloseCodeAuthenticationFailed, GatewayCloseCodeAlreadyAuthenticated, GatewayCloseCodeInvalidShard,
+		GatewayCloseCodeShardingRequired, GatewayCloseCodeInvalidAPIVersion, GatewayCloseCodeInvalidIntents,
+		GatewayCloseCodeDisallowedIntents:
+		return false
+	default: // New (or undocumented?) close codes are assumed to be recoverable.
+		return true
+	}
+}
+
+// GatewayCloseError is returned when the Gateway closes the connection with
+// one of its close codes.
+type GatewayCloseError struct {
+	Code GatewayCloseCode
+	// Reason is the reason sent by the Gateway along the close code, if any.
+	Reason string
+	// Intents are the intents responsible for this error. It is only set for
+	// GatewayCloseCodeInvalidIntents (in which case these are all requested
+	// intents) and GatewayCloseCodeDisallowedIntents (in which case these are
+	// the privileged intents that were requested).
+	Intents GatewayIntent
+
+	err error
+}
+
+// NewGatewayCloseError returns a new GatewayCloseError given a close code, the reason
+// sent by the Gateway, the intents the client identified with and the original error.
+func NewGatewayCloseError(code GatewayCloseCode, reason string, intents GatewayIntent, err error) *GatewayCloseError {
+	e := &GatewayCloseError{
+		Code:   code,
+		Reason: reason,
+		err:    err,
+	}
+
+	switch code {
+	case GatewayCloseCodeInvalidIntents:
+		e.Intents = intents
+	case GatewayCloseCodeDisallowedIntents:
+		e.Intents = intents & GatewayIntentPrivileged
+	}
+
+	return e
+}
+
+// Error implements the error interface.
+func (e *GatewayCloseError) Error() string {
+	msg := fmt.Sprintf("gateway closed the connection: %s (%d)", e.Code, int(e.Code))
+
+	switch e.Code {
+	case GatewayCloseCodeInvalidIntents:
+		msg += fmt.Sprintf(", requested intents %s are not valid", e.Intents)
+	case GatewayCloseCodeDisallowedIntents:
+		if e.Intents != 0 {
+			msg += fmt.Sprintf(", privileged intents %s are not enabled for this application", e.Intents)
+		} else {
+			msg += ", some requested intents are not enabled for this application"
+		}
+	}
+
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// Unwrap returns the underlying websocket error.
+func (e *GatewayCloseError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGatewayCloseCodeRecoverable(t *testing.T) {
+	tests := []struct {
+		code        GatewayCloseCode
+		recoverable bool
+	}{
+		{code: GatewayCloseCodeUnknownError, recoverable: true},
+		{code: GatewayCloseCodeUnknownOpcode, recoverable: false},
+		{code: GatewayCloseCodeDecodeError, recoverable: false},
+		{code: GatewayCloseCodeNotAuthenticated, recoverable: false},
+		{code: GatewayCloseCodeAuthenticationFailed, recoverable: false},
+		{code: GatewayCloseCodeAlreadyAuthenticated, recoverable: false},
+		{code: GatewayCloseCodeInvalidSequence, recoverable: true},
+		{code: GatewayCloseCodeRateLimited, recoverable: true},
+		{code: GatewayCloseCodeSessionTimedOut, recoverable: true},
+		{code: GatewayCloseCodeInvalidShard, recoverable: false},
+		{code: GatewayCloseCodeShardingRequired, recoverable: false},
+		{code: GatewayCloseCodeInvalidAPIVersion, recoverable: false},
+		{code: GatewayCloseCodeInvalidIntents, recoverable: false},
+		{code: GatewayCloseCodeDisallowedIntents, recoverable: false},
+		// Undocumented close codes are assumed to be recoverable.
+		{code: 4006, recoverable: true},
+		{code: 4999, recoverable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", int(tt.code), tt.code), func(t *testing.T) {
+			if r := tt.code.Recoverable(); r != tt.recoverable {
+				t.Errorf("expected recoverable to be %t; got %t", tt.recoverable, r)
+			}
+		})
+	}
+}
+
+func TestGatewayCloseError(t *testing.T) {
+	wrapped := errors.New("websocket closed")
+	intents := GatewayIntentGuild | GatewayIntentGuildMembers | GatewayIntentGuildPresences
+
+	tests := []struct {
+		name     string
+		code     GatewayCloseCode
+		intents  GatewayIntent
+		expected string
+	}{
+		{
+			name:     "reason",
+			code:     GatewayCloseCodeAuthenticationFailed,
+			expected: "gateway closed the connection: authentication failed, the token is invalid (4004): reason",
+		},
+		{
+			name:     "invalid intents",
+			code:     GatewayCloseCodeInvalidIntents,
+			intents:  intents,
+			expected: fmt.Sprintf("gateway closed the connection: invalid intents (4013), requested intents %s are not valid: reason", intents),
+		},
+		{
+			name:     "disallowed intents",
+			code:     GatewayCloseCodeDisallowedIntents,
+			intents:  intents & GatewayIntentPrivileged,
+			expected: fmt.Sprintf("gateway closed the connection: disallowed intents (4014), privileged intents %s are not enabled for this application: reason", intents&GatewayIntentPrivileged),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGatewayCloseError(tt.code, "reason", intents, wrapped)
+			if err.Intents != tt.intents {
+				t.Errorf("expected intents %s; got %s", tt.intents, err.Intents)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q; got %q", tt.expected, err.Error())
+			}
+			if !errors.Is(err, wrapped) {
+				t.Error("expected close error to wrap the websocket error")
+			}
+		})
+	}
+}
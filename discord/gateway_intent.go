@@ -1,49 +1,81 @@
 package discord
 
+import (
+	"strconv"
+	"strings"
+)
+
 // GatewayIntent specifies which events the Gateway should send to a client.
 type GatewayIntent int
 
-// String implements fmt.Stringer.
+// String implements fmt.Stringer. Combined intents are
+// represented as a list of names separated by a pipe.
 func (i GatewayIntent) String() string {
-	return intentNames[i]
+	if name, ok := intentNames[i]; ok {
+		return name
+	}
+
+	var names []string
+	for bit := GatewayIntent(1); bit > 0 && bit <= i; bit <<= 1 {
+		if i&bit == 0 {
+			continue
+		}
+
+		if name, ok := intentNames[bit]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, strconv.Itoa(int(bit)))
+		}
+	}
+	return strings.Join(names, "|")
 }
 
 // List of gateway intents a client can subscribe to.
 const (
-	GatewayIntentGuild                  GatewayIntent = 1 << 0
-	GatewayIntentGuildMembers           GatewayIntent = 1 << 1
-	GatewayIntentGuildBans              GatewayIntent = 1 << 2
-	GatewayIntentGuildEmojis            GatewayIntent = 1 << 3
-	GatewayIntentGuildIntegrations      GatewayIntent = 1 << 4
-	GatewayIntentGuildWebhooks          GatewayIntent = 1 << 5
-	GatewayIntentGuildInvites           GatewayIntent = 1 << 6
-	GatewayIntentGuildVoiceStates       GatewayIntent = 1 << 7
-	GatewayIntentGuildPresences         GatewayIntent = 1 << 8
-	GatewayIntentGuildMessages          GatewayIntent = 1 << 9
-	GatewayIntentGuildMessageReactions  GatewayIntent = 1 << 10
-	GatewayIntentGuildMessageTyping     GatewayIntent = 1 << 11
-	GatewayIntentDirectMessages         GatewayIntent = 1 << 12
-	GatewayIntentDirectMessageReactions GatewayIntent = 1 << 13
-	GatewayIntentDirectMessageTyping    GatewayIntent = 1 << 14
+	GatewayIntentGuild                       GatewayIntent = 1 << 0
+	GatewayIntentGuildMembers                GatewayIntent = 1 << 1
+	GatewayIntentGuildBans                   GatewayIntent = 1 << 2
+	GatewayIntentGuildEmojis                 GatewayIntent = 1 << 3
+	GatewayIntentGuildIntegrations           GatewayIntent = 1 << 4
+	GatewayIntentGuildWebhooks               GatewayIntent = 1 << 5
+	GatewayIntentGuildInvites                GatewayIntent = 1 << 6
+	GatewayIntentGuildVoiceStates            GatewayIntent = 1 << 7
+	GatewayIntentGuildPresences              GatewayIntent = 1 << 8
+	GatewayIntentGuildMessages               GatewayIntent = 1 << 9
+	GatewayIntentGuildMessageReactions       GatewayIntent = 1 << 10
+	GatewayIntentGuildMessageTyping          GatewayIntent = 1 << 11
+	GatewayIntentDirectMessages              GatewayIntent = 1 << 12
+	GatewayIntentDirectMessageReactions      GatewayIntent = 1 << 13
+	GatewayIntentDirectMessageTyping         GatewayIntent = 1 << 14
+	GatewayIntentGuildScheduledEvents        GatewayIntent = 1 << 16
+	GatewayIntentAutoModerationConfiguration GatewayIntent = 1 << 20
+	GatewayIntentAutoModerationExecution     GatewayIntent = 1 << 21
 )
 
+// GatewayIntentPrivileged are the intents that must be enabled in the settings
+// of an application before a client can subscribe to them.
+const GatewayIntentPrivileged = GatewayIntentGuildMembers | GatewayIntentGuildPresences
+
 // Equivalent to all intents except privileged (GatewayIntentGuildMembers and GatewayIntentGuildPresences), OR'd.
-const GatewayIntentUnprivileged = GatewayIntentGuild | GatewayIntentGuildBans | GatewayIntentGuildEmojis | GatewayIntentGuildIntegrations | GatewayIntentGuildWebhooks | GatewayIntentGuildInvites | GatewayIntentGuildVoiceStates | GatewayIntentGuildMessages | GatewayIntentGuildMessageReactions | GatewayIntentGuildMessageTyping | GatewayIntentDirectMessages | GatewayIntentDirectMessageReactions | GatewayIntentDirectMessageTyping
+const GatewayIntentUnprivileged = GatewayIntentGuild | GatewayIntentGuildBans | GatewayIntentGuildEmojis | GatewayIntentGuildIntegrations | GatewayIntentGuildWebhooks | GatewayIntentGuildInvites | GatewayIntentGuildVoiceStates | GatewayIntentGuildMessages | GatewayIntentGuildMessageReactions | GatewayIntentGuildMessageTyping | GatewayIntentDirectMessages | GatewayIntentDirectMessageReactions | GatewayIntentDirectMessageTyping | GatewayIntentGuildScheduledEvents | GatewayIntentAutoModerationConfiguration | GatewayIntentAutoModerationExecution
 
 var intentNames = map[GatewayIntent]string{
-	GatewayIntentGuild:                  "GUILDS",
-	GatewayIntentGuildMembers:           "GUILD_MEMBERS",
-	GatewayIntentGuildBans:              "GUILD_BANS",
-	GatewayIntentGuildEmojis:            "GUILD_EMOJIS",
-	GatewayIntentGuildIntegrations:      "GUILD_INTEGRATIONS",
-	GatewayIntentGuildWebhooks:          "GUILD_WEBHOOKS",
-	GatewayIntentGuildInvites:           "GUILD_INVITES",
-	GatewayIntentGuildVoiceStates:       "GUILD_VOICE_STATES",
-	GatewayIntentGuildPresences:         "GUILD_PRESENCES",
-	GatewayIntentGuildMessages:          "GUILD_MESSAGES",
-	GatewayIntentGuildMessageReactions:  "GUILD_MESSAGE_REACTIONS",
-	GatewayIntentGuildMessageTyping:     "GUILD_MESSAGE_TYPING",
-	GatewayIntentDirectMessages:         "DIRECT_MESSAGES",
-	GatewayIntentDirectMessageReactions: "DIRECT_MESSAGE_REACTIONS",
-	GatewayIntentDirectMessageTyping:    "DIRECT_MESSAGE_TYPING",
+	GatewayIntentGuild:                       "GUILDS",
+	GatewayIntentGuildMembers:                "GUILD_MEMBERS",
+	GatewayIntentGuildBans:                   "GUILD_BANS",
+	GatewayIntentGuildEmojis:                 "GUILD_EMOJIS",
+	GatewayIntentGuildIntegrations:           "GUILD_INTEGRATIONS",
+	GatewayIntentGuildWebhooks:               "GUILD_WEBHOOKS",
+	GatewayIntentGuildInvites:                "GUILD_INVITES",
+	GatewayIntentGuildVoiceStates:            "GUILD_VOICE_STATES",
+	GatewayIntentGuildPresences:              "GUILD_PRESENCES",
+	GatewayIntentGuildMessages:               "GUILD_MESSAGES",
+	GatewayIntentGuildMessageReactions:       "GUILD_MESSAGE_REACTIONS",
+	GatewayIntentGuildMessageTyping:          "GUILD_MESSAGE_TYPING",
+	GatewayIntentDirectMessages:              "DIRECT_MESSAGES",
+	GatewayIntentDirectMessageReactions:      "DIRECT_MESSAGE_REACTIONS",
+	GatewayIntentDirectMessageTyping:         "DIRECT_MESSAGE_TYPING",
+	GatewayIntentGuildScheduledEvents:        "GUILD_SCHEDULED_EVENTS",
+	GatewayIntentAutoModerationConfiguration: "AUTO_MODERATION_CONFIGURATION",
+	GatewayIntentAutoModerationExecution:     "AUTO_MODERATION_EXECUTION",
 }
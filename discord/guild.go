@@ -1,6 +1,8 @@
 package discord
 
 import (
+	"time"
+
 	"github.com/skwair/harmony/voice"
 )
 
@@ -131,6 +133,9 @@ type Guild struct {
 	ExplicitContentFilter       GuildExplicitContentFilter    `json:"explicit_content_filter"`
 	Roles                       []Role                        `json:"roles"`
 	Emojis                      []Emoji                       `json:"emojis"`
+	Stickers                    []Sticker                     `json:"stickers"`
+	ScheduledEvents             []ScheduledEvent              `json:"guild_scheduled_events"`
+	StageInstances              []StageInstance               `json:"stage_instances"`
 	Features                    []string                      `json:"features"`
 	MFALevel                    MFALevel                      `json:"mfa_level"`
 	ApplicationID               string                        `json:"application_id"`
@@ -148,8 +153,8 @@ type Guild struct {
 	MaxVideoChannelUsers        int                           `json:"max_video_channel_users"`
 
 	// Following fields are only sent using the Get Guild method and are relative to the current user.
-	Owner       bool `json:"owner"`
-	Permissions int  `json:"permissions,string"`
+	Owner       bool  `json:"owner"`
+	Permissions int64 `json:"permissions,string"`
 
 	// Following fields are only sent within the GUILD_CREATE event.
 	JoinedAt    Time          `json:"joined_at"`
@@ -169,7 +174,7 @@ type PartialGuild struct {
 	Name        string `json:"name"`
 	Icon        string `json:"icon"`
 	Owner       bool   `json:"owner"`
-	Permissions int    `json:"permissions,string"`
+	Permissions int64  `json:"permissions,string"`
 }
 
 // UnavailableGuild is a Guild that is not available, either because there is a
@@ -189,14 +194,36 @@ type GuildMember struct {
 	PremiumSince Time     `json:"premium_since"`
 	Deaf         bool     `json:"deaf"`
 	Mute         bool     `json:"mute"`
+	// Time until which this member is timed out, zero if it is not.
+	// It can be in the past if the timeout expired.
+	CommunicationDisabledUntil Time `json:"communication_disabled_until"`
+}
+
+// TimedOut returns whether the Guild member is currently timed out, meaning
+// it can not send messages, react, join voice channels or speak in them.
+func (m *GuildMember) TimedOut() bool {
+	return m.CommunicationDisabledUntil.After(time.Now())
 }
 
 // PermissionsIn returns the permissions of the Guild member in the given Guild and channel.
-func (m *GuildMember) PermissionsIn(g *Guild, ch *Channel) (permissions int) {
+func (m *GuildMember) PermissionsIn(g *Guild, ch *Channel) (permissions int64) {
 	base := computeBasePermissions(g, m)
 	return computeOverwrites(ch, m, base)
 }
 
+// Permissions returns the guild-wide permissions of the Guild member in the given
+// Guild, without taking channel permission overwrites into account.
+func (m *GuildMember) Permissions(g *Guild) (permissions int64) {
+	return computeBasePermissions(g, m)
+}
+
+// ExplainPermissionsIn explains which roles and permission overwrites granted or denied
+// each permission of the Guild member in the given Guild and channel. If ch is nil,
+// channel permission overwrites are not taken into account.
+func (m *GuildMember) ExplainPermissionsIn(g *Guild, ch *Channel) *PermissionsExplanation {
+	return explainPermissions(g, ch, m)
+}
+
 // HasRole returns whether this member has the given role.
 // Note that this method does not try to fetch this member latest roles, it instead looks
 // in the roles it already had when this member object was created.
@@ -220,7 +247,7 @@ type Role struct {
 	Color       int    `json:"color"`    // Integer representation of hexadecimal color code.
 	Hoist       bool   `json:"hoist"`    // Whether this role is pinned in the user listing.
 	Position    int    `json:"position"` // Integer	position of this role.
-	Permissions int    `json:"permissions,string"`
+	Permissions int64  `json:"permissions,string"`
 	Managed     bool   `json:"managed"` // Whether this role is managed by an integration.
 	Mentionable bool   `json:"mentionable"`
 }
@@ -2,6 +2,7 @@ package discord
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/skwair/harmony/optional"
 )
@@ -20,12 +21,45 @@ type GuildSettings struct {
 	OwnerID                     *optional.String `json:"owner_id,omitempty"`
 	Splash                      *optional.String `json:"splash,omitempty"`
 	SystemChannelID             *optional.String `json:"system_channel_id,omitempty"`
+
+	// Roles and Channels can only be set when creating a guild.
+	Roles    []GuildCreateRole    `json:"roles,omitempty"`
+	Channels []GuildCreateChannel `json:"channels,omitempty"`
+}
+
+// GuildCreateRole is a role a guild is created with.
+type GuildCreateRole struct {
+	// ID is a placeholder for the ID of this role, used to reference it in the
+	// permission overwrites of the channels the guild is created with. The first
+	// role is the @everyone role and its ID is ignored.
+	ID          int    `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Permissions int64  `json:"permissions,string,omitempty"`
+	Color       int    `json:"color,omitempty"`
+	Hoist       bool   `json:"hoist,omitempty"`
+	Mentionable bool   `json:"mentionable,omitempty"`
+}
+
+// GuildCreateChannel is a channel a guild is created with.
+type GuildCreateChannel struct {
+	// ID is a placeholder for the ID of this channel, used to reference it as the parent
+	// of other channels or as the AFK or system channel of the guild. It is only required
+	// for category channels and for channels used as AFK or system channel.
+	ID       int         `json:"id,omitempty"`
+	ParentID int         `json:"parent_id,omitempty"`
+	Name     string      `json:"name"`
+	Type     ChannelType `json:"type"`
+	Topic    string      `json:"topic,omitempty"`
+	NSFW     bool        `json:"nsfw,omitempty"`
+	// Permission overwrites of this channel. Their ID must be the placeholder ID of
+	// one of the roles the guild is created with.
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites,omitempty"`
 }
 
 // GuildSetting is a function that configures a guild.
 type GuildSetting func(*GuildSettings)
 
-// NewGuildSettings returns new GuildSettings to modify a a guild.
+// NewGuildSettings returns new GuildSettings to create or modify a guild.
 func NewGuildSettings(opts ...GuildSetting) *GuildSettings {
 	s := &GuildSettings{}
 
@@ -118,6 +152,21 @@ func WithGuildSystemChannel(id string) GuildSetting {
 	}
 }
 
+// WithGuildRoles sets the roles a guild is created with. It has no effect when modifying a guild.
+func WithGuildRoles(roles ...GuildCreateRole) GuildSetting {
+	return func(s *GuildSettings) {
+		s.Roles = roles
+	}
+}
+
+// WithGuildChannels sets the channels a guild is created with. If set, the default
+// channels of new guilds are not created. It has no effect when modifying a guild.
+func WithGuildChannels(channels ...GuildCreateChannel) GuildSetting {
+	return func(s *GuildSettings) {
+		s.Channels = channels
+	}
+}
+
 // GuildMemberSettings are the settings of a guild member, all fields are optional
 // and only those explicitly set will be modified.
 type GuildMemberSettings struct {
@@ -127,6 +176,8 @@ type GuildMemberSettings struct {
 	Deaf  *optional.Bool        `json:"deaf,omitempty"`
 	// ID of channel to move user to (if they are connected to voice).
 	ChannelID *optional.String `json:"channel_id,omitempty"`
+	// Time until which the member is timed out, up to 28 days in the future.
+	CommunicationDisabledUntil *optional.String `json:"communication_disabled_until,omitempty"`
 }
 
 // GuildMemberSetting is a function that configures a guild member.
@@ -171,6 +222,18 @@ func WithGuildMemberDeaf(yes bool) GuildMemberSetting {
 	}
 }
 
+// WithGuildMemberTimeout times out a guild member until the given time, up to 28 days
+// in the future. A zero time removes the timeout. Requires the 'MODERATE_MEMBERS' permission.
+func WithGuildMemberTimeout(until time.Time) GuildMemberSetting {
+	return func(s *GuildMemberSettings) {
+		if until.IsZero() {
+			s.CommunicationDisabledUntil = optional.NewNilString()
+		} else {
+			s.CommunicationDisabledUntil = optional.NewString(until.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
 // WithGuildMemberChannelID sets the channel id of a guild member (if connected to voice).
 func WithGuildMemberChannelID(id string) GuildMemberSetting {
 	return func(s *GuildMemberSettings) {
@@ -210,9 +273,9 @@ func WithRoleName(name string) RoleSetting {
 }
 
 // WithRolePermissions sets the permissions of guild a role.
-func WithRolePermissions(perm int) RoleSetting {
+func WithRolePermissions(perm int64) RoleSetting {
 	return func(s *RoleSettings) {
-		s.Permissions = optional.NewString(strconv.Itoa(perm))
+		s.Permissions = optional.NewString(strconv.FormatInt(perm, 10))
 	}
 }
 
@@ -0,0 +1,20 @@
+package discord
+
+// GuildTemplate is a snapshot of a guild that can be used to create new guilds.
+type GuildTemplate struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Number of times this template has been used.
+	UsageCount    int    `json:"usage_count"`
+	CreatorID     string `json:"creator_id"`
+	Creator       *User  `json:"creator"`
+	CreatedAt     Time   `json:"created_at"`
+	UpdatedAt     Time   `json:"updated_at"`
+	SourceGuildID string `json:"source_guild_id"`
+	// Partial snapshot of the guild this template was created from. Its roles and
+	// channels have placeholder IDs instead of the IDs of the source guild.
+	SerializedSourceGuild *Guild `json:"serialized_source_guild"`
+	// Whether this template has unsynced changes.
+	IsDirty bool `json:"is_dirty"`
+}
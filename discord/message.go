@@ -1,5 +1,7 @@
 package discord
 
+import "encoding/json"
+
 // MessageType describes the type of a message. Different fields
 // are set or not depending on the message's type.
 type MessageType int
@@ -35,8 +37,52 @@ const (
 	MessageFlagIsCrosspost MessageFlag = 1 << 1
 	// Do not include any embeds when serializing this message.
 	MessageFlagSuppressEmbeds MessageFlag = 1 << 2
+	// The source message for this crosspost has been deleted (via Channel Following).
+	MessageFlagSourceMessageDeleted MessageFlag = 1 << 3
+	// This message came from the urgent message system.
+	MessageFlagUrgent MessageFlag = 1 << 4
+	// This message will not trigger push and desktop notifications.
+	MessageFlagSuppressNotifications MessageFlag = 1 << 12
+)
+
+// AllowedMentionType is a type of mention that can be parsed from the content of a message.
+type AllowedMentionType string
+
+const (
+	// Role mentions are parsed from the content of the message.
+	AllowedMentionTypeRoles AllowedMentionType = "roles"
+	// User mentions are parsed from the content of the message.
+	AllowedMentionTypeUsers AllowedMentionType = "users"
+	// @everyone and @here mentions are parsed from the content of the message.
+	AllowedMentionTypeEveryone AllowedMentionType = "everyone"
 )
 
+// AllowedMentions controls which mentions in the content of a message actually
+// notify their targets. A zero value suppresses all mentions.
+type AllowedMentions struct {
+	// Types of mentions to parse from the content of the message.
+	Parse []AllowedMentionType `json:"parse"`
+	// IDs of roles that can be mentioned, up to 100. Must not be set
+	// if Parse contains AllowedMentionTypeRoles.
+	Roles []string `json:"roles,omitempty"`
+	// IDs of users that can be mentioned, up to 100. Must not be set
+	// if Parse contains AllowedMentionTypeUsers.
+	Users []string `json:"users,omitempty"`
+	// For replies, whether to mention the author of the message being replied to.
+	RepliedUser bool `json:"replied_user"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *AllowedMentions) MarshalJSON() ([]byte, error) {
+	type allowedMentions AllowedMentions
+	am := allowedMentions(*m)
+	// An empty list must be sent to suppress all mentions.
+	if am.Parse == nil {
+		am.Parse = []AllowedMentionType{}
+	}
+	return json.Marshal(am)
+}
+
 // Message represents a message sent in a channel within Discord.
 // The author object follows the structure of the user object, but is
 // only a valid user in the case where the message is generated by a
@@ -88,6 +134,7 @@ type Message struct {
 	MessageReference  MessageReference   `json:"message_reference"`
 	Flags             MessageFlag        `json:"flags"`
 	Stickers          []MessageSticker   `json:"stickers"`
+	StickerItems      []StickerItem      `json:"sticker_items"`
 	ReferencedMessage *Message           `json:"referenced_message"`
 }
 
@@ -123,8 +170,12 @@ const (
 	StickerFormatPNG    StickerFormat = 1
 	StickerFormatAPNG   StickerFormat = 2
 	StickerFormatLOTTIE StickerFormat = 3
+	StickerFormatGIF    StickerFormat = 4
 )
 
+// MessageSticker is a sticker sent in a message.
+//
+// Deprecated: use StickerItems instead.
 type MessageSticker struct {
 	ID           string        `json:"id"`
 	PackID       string        `json:"pack_id"`
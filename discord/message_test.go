@@ -0,0 +1,45 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAllowedMentionsMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		mentions *AllowedMentions
+		expected string
+	}{
+		{
+			name:     "zero value suppresses mentions",
+			mentions: &AllowedMentions{},
+			expected: `{"parse":[],"replied_user":false}`,
+		},
+		{
+			name:     "parse",
+			mentions: &AllowedMentions{Parse: []AllowedMentionType{AllowedMentionTypeUsers, AllowedMentionTypeEveryone}},
+			expected: `{"parse":["users","everyone"],"replied_user":false}`,
+		},
+		{
+			name:     "explicit targets",
+			mentions: &AllowedMentions{Roles: []string{"r1"}, Users: []string{"u1", "u2"}, RepliedUser: true},
+			expected: `{"parse":[],"roles":["r1"],"users":["u1","u2"],"replied_user":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Allowed mentions are always sent as part of another payload.
+			b, err := json.Marshal(struct {
+				AllowedMentions *AllowedMentions `json:"allowed_mentions"`
+			}{tt.mentions})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := `{"allowed_mentions":` + tt.expected + `}`; string(b) != expected {
+				t.Errorf("expected %s; got %s", expected, b)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package discord
 
+import "fmt"
+
 // Set of permissions that can be assigned to Users and Roles.
 const (
 	PermissionNone               = 0x00000000 // Allows nothing.
@@ -31,7 +33,14 @@ const (
 	PermissionManageNicknames    = 0x08000000 // Allows for modification of other users nicknames.
 	PermissionManageRoles        = 0x10000000 // Allows management and editing of roles.
 	PermissionManageWebhooks     = 0x20000000 // Allows management and editing of webhooks.
-	PermissionManageEmojis       = 0x40000000 // Allows management and editing of emojis.
+	PermissionManageEmojis       = 0x40000000 // Allows management and editing of emojis and stickers.
+)
+
+// Permissions that do not fit in 32 bits.
+const (
+	PermissionRequestToSpeak  = 0x100000000   // Allows for requesting to speak in stage channels.
+	PermissionManageEvents    = 0x200000000   // Allows for creating, editing and deleting scheduled events.
+	PermissionModerateMembers = 0x10000000000 // Allows for timing out users.
 )
 
 // PermissionOverwrite describes a specific permission that overwrites
@@ -39,17 +48,17 @@ const (
 type PermissionOverwrite struct {
 	Type  int    `json:"type"` // Either 0 for "role" or 1 for "member".
 	ID    string `json:"id"`   // ID of the role or member, depending on Type.
-	Allow int    `json:"allow,string"`
-	Deny  int    `json:"deny,string"`
+	Allow int64  `json:"allow,string"`
+	Deny  int64  `json:"deny,string"`
 }
 
 // PermissionsContains returns whether the given permission is set in permissions.
-func PermissionsContains(permissions, permission int) bool {
+func PermissionsContains(permissions, permission int64) bool {
 	return permissions&permission == permission
 }
 
 // computeBasePermissions returns the base permissions a member has in a given guild.
-func computeBasePermissions(g *Guild, m *GuildMember) (permissions int) {
+func computeBasePermissions(g *Guild, m *GuildMember) (permissions int64) {
 	if g.OwnerID == m.User.ID {
 		return PermissionAdministrator
 	}
@@ -72,7 +81,7 @@ func computeBasePermissions(g *Guild, m *GuildMember) (permissions int) {
 	return permissions
 }
 
-func computeOverwrites(ch *Channel, m *GuildMember, basePermissions int) (permissions int) {
+func computeOverwrites(ch *Channel, m *GuildMember, basePermissions int64) (permissions int64) {
 	// Administrator can not be overridden.
 	if PermissionsContains(basePermissions, PermissionAdministrator) {
 		return PermissionAdministrator
@@ -87,8 +96,7 @@ func computeOverwrites(ch *Channel, m *GuildMember, basePermissions int) (permis
 	}
 
 	pos := ch.PermissionOverwrites
-	allow := PermissionNone
-	deny := PermissionNone
+	var allow, deny int64
 	for _, id := range m.Roles {
 		por := overwriteByID(pos, id)
 		if por != nil {
@@ -125,3 +133,205 @@ func overwriteByID(po []PermissionOverwrite, id string) *PermissionOverwrite {
 	}
 	return nil
 }
+
+// permissionNames are human readable names of permissions, in the
+// order they are listed in when explaining permissions.
+var permissionNames = []struct {
+	permission int64
+	name       string
+}{
+	{PermissionCreateInvite, "Create Invite"},
+	{PermissionKickMembers, "Kick Members"},
+	{PermissionBanMembers, "Ban Members"},
+	{PermissionAdministrator, "Administrator"},
+	{PermissionManageChannels, "Manage Channels"},
+	{PermissionManageGuild, "Manage Guild"},
+	{PermissionAddReactions, "Add Reactions"},
+	{PermissionViewAuditLog, "View Audit Log"},
+	{PermissionPrioritySpeaker, "Priority Speaker"},
+	{PermissionViewChannel, "View Channel"},
+	{PermissionSendMessages, "Send Messages"},
+	{PermissionSendTTSMessages, "Send TTS Messages"},
+	{PermissionManageMessages, "Manage Messages"},
+	{PermissionEmbedLinks, "Embed Links"},
+	{PermissionAttachFiles, "Attach Files"},
+	{PermissionReadMessageHistory, "Read Message History"},
+	{PermissionMentionEveryone, "Mention Everyone"},
+	{PermissionUseExternalEmojis, "Use External Emojis"},
+	{PermissionConnect, "Connect"},
+	{PermissionSpeak, "Speak"},
+	{PermissionMuteMembers, "Mute Members"},
+	{PermissionDeafenMembers, "Deafen Members"},
+	{PermissionMoveMembers, "Move Members"},
+	{PermissionUseVAD, "Use VAD"},
+	{PermissionChangeNickname, "Change Nickname"},
+	{PermissionManageNicknames, "Manage Nicknames"},
+	{PermissionManageRoles, "Manage Roles"},
+	{PermissionManageWebhooks, "Manage Webhooks"},
+	{PermissionManageEmojis, "Manage Emojis"},
+	{PermissionRequestToSpeak, "Request to Speak"},
+	{PermissionManageEvents, "Manage Events"},
+	{PermissionModerateMembers, "Moderate Members"},
+}
+
+// PermissionName returns the human readable name of the given permission,
+// or an empty string if it is not a known single permission.
+func PermissionName(permission int64) string {
+	for _, p := range permissionNames {
+		if p.permission == permission {
+			return p.name
+		}
+	}
+	return ""
+}
+
+// PermissionSourceType is the kind of object that granted or denied a permission.
+type PermissionSourceType int
+
+// Valid permission source types.
+const (
+	// No role or overwrite granted the permission, it is denied by default.
+	PermissionSourceNone PermissionSourceType = iota
+	// The member owns the guild and has all permissions.
+	PermissionSourceOwner
+	// A guild role, including the @everyone role.
+	PermissionSourceRole
+	// A channel overwrite for a role, including the @everyone role.
+	PermissionSourceRoleOverwrite
+	// A channel overwrite for the member.
+	PermissionSourceMemberOverwrite
+)
+
+// PermissionDecision explains why a single permission is granted or denied.
+type PermissionDecision struct {
+	Permission int64
+	Allowed    bool
+	// Source is what granted or denied this permission last. When Source is a
+	// role or a role overwrite, SourceID is the ID of the role. When it is a
+	// member overwrite or the guild owner, SourceID is the ID of the member.
+	Source   PermissionSourceType
+	SourceID string
+}
+
+// String returns a human readable explanation of this decision,
+// e.g. "Send Messages: denied by role overwrite 123".
+func (d PermissionDecision) String() string {
+	verb := "denied"
+	if d.Allowed {
+		verb = "allowed"
+	}
+
+	name := PermissionName(d.Permission)
+	switch d.Source {
+	case PermissionSourceOwner:
+		return fmt.Sprintf("%s: %s as guild owner", name, verb)
+	case PermissionSourceRole:
+		return fmt.Sprintf("%s: %s by role %s", name, verb, d.SourceID)
+	case PermissionSourceRoleOverwrite:
+		return fmt.Sprintf("%s: %s by role overwrite %s", name, verb, d.SourceID)
+	case PermissionSourceMemberOverwrite:
+		return fmt.Sprintf("%s: %s by member overwrite %s", name, verb, d.SourceID)
+	}
+	return fmt.Sprintf("%s: %s, no role grants it", name, verb)
+}
+
+// PermissionsExplanation explains how the permissions of a member were computed.
+type PermissionsExplanation struct {
+	// Resulting permissions.
+	Permissions int64
+	// One decision per known permission.
+	Decisions []PermissionDecision
+}
+
+// Missing returns decisions of the given permissions that are denied.
+func (e *PermissionsExplanation) Missing(permissions int64) []PermissionDecision {
+	var missing []PermissionDecision
+	for _, d := range e.Decisions {
+		if !d.Allowed && PermissionsContains(permissions, d.Permission) {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// explainPermissions explains the permissions a member has in a given guild and,
+// if ch is not nil, in a given channel. It follows the same rules as
+// computeBasePermissions and computeOverwrites.
+func explainPermissions(g *Guild, ch *Channel, m *GuildMember) *PermissionsExplanation {
+	e := &PermissionsExplanation{Decisions: make([]PermissionDecision, len(permissionNames))}
+	for i, p := range permissionNames {
+		e.Decisions[i].Permission = p.permission
+	}
+
+	// set records the source of the given permissions.
+	set := func(permissions int64, allowed bool, src PermissionSourceType, id string) {
+		for i := range e.Decisions {
+			d := &e.Decisions[i]
+			if PermissionsContains(permissions, d.Permission) {
+				d.Allowed = allowed
+				d.Source = src
+				d.SourceID = id
+			}
+		}
+	}
+
+	if g.OwnerID == m.User.ID {
+		set(^PermissionNone, true, PermissionSourceOwner, m.User.ID)
+		e.Permissions = PermissionAdministrator
+		return e
+	}
+
+	// Permissions are attributed to the first role that grants them.
+	roleIDs := append([]string{g.ID}, m.Roles...)
+	for _, id := range roleIDs {
+		if role := roleByID(g.Roles, id); role != nil {
+			set(role.Permissions&^e.Permissions, true, PermissionSourceRole, role.ID)
+			e.Permissions |= role.Permissions
+		}
+	}
+
+	// Administrator can not be overridden.
+	if PermissionsContains(e.Permissions, PermissionAdministrator) {
+		for _, d := range e.Decisions {
+			if d.Permission == PermissionAdministrator {
+				set(^PermissionNone, true, d.Source, d.SourceID)
+				break
+			}
+		}
+		e.Permissions = PermissionAdministrator
+		return e
+	}
+
+	if ch == nil {
+		return e
+	}
+
+	if po := overwriteByID(ch.PermissionOverwrites, ch.GuildID); po != nil {
+		set(po.Deny, false, PermissionSourceRoleOverwrite, po.ID)
+		set(po.Allow, true, PermissionSourceRoleOverwrite, po.ID)
+		e.Permissions &= ^po.Deny
+		e.Permissions |= po.Allow
+	}
+
+	// Role overwrites are applied all at once, allows take precedence over denies.
+	var allow, deny int64
+	for _, id := range m.Roles {
+		if por := overwriteByID(ch.PermissionOverwrites, id); por != nil {
+			set(por.Deny&^allow, false, PermissionSourceRoleOverwrite, por.ID)
+			set(por.Allow, true, PermissionSourceRoleOverwrite, por.ID)
+			allow |= por.Allow
+			deny |= por.Deny
+		}
+	}
+	e.Permissions &= ^deny
+	e.Permissions |= allow
+
+	if pom := overwriteByID(ch.PermissionOverwrites, m.User.ID); pom != nil {
+		set(pom.Deny, false, PermissionSourceMemberOverwrite, pom.ID)
+		set(pom.Allow, true, PermissionSourceMemberOverwrite, pom.ID)
+		e.Permissions &= ^pom.Deny
+		e.Permissions |= pom.Allow
+	}
+
+	return e
+}
@@ -0,0 +1,132 @@
+package discord
+
+import "testing"
+
+func TestExplainPermissions(t *testing.T) {
+	guild := func(ownerID string, roles ...Role) *Guild {
+		return &Guild{ID: "g1", OwnerID: ownerID, Roles: append([]Role{{ID: "g1", Permissions: PermissionViewChannel}}, roles...)}
+	}
+	member := &GuildMember{User: &User{ID: "u1"}, Roles: []string{"r1", "r2"}}
+
+	tests := []struct {
+		name string
+		g    *Guild
+		ch   *Channel
+		// Expected decision for some permissions.
+		expected map[int64]PermissionDecision
+	}{
+		{
+			name: "owner",
+			g:    guild("u1"),
+			expected: map[int64]PermissionDecision{
+				PermissionAdministrator:   {Allowed: true, Source: PermissionSourceOwner, SourceID: "u1"},
+				PermissionModerateMembers: {Allowed: true, Source: PermissionSourceOwner, SourceID: "u1"},
+			},
+		},
+		{
+			name: "roles",
+			g:    guild("u2", Role{ID: "r1", Permissions: PermissionSendMessages}, Role{ID: "r2", Permissions: PermissionSendMessages | PermissionModerateMembers}),
+			expected: map[int64]PermissionDecision{
+				PermissionViewChannel:     {Allowed: true, Source: PermissionSourceRole, SourceID: "g1"},
+				PermissionSendMessages:    {Allowed: true, Source: PermissionSourceRole, SourceID: "r1"},
+				PermissionModerateMembers: {Allowed: true, Source: PermissionSourceRole, SourceID: "r2"},
+				PermissionKickMembers:     {Allowed: false, Source: PermissionSourceNone},
+			},
+		},
+		{
+			name: "administrator",
+			g:    guild("u2", Role{ID: "r2", Permissions: PermissionAdministrator}),
+			ch: &Channel{GuildID: "g1", PermissionOverwrites: []PermissionOverwrite{
+				{ID: "u1", Type: 1, Deny: PermissionSendMessages},
+			}},
+			expected: map[int64]PermissionDecision{
+				PermissionSendMessages: {Allowed: true, Source: PermissionSourceRole, SourceID: "r2"},
+				PermissionBanMembers:   {Allowed: true, Source: PermissionSourceRole, SourceID: "r2"},
+			},
+		},
+		{
+			name: "everyone overwrite",
+			g:    guild("u2", Role{ID: "r1", Permissions: PermissionSendMessages}),
+			ch: &Channel{GuildID: "g1", PermissionOverwrites: []PermissionOverwrite{
+				{ID: "g1", Deny: PermissionSendMessages | PermissionViewChannel},
+			}},
+			expected: map[int64]PermissionDecision{
+				PermissionViewChannel:  {Allowed: false, Source: PermissionSourceRoleOverwrite, SourceID: "g1"},
+				PermissionSendMessages: {Allowed: false, Source: PermissionSourceRoleOverwrite, SourceID: "g1"},
+			},
+		},
+		{
+			name: "role overwrite allows take precedence",
+			g:    guild("u2"),
+			ch: &Channel{GuildID: "g1", PermissionOverwrites: []PermissionOverwrite{
+				{ID: "r1", Allow: PermissionSendMessages},
+				{ID: "r2", Deny: PermissionSendMessages | PermissionViewChannel},
+			}},
+			expected: map[int64]PermissionDecision{
+				PermissionViewChannel:  {Allowed: false, Source: PermissionSourceRoleOverwrite, SourceID: "r2"},
+				PermissionSendMessages: {Allowed: true, Source: PermissionSourceRoleOverwrite, SourceID: "r1"},
+			},
+		},
+		{
+			name: "member overwrite",
+			g:    guild("u2", Role{ID: "r1", Permissions: PermissionSendMessages}),
+			ch: &Channel{GuildID: "g1", PermissionOverwrites: []PermissionOverwrite{
+				{ID: "r1", Allow: PermissionAttachFiles},
+				{ID: "u1", Type: 1, Allow: PermissionEmbedLinks, Deny: PermissionAttachFiles | PermissionSendMessages},
+			}},
+			expected: map[int64]PermissionDecision{
+				PermissionViewChannel:  {Allowed: true, Source: PermissionSourceRole, SourceID: "g1"},
+				PermissionSendMessages: {Allowed: false, Source: PermissionSourceMemberOverwrite, SourceID: "u1"},
+				PermissionAttachFiles:  {Allowed: false, Source: PermissionSourceMemberOverwrite, SourceID: "u1"},
+				PermissionEmbedLinks:   {Allowed: true, Source: PermissionSourceMemberOverwrite, SourceID: "u1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := member.ExplainPermissionsIn(tt.g, tt.ch)
+
+			// The explanation must agree with the permissions computed the usual way.
+			expected := member.Permissions(tt.g)
+			if tt.ch != nil {
+				expected = member.PermissionsIn(tt.g, tt.ch)
+			}
+			if e.Permissions != expected {
+				t.Errorf("expected permissions %#x; got %#x", expected, e.Permissions)
+			}
+
+			if len(e.Decisions) != len(permissionNames) {
+				t.Fatalf("expected %d decisions; got %d", len(permissionNames), len(e.Decisions))
+			}
+			for _, d := range e.Decisions {
+				if e.Permissions != PermissionAdministrator && d.Allowed != PermissionsContains(e.Permissions, d.Permission) {
+					t.Errorf("%s: decision does not match permissions %#x", d, e.Permissions)
+				}
+
+				want, ok := tt.expected[d.Permission]
+				if !ok {
+					continue
+				}
+				want.Permission = d.Permission
+				if d != want {
+					t.Errorf("expected %q; got %q", want, d)
+				}
+			}
+		})
+	}
+}
+
+func TestPermissionsExplanationMissing(t *testing.T) {
+	g := &Guild{ID: "g1", Roles: []Role{{ID: "g1", Permissions: PermissionViewChannel | PermissionSendMessages}}}
+	m := &GuildMember{User: &User{ID: "u1"}}
+
+	e := m.ExplainPermissionsIn(g, nil)
+	missing := e.Missing(PermissionViewChannel | PermissionSendMessages | PermissionEmbedLinks | PermissionRequestToSpeak)
+	if len(missing) != 2 || missing[0].Permission != PermissionEmbedLinks || missing[1].Permission != PermissionRequestToSpeak {
+		t.Errorf("expected Embed Links and Request to Speak to be missing; got %v", missing)
+	}
+	if s := missing[0].String(); s != "Embed Links: denied, no role grants it" {
+		t.Errorf("unexpected explanation %q", s)
+	}
+}
@@ -0,0 +1,82 @@
+package discord
+
+// ScheduledEventPrivacyLevel is the privacy level of a ScheduledEvent.
+type ScheduledEventPrivacyLevel int
+
+const (
+	// ScheduledEventPrivacyLevelGuildOnly means the scheduled
+	// event is only accessible to guild members.
+	ScheduledEventPrivacyLevelGuildOnly ScheduledEventPrivacyLevel = 2
+)
+
+// ScheduledEventStatus is the status of a ScheduledEvent.
+// Once it is completed or canceled, the status of an event can not be changed anymore.
+type ScheduledEventStatus int
+
+const (
+	ScheduledEventStatusScheduled ScheduledEventStatus = 1
+	ScheduledEventStatusActive    ScheduledEventStatus = 2
+	ScheduledEventStatusCompleted ScheduledEventStatus = 3
+	ScheduledEventStatusCanceled  ScheduledEventStatus = 4
+)
+
+// ScheduledEventEntityType is the type of entity a ScheduledEvent is for.
+type ScheduledEventEntityType int
+
+const (
+	// ScheduledEventEntityTypeStageInstance is for events happening in a stage channel.
+	ScheduledEventEntityTypeStageInstance ScheduledEventEntityType = 1
+	// ScheduledEventEntityTypeVoice is for events happening in a voice channel.
+	ScheduledEventEntityTypeVoice ScheduledEventEntityType = 2
+	// ScheduledEventEntityTypeExternal is for events happening somewhere else,
+	// they must have a location and an end time.
+	ScheduledEventEntityTypeExternal ScheduledEventEntityType = 3
+)
+
+// ScheduledEvent is an event scheduled in a guild.
+type ScheduledEvent struct {
+	ID      string `json:"id"`
+	GuildID string `json:"guild_id"`
+	// ID of the channel the event will be hosted in, empty
+	// if its entity type is ScheduledEventEntityTypeExternal.
+	ChannelID string `json:"channel_id"`
+	// ID of the user that created the event.
+	CreatorID   string `json:"creator_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Time the event will start.
+	ScheduledStartTime Time `json:"scheduled_start_time"`
+	// Time the event will end, required if its entity
+	// type is ScheduledEventEntityTypeExternal.
+	ScheduledEndTime Time                       `json:"scheduled_end_time"`
+	PrivacyLevel     ScheduledEventPrivacyLevel `json:"privacy_level"`
+	Status           ScheduledEventStatus       `json:"status"`
+	EntityType       ScheduledEventEntityType   `json:"entity_type"`
+	// ID of the entity associated with the event, such as a stage instance.
+	EntityID       string                        `json:"entity_id"`
+	EntityMetadata *ScheduledEventEntityMetadata `json:"entity_metadata"`
+	// The user that created the event.
+	Creator *User `json:"creator"`
+	// Number of users subscribed to the event.
+	// Only set when requested with the user count.
+	UserCount int `json:"user_count"`
+	// Cover image hash of the event.
+	Image string `json:"image"`
+}
+
+// ScheduledEventEntityMetadata holds additional
+// metadata about the entity of a ScheduledEvent.
+type ScheduledEventEntityMetadata struct {
+	// Location of the event (1-100 characters), for
+	// events of type ScheduledEventEntityTypeExternal.
+	Location string `json:"location,omitempty"`
+}
+
+// ScheduledEventUser is a user subscribed to a ScheduledEvent.
+type ScheduledEventUser struct {
+	ScheduledEventID string `json:"guild_scheduled_event_id"`
+	User             *User  `json:"user"`
+	// Guild member data for this user, if it was
+	// requested and the user is a member of the guild.
+	Member *GuildMember `json:"member"`
+}
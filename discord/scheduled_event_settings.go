@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"time"
+
+	"github.com/skwair/harmony/optional"
+)
+
+// ScheduledEventSettings describes a scheduled event creation or update.
+type ScheduledEventSettings struct {
+	ChannelID          *optional.String              `json:"channel_id,omitempty"`
+	EntityMetadata     *ScheduledEventEntityMetadata `json:"entity_metadata,omitempty"`
+	Name               *optional.String              `json:"name,omitempty"`
+	PrivacyLevel       *optional.Int                 `json:"privacy_level,omitempty"`
+	ScheduledStartTime *optional.String              `json:"scheduled_start_time,omitempty"`
+	ScheduledEndTime   *optional.String              `json:"scheduled_end_time,omitempty"`
+	Description        *optional.String              `json:"description,omitempty"`
+	EntityType         *optional.Int                 `json:"entity_type,omitempty"`
+	Status             *optional.Int                 `json:"status,omitempty"`
+	Image              *optional.String              `json:"image,omitempty"`
+}
+
+// ScheduledEventSetting is a function that configures a scheduled event.
+type ScheduledEventSetting func(*ScheduledEventSettings)
+
+// NewScheduledEventSettings returns new ScheduledEventSettings to create or modify a scheduled event.
+// Creating an event requires at least a name, a start time, an entity type and either a channel,
+// or a location and an end time for external events. When creating an event, the privacy level
+// defaults to ScheduledEventPrivacyLevelGuildOnly.
+func NewScheduledEventSettings(opts ...ScheduledEventSetting) *ScheduledEventSettings {
+	s := &ScheduledEventSettings{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithScheduledEventName sets the name of a scheduled event (1-100 characters).
+func WithScheduledEventName(name string) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.Name = optional.NewString(name)
+	}
+}
+
+// WithScheduledEventDescription sets the description of a scheduled event (1-1000 characters).
+func WithScheduledEventDescription(description string) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.Description = optional.NewString(description)
+	}
+}
+
+// WithScheduledEventChannel sets the stage or voice channel a scheduled event will be hosted in.
+func WithScheduledEventChannel(id string) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.ChannelID = optional.NewString(id)
+	}
+}
+
+// WithScheduledEventLocation sets the location of an external scheduled event (1-100 characters).
+// When modifying an event to make it external, its channel is removed.
+func WithScheduledEventLocation(location string) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.EntityMetadata = &ScheduledEventEntityMetadata{Location: location}
+		s.ChannelID = optional.NewNilString()
+	}
+}
+
+// WithScheduledEventEntityType sets the entity type of a scheduled event.
+func WithScheduledEventEntityType(t ScheduledEventEntityType) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.EntityType = optional.NewInt(int(t))
+	}
+}
+
+// WithScheduledEventPrivacyLevel sets the privacy level of a scheduled event.
+func WithScheduledEventPrivacyLevel(level ScheduledEventPrivacyLevel) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.PrivacyLevel = optional.NewInt(int(level))
+	}
+}
+
+// WithScheduledEventStartTime sets the time a scheduled event will start.
+func WithScheduledEventStartTime(t time.Time) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.ScheduledStartTime = optional.NewString(t.Format(time.RFC3339))
+	}
+}
+
+// WithScheduledEventEndTime sets the time a scheduled event will end.
+func WithScheduledEventEndTime(t time.Time) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.ScheduledEndTime = optional.NewString(t.Format(time.RFC3339))
+	}
+}
+
+// WithScheduledEventStatus sets the status of a scheduled event. Scheduled events can
+// only go from scheduled to active or canceled, and from active to completed.
+func WithScheduledEventStatus(status ScheduledEventStatus) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.Status = optional.NewInt(int(status))
+	}
+}
+
+// WithScheduledEventImage sets the cover image of a scheduled event.
+// image is a data URI scheme that supports JPG, GIF and PNG formats, see
+// https://discord.com/developers/docs/reference#image-data
+// for more information.
+func WithScheduledEventImage(image string) ScheduledEventSetting {
+	return func(s *ScheduledEventSettings) {
+		s.Image = optional.NewString(image)
+	}
+}
@@ -0,0 +1,25 @@
+package discord
+
+// StagePrivacyLevel is the privacy level of a StageInstance.
+type StagePrivacyLevel int
+
+const (
+	// StagePrivacyLevelPublic means the stage instance is visible publicly.
+	//
+	// Deprecated: Discord does not allow creating public stage instances anymore.
+	StagePrivacyLevelPublic StagePrivacyLevel = 1
+	// StagePrivacyLevelGuildOnly means the stage instance is only visible to guild members.
+	StagePrivacyLevelGuildOnly StagePrivacyLevel = 2
+)
+
+// StageInstance holds information about a live stage.
+type StageInstance struct {
+	ID        string `json:"id"`
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	// Topic of the stage instance (1-120 characters).
+	Topic        string            `json:"topic"`
+	PrivacyLevel StagePrivacyLevel `json:"privacy_level"`
+	// ID of the scheduled event for this stage instance, if any.
+	ScheduledEventID string `json:"guild_scheduled_event_id"`
+}
@@ -0,0 +1,56 @@
+package discord
+
+import "github.com/skwair/harmony/optional"
+
+// StageInstanceSettings describes a stage instance creation or update.
+type StageInstanceSettings struct {
+	Topic        *optional.String `json:"topic,omitempty"`
+	PrivacyLevel *optional.Int    `json:"privacy_level,omitempty"`
+	// Only used when creating a stage instance.
+	SendStartNotification *optional.Bool   `json:"send_start_notification,omitempty"`
+	ScheduledEventID      *optional.String `json:"guild_scheduled_event_id,omitempty"`
+}
+
+// StageInstanceSetting is a function that configures a stage instance.
+type StageInstanceSetting func(*StageInstanceSettings)
+
+// NewStageInstanceSettings returns new StageInstanceSettings to create or modify a stage
+// instance. Creating a stage instance requires at least a topic.
+func NewStageInstanceSettings(opts ...StageInstanceSetting) *StageInstanceSettings {
+	s := &StageInstanceSettings{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithStageInstanceTopic sets the topic of a stage instance (1-120 characters).
+func WithStageInstanceTopic(topic string) StageInstanceSetting {
+	return func(s *StageInstanceSettings) {
+		s.Topic = optional.NewString(topic)
+	}
+}
+
+// WithStageInstancePrivacyLevel sets the privacy level of a stage instance.
+func WithStageInstancePrivacyLevel(level StagePrivacyLevel) StageInstanceSetting {
+	return func(s *StageInstanceSettings) {
+		s.PrivacyLevel = optional.NewInt(int(level))
+	}
+}
+
+// WithStageInstanceStartNotification notifies @everyone that a stage instance
+// has started. Requires the 'MENTION_EVERYONE' permission.
+func WithStageInstanceStartNotification() StageInstanceSetting {
+	return func(s *StageInstanceSettings) {
+		s.SendStartNotification = optional.NewBool(true)
+	}
+}
+
+// WithStageInstanceScheduledEvent associates a stage instance with a scheduled event.
+func WithStageInstanceScheduledEvent(id string) StageInstanceSetting {
+	return func(s *StageInstanceSettings) {
+		s.ScheduledEventID = optional.NewString(id)
+	}
+}
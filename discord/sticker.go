@@ -0,0 +1,52 @@
+package discord
+
+// StickerType is the type of a Sticker.
+type StickerType int
+
+const (
+	// StickerTypeStandard is an official sticker in a pack, part of Nitro or in a removed purchasable pack.
+	StickerTypeStandard StickerType = 1
+	// StickerTypeGuild is a sticker uploaded to a guild.
+	StickerTypeGuild StickerType = 2
+)
+
+// Sticker is a sticker that can be sent in messages.
+type Sticker struct {
+	ID string `json:"id"`
+	// For standard stickers, ID of the pack the sticker is from.
+	PackID      string `json:"pack_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Autocomplete/suggestion tags for the sticker (max 200 characters).
+	Tags       string        `json:"tags"`
+	Type       StickerType   `json:"type"`
+	FormatType StickerFormat `json:"format_type"`
+	// Whether this guild sticker can be used, may be false due to loss of Server Boosts.
+	Available bool   `json:"available"`
+	GuildID   string `json:"guild_id"`
+	// The user that uploaded the guild sticker.
+	User *User `json:"user"`
+	// The standard sticker's sort order within its pack.
+	SortValue int `json:"sort_value"`
+}
+
+// StickerItem is the smallest amount of data required to render a sticker.
+type StickerItem struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	FormatType StickerFormat `json:"format_type"`
+}
+
+// StickerPack is a pack of standard stickers.
+type StickerPack struct {
+	ID       string    `json:"id"`
+	Stickers []Sticker `json:"stickers"`
+	Name     string    `json:"name"`
+	// ID of the pack's SKU.
+	SKUID string `json:"sku_id"`
+	// ID of a sticker in the pack which is shown as the pack's icon.
+	CoverStickerID string `json:"cover_sticker_id"`
+	Description    string `json:"description"`
+	// ID of the sticker pack's banner image.
+	BannerAssetID string `json:"banner_asset_id"`
+}
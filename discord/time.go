@@ -21,6 +21,12 @@ func (t Time) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (t *Time) UnmarshalJSON(data []byte) error {
+	// Zero values are marshaled as empty strings.
+	if string(data) == `""` {
+		t.Time = time.Time{}
+		return nil
+	}
+
 	var ts time.Time
 	if err := ts.UnmarshalJSON(data); err != nil {
 		return err
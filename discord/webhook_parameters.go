@@ -13,8 +13,15 @@ type WebhookParameters struct {
 	Username  *optional.String `json:"username,omitempty"`
 	AvatarURL *optional.String `json:"avatar_url,omitempty"`
 	TTS       *optional.Bool   `json:"tts,omitempty"`
-	Embeds    []MessageEmbed   `json:"embeds,omitempty"`
+	Embeds    []MessageEmbed   `json:"embeds,omitempty"` // Up to 10 embeds.
 	Files     []File           `json:"-"`
+
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           *optional.Int    `json:"flags,omitempty"`
+
+	// ThreadID is the ID of the thread the message should be sent
+	// in or edited from. It is sent as a query parameter.
+	ThreadID string `json:"-"`
 }
 
 // Bytes implements the rest.MultipartPayload interface so WebhookParameters can be used as
@@ -78,3 +85,27 @@ func WithWebhookFiles(files []File) WebhookParameter {
 		s.Files = files
 	}
 }
+
+// WithWebhookAllowedMentions sets which mentions in the content of a webhook message
+// actually notify their targets. If not set, all mentions are parsed from the content.
+func WithWebhookAllowedMentions(mentions *AllowedMentions) WebhookParameter {
+	return func(s *WebhookParameters) {
+		s.AllowedMentions = mentions
+	}
+}
+
+// WithWebhookFlags sets the flags of a webhook message. Only MessageFlagSuppressEmbeds
+// and MessageFlagSuppressNotifications can be set.
+func WithWebhookFlags(flags MessageFlag) WebhookParameter {
+	return func(s *WebhookParameters) {
+		s.Flags = optional.NewInt(int(flags))
+	}
+}
+
+// WithWebhookThread sets the thread in which a webhook message is sent or edited.
+// The thread must be in the channel of the webhook.
+func WithWebhookThread(id string) WebhookParameter {
+	return func(s *WebhookParameters) {
+		s.ThreadID = id
+	}
+}
@@ -0,0 +1,437 @@
+/*
+Package diskstore provides a harmony.StateStore that keeps the State on disk
+instead of in memory.
+
+Entities are appended to a single log file and only an index of their location
+in this file is kept in memory. Overwritten and deleted entities are reclaimed
+by periodically rewriting the log file, see Store.Compact.
+
+Writes are not synced to disk as they happen, since the State can always be
+rebuilt from the Gateway. If the process or the system crashes, the most recent
+writes can be lost, but records that were only partially written are detected
+and discarded the next time the store is opened. Use Store.Sync to make sure
+previous writes are persisted. Closing the store syncs it as well.
+
+	store, err := diskstore.Open("state.db")
+	if err != nil {
+		// Handle error
+	}
+	defer store.Close()
+
+	client, err := harmony.NewClient(token, harmony.WithStateStore(store))
+*/
+package diskstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	opPut byte = iota + 1
+	opDelete
+)
+
+// headerSize is the size of a record header: a CRC32 checksum of the rest of the
+// record, an operation, the length of the bucket, the key and the value.
+const headerSize = 4 + 1 + 2 + 2 + 4
+
+// defaultCompactThreshold is the number of bytes used by stale records
+// after which the log file is automatically compacted.
+const defaultCompactThreshold = 64 << 20
+
+// ErrClosed is returned when using a Store that has been closed.
+var ErrClosed = errors.New("diskstore: store is closed")
+
+// entry is the location of a value in the log file.
+type entry struct {
+	offset int64 // Offset of the record.
+	size   int64 // Size of the whole record.
+	vsize  int64 // Size of the value, at the end of the record.
+}
+
+// Store is a harmony.StateStore that keeps entities on disk.
+// It is safe for concurrent use.
+type Store struct {
+	mu sync.RWMutex
+
+	path string
+	f    *os.File
+	// Offset at which the next record is written.
+	end int64
+
+	// Location of values by bucket and key.
+	index map[string]map[string]entry
+	// Guild ID of guild channels, by channel ID,
+	// and channel IDs by guild ID.
+	channelGuilds map[string]string
+	guildChannels map[string]map[string]struct{}
+
+	// Bytes used by records that are not live anymore.
+	stale            int64
+	compactThreshold int64
+}
+
+// Open opens the store at the given path, creating it if it does not exist.
+// Entities previously stored at this path are loaded.
+func Open(path string) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		path:             path,
+		f:                f,
+		compactThreshold: defaultCompactThreshold,
+	}
+
+	if err = s.load(); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("diskstore: load %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Close syncs and closes the store. It must not be used afterwards.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	err := s.f.Sync()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	s.f = nil
+	return err
+}
+
+// Sync commits the content of the store to stable storage.
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	return s.f.Sync()
+}
+
+// Compact rewrites the log file so that it only contains live entities.
+// It is automatically called when too much space is used by stale entities.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	return s.compact()
+}
+
+// load builds the index by replaying the log file. If the last record is
+// incomplete or corrupted (because of a crash while writing it for example),
+// it is discarded.
+func (s *Store) load() error {
+	s.index = make(map[string]map[string]entry)
+	s.channelGuilds = make(map[string]string)
+	s.guildChannels = make(map[string]map[string]struct{})
+	s.end = 0
+	s.stale = 0
+
+	fi, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	size := fi.Size()
+
+	for {
+		op, bucket, key, e, err := s.readRecord(s.end, size)
+		if err == io.EOF || err == io.ErrUnexpectedEOF || err == errChecksum {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		s.apply(op, bucket, key, e)
+		s.end += e.size
+	}
+
+	// Discard whatever follows the last valid record.
+	if err := s.f.Truncate(s.end); err != nil {
+		return err
+	}
+
+	// Rebuild the guild channels index.
+	for id, e := range s.index[bucketChannels] {
+		var ch struct {
+			GuildID string `json:"guild_id"`
+		}
+		if err := s.read(e, &ch); err != nil {
+			return err
+		}
+		s.indexChannel(id, ch.GuildID)
+	}
+
+	return nil
+}
+
+// indexChannel records the guild the given channel belongs to. An empty
+// guild ID removes the channel from the index.
+func (s *Store) indexChannel(id, guildID string) {
+	if old, ok := s.channelGuilds[id]; ok {
+		delete(s.guildChannels[old], id)
+		if len(s.guildChannels[old]) == 0 {
+			delete(s.guildChannels, old)
+		}
+		delete(s.channelGuilds, id)
+	}
+
+	if guildID == "" {
+		return
+	}
+
+	s.channelGuilds[id] = guildID
+	if s.guildChannels[guildID] == nil {
+		s.guildChannels[guildID] = make(map[string]struct{})
+	}
+	s.guildChannels[guildID][id] = struct{}{}
+}
+
+var errChecksum = errors.New("diskstore: checksum mismatch")
+
+// readRecord reads the record at the given offset of a log file of the given size.
+func (s *Store) readRecord(offset, size int64) (op byte, bucket, key string, e entry, err error) {
+	var header [headerSize]byte
+	if _, err = s.f.ReadAt(header[:], offset); err != nil {
+		return 0, "", "", entry{}, err
+	}
+
+	op = header[4]
+	blen := int64(binary.BigEndian.Uint16(header[5:]))
+	klen := int64(binary.BigEndian.Uint16(header[7:]))
+	vlen := int64(binary.BigEndian.Uint32(header[9:]))
+
+	// The header is not verified yet, make sure it does not
+	// make us allocate more than what is left in the file.
+	if offset+headerSize+blen+klen+vlen > size {
+		return 0, "", "", entry{}, io.ErrUnexpectedEOF
+	}
+
+	body := make([]byte, blen+klen+vlen)
+	if _, err = s.f.ReadAt(body, offset+headerSize); err != nil {
+		return 0, "", "", entry{}, err
+	}
+
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:])
+	_, _ = crc.Write(body)
+	if crc.Sum32() != binary.BigEndian.Uint32(header[:4]) {
+		return 0, "", "", entry{}, errChecksum
+	}
+
+	e = entry{
+		offset: offset,
+		size:   headerSize + int64(len(body)),
+		vsize:  vlen,
+	}
+	return op, string(body[:blen]), string(body[blen : blen+klen]), e, nil
+}
+
+// apply updates the index given a record.
+func (s *Store) apply(op byte, bucket, key string, e entry) {
+	b := s.index[bucket]
+	if old, ok := b[key]; ok {
+		s.stale += old.size
+		delete(b, key)
+	}
+
+	switch op {
+	case opPut:
+		if b == nil {
+			b = make(map[string]entry)
+			s.index[bucket] = b
+		}
+		b[key] = e
+	case opDelete:
+		// The tombstone itself is stale.
+		s.stale += e.size
+		if len(b) == 0 {
+			delete(s.index, bucket)
+		}
+	}
+}
+
+// writeRecord appends a record to the log file and updates the index.
+func (s *Store) writeRecord(op byte, bucket, key string, value []byte) error {
+	if s.f == nil {
+		return ErrClosed
+	}
+	if len(bucket) > math.MaxUint16 || len(key) > math.MaxUint16 || int64(len(value)) > math.MaxUint32 {
+		return fmt.Errorf("diskstore: record too large (bucket: %d, key: %d, value: %d bytes)", len(bucket), len(key), len(value))
+	}
+
+	rec := make([]byte, headerSize+len(bucket)+len(key)+len(value))
+	rec[4] = op
+	binary.BigEndian.PutUint16(rec[5:], uint16(len(bucket)))
+	binary.BigEndian.PutUint16(rec[7:], uint16(len(key)))
+	binary.BigEndian.PutUint32(rec[9:], uint32(len(value)))
+	n := headerSize
+	n += copy(rec[n:], bucket)
+	n += copy(rec[n:], key)
+	copy(rec[n:], value)
+	binary.BigEndian.PutUint32(rec, crc32.ChecksumIEEE(rec[4:]))
+
+	if _, err := s.f.WriteAt(rec, s.end); err != nil {
+		return err
+	}
+
+	e := entry{offset: s.end, size: int64(len(rec)), vsize: int64(len(value))}
+	s.apply(op, bucket, key, e)
+	s.end += e.size
+
+	if s.stale > s.compactThreshold && s.stale > s.end/2 {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact rewrites the log file with live records only.
+func (s *Store) compact() error {
+	tmpPath := s.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	var (
+		end   int64
+		index = make(map[string]map[string]entry, len(s.index))
+	)
+	for bucket, b := range s.index {
+		index[bucket] = make(map[string]entry, len(b))
+		for key, e := range b {
+			rec := make([]byte, e.size)
+			if _, err = s.f.ReadAt(rec, e.offset); err != nil {
+				_ = tmp.Close()
+				return err
+			}
+			if _, err = tmp.WriteAt(rec, end); err != nil {
+				_ = tmp.Close()
+				return err
+			}
+			index[bucket][key] = entry{offset: end, size: e.size, vsize: e.vsize}
+			end += e.size
+		}
+	}
+
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	// Persist the rename itself. Not all platforms support syncing
+	// directories and the new file is complete anyway, so this is
+	// done on a best effort basis.
+	syncDir(filepath.Dir(s.path))
+
+	_ = s.f.Close()
+	s.f = tmp
+	s.end = end
+	s.index = index
+	s.stale = 0
+	return nil
+}
+
+// syncDir syncs the directory at the given path, ignoring errors.
+func syncDir(path string) {
+	d, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+// read decodes the value of the given entry into v.
+func (s *Store) read(e entry, v interface{}) error {
+	value := make([]byte, e.vsize)
+	if _, err := s.f.ReadAt(value, e.offset+e.size-e.vsize); err != nil {
+		return err
+	}
+	return json.Unmarshal(value, v)
+}
+
+// get decodes the value stored in the given bucket at the given key into v.
+// It reports whether the value was found.
+func (s *Store) get(bucket, key string, v interface{}) (bool, error) {
+	if s.f == nil {
+		return false, ErrClosed
+	}
+
+	e, ok := s.index[bucket][key]
+	if !ok {
+		return false, nil
+	}
+	return true, s.read(e, v)
+}
+
+// put stores v in the given bucket at the given key.
+func (s *Store) put(bucket, key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.writeRecord(opPut, bucket, key, value)
+}
+
+// delete deletes the value stored in the given bucket at the given key, if any.
+func (s *Store) delete(bucket, key string) error {
+	if _, ok := s.index[bucket][key]; !ok {
+		return nil
+	}
+	return s.writeRecord(opDelete, bucket, key, nil)
+}
+
+// deleteBucket deletes all values stored in the given bucket.
+func (s *Store) deleteBucket(bucket string) error {
+	for key := range s.index[bucket] {
+		if err := s.delete(bucket, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// each calls fn with the key and entry of every value stored in the given bucket.
+func (s *Store) each(bucket string, fn func(key string, e entry) error) error {
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	for key, e := range s.index[bucket] {
+		if err := fn(key, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
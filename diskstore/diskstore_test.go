@@ -0,0 +1,317 @@
+package diskstore
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+func openTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "state.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("could not open store: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s, path
+}
+
+func reopen(t *testing.T, s *Store, path string) *Store {
+	t.Helper()
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("could not close store: %v", err)
+	}
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("could not reopen store: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("could not stat store: %v", err)
+	}
+	return fi.Size()
+}
+
+func TestRoundTrip(t *testing.T) {
+	s, path := openTestStore(t)
+
+	channelID := "c1"
+	must(t, s.SetUser(&discord.User{ID: "u1", Username: "foo"}))
+	must(t, s.SetGuild(&discord.Guild{ID: "g1", Name: "guild"}))
+	must(t, s.SetMember("g1", &discord.GuildMember{User: &discord.User{ID: "u1"}, Nick: "nick"}))
+	must(t, s.SetChannel(&discord.Channel{ID: "c1", GuildID: "g1", Name: "general"}))
+	must(t, s.SetChannel(&discord.Channel{ID: "dm1"}))
+	must(t, s.SetRole("g1", &discord.Role{ID: "r1", Name: "role"}))
+	must(t, s.SetPresence(&discord.Presence{User: &discord.User{ID: "u1"}, GuildID: "g1", Status: "online"}))
+	must(t, s.SetVoiceState(&voice.State{GuildID: "g1", UserID: "u1", ChannelID: &channelID}))
+
+	check := func(t *testing.T, s *Store) {
+		if u, err := s.User("u1"); err != nil || u == nil || u.Username != "foo" {
+			t.Errorf("User: got %+v, %v", u, err)
+		}
+		if g, err := s.Guild("g1"); err != nil || g == nil || g.Name != "guild" {
+			t.Errorf("Guild: got %+v, %v", g, err)
+		}
+		if m, err := s.Member("g1", "u1"); err != nil || m == nil || m.Nick != "nick" {
+			t.Errorf("Member: got %+v, %v", m, err)
+		}
+		if ch, err := s.Channel("c1"); err != nil || ch == nil || ch.Name != "general" {
+			t.Errorf("Channel: got %+v, %v", ch, err)
+		}
+		if chs, err := s.GuildChannels("g1"); err != nil || len(chs) != 1 || chs[0].ID != "c1" {
+			t.Errorf("GuildChannels: got %+v, %v", chs, err)
+		}
+		if chs, err := s.Channels(); err != nil || len(chs) != 2 {
+			t.Errorf("Channels: got %d channels, %v", len(chs), err)
+		}
+		if r, err := s.Role("g1", "r1"); err != nil || r == nil || r.Name != "role" {
+			t.Errorf("Role: got %+v, %v", r, err)
+		}
+		if p, err := s.Presence("u1"); err != nil || p == nil || p.Status != "online" {
+			t.Errorf("Presence: got %+v, %v", p, err)
+		}
+		if vs, err := s.VoiceState("g1", "u1"); err != nil || vs == nil || *vs.ChannelID != "c1" {
+			t.Errorf("VoiceState: got %+v, %v", vs, err)
+		}
+		if u, err := s.User("unknown"); err != nil || u != nil {
+			t.Errorf("User: expected nil for unknown user; got %+v, %v", u, err)
+		}
+	}
+
+	t.Run("open", func(t *testing.T) { check(t, s) })
+	s = reopen(t, s, path)
+	t.Run("reopen", func(t *testing.T) { check(t, s) })
+}
+
+func TestDelete(t *testing.T) {
+	s, path := openTestStore(t)
+
+	must(t, s.SetUser(&discord.User{ID: "u1"}))
+	must(t, s.SetUser(&discord.User{ID: "u2"}))
+	must(t, s.SetGuild(&discord.Guild{ID: "g1"}))
+	must(t, s.SetMember("g1", &discord.GuildMember{User: &discord.User{ID: "u1"}}))
+	must(t, s.SetChannel(&discord.Channel{ID: "c1", GuildID: "g1"}))
+	must(t, s.SetRole("g1", &discord.Role{ID: "r1"}))
+
+	must(t, s.DeleteUser("u1"))
+	// Deleting something that does not exist is a no-op.
+	must(t, s.DeleteUser("unknown"))
+	must(t, s.DeleteGuild("g1"))
+
+	check := func(t *testing.T, s *Store) {
+		users, err := s.Users()
+		if err != nil || len(users) != 1 || users["u2"] == nil {
+			t.Errorf("Users: expected only u2; got %v, %v", users, err)
+		}
+		if g, err := s.Guild("g1"); err != nil || g != nil {
+			t.Errorf("Guild: expected deleted guild; got %+v, %v", g, err)
+		}
+		if m, err := s.Members("g1"); err != nil || len(m) != 0 {
+			t.Errorf("Members: expected no member; got %+v, %v", m, err)
+		}
+		if ch, err := s.Channel("c1"); err != nil || ch != nil {
+			t.Errorf("Channel: expected deleted channel; got %+v, %v", ch, err)
+		}
+		if chs, err := s.GuildChannels("g1"); err != nil || len(chs) != 0 {
+			t.Errorf("GuildChannels: expected no channel; got %+v, %v", chs, err)
+		}
+		if r, err := s.Roles("g1"); err != nil || len(r) != 0 {
+			t.Errorf("Roles: expected no role; got %+v, %v", r, err)
+		}
+	}
+
+	t.Run("open", func(t *testing.T) { check(t, s) })
+	s = reopen(t, s, path)
+	t.Run("reopen", func(t *testing.T) { check(t, s) })
+}
+
+func TestCorruptedTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, f *os.File, lastRecord, size int64)
+	}{
+		{
+			name: "torn write",
+			corrupt: func(t *testing.T, f *os.File, lastRecord, size int64) {
+				must(t, f.Truncate(size-3))
+			},
+		},
+		{
+			name: "torn header",
+			corrupt: func(t *testing.T, f *os.File, lastRecord, size int64) {
+				must(t, f.Truncate(lastRecord+headerSize/2))
+			},
+		},
+		{
+			name: "checksum mismatch",
+			corrupt: func(t *testing.T, f *os.File, lastRecord, size int64) {
+				_, err := f.WriteAt([]byte{'X'}, size-2)
+				must(t, err)
+			},
+		},
+		{
+			name: "huge value length",
+			corrupt: func(t *testing.T, f *os.File, lastRecord, size int64) {
+				var vlen [4]byte
+				binary.BigEndian.PutUint32(vlen[:], 0xFFFFFFFF)
+				_, err := f.WriteAt(vlen[:], lastRecord+9)
+				must(t, err)
+			},
+		},
+		{
+			name: "garbage appended",
+			corrupt: func(t *testing.T, f *os.File, lastRecord, size int64) {
+				_, err := f.WriteAt([]byte("garbage that is not a record"), size)
+				must(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, path := openTestStore(t)
+
+			must(t, s.SetUser(&discord.User{ID: "u1", Username: "foo"}))
+			lastRecord := s.end
+			must(t, s.SetUser(&discord.User{ID: "u2", Username: "bar"}))
+			size := s.end
+			must(t, s.Close())
+
+			f, err := os.OpenFile(path, os.O_RDWR, 0)
+			must(t, err)
+			tt.corrupt(t, f, lastRecord, size)
+			must(t, f.Close())
+
+			s, err = Open(path)
+			if err != nil {
+				t.Fatalf("could not open corrupted store: %v", err)
+			}
+			defer s.Close()
+
+			if u, err := s.User("u1"); err != nil || u == nil || u.Username != "foo" {
+				t.Errorf("expected first user to be kept; got %+v, %v", u, err)
+			}
+			if tt.name != "garbage appended" {
+				if u, err := s.User("u2"); err != nil || u != nil {
+					t.Errorf("expected corrupted user to be discarded; got %+v, %v", u, err)
+				}
+			}
+
+			// The corrupted part is truncated and new records can be written.
+			must(t, s.SetUser(&discord.User{ID: "u3", Username: "baz"}))
+			s = reopen(t, s, path)
+			if u, err := s.User("u3"); err != nil || u == nil || u.Username != "baz" {
+				t.Errorf("expected user written after recovery; got %+v, %v", u, err)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	s, path := openTestStore(t)
+
+	for i := 0; i < 100; i++ {
+		must(t, s.SetUser(&discord.User{ID: "u1", Username: "foo"}))
+	}
+	must(t, s.SetUser(&discord.User{ID: "u2", Username: "bar"}))
+	must(t, s.DeleteUser("u2"))
+	must(t, s.SetChannel(&discord.Channel{ID: "c1", GuildID: "g1"}))
+
+	before := fileSize(t, path)
+	if s.stale == 0 {
+		t.Fatal("expected stale records")
+	}
+
+	must(t, s.Compact())
+
+	after := fileSize(t, path)
+	if after >= before {
+		t.Errorf("expected compaction to shrink the log file; got %d bytes, had %d", after, before)
+	}
+	if s.stale != 0 {
+		t.Errorf("expected no stale records after compaction; got %d bytes", s.stale)
+	}
+	if after != s.end {
+		t.Errorf("expected log file size (%d) to match end offset (%d)", after, s.end)
+	}
+
+	check := func(t *testing.T, s *Store) {
+		users, err := s.Users()
+		if err != nil {
+			t.Fatalf("Users: %v", err)
+		}
+		var ids []string
+		for id := range users {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if len(ids) != 1 || ids[0] != "u1" || users["u1"].Username != "foo" {
+			t.Errorf("expected only u1; got %v", ids)
+		}
+		if chs, err := s.GuildChannels("g1"); err != nil || len(chs) != 1 {
+			t.Errorf("GuildChannels: expected one channel; got %+v, %v", chs, err)
+		}
+	}
+
+	t.Run("compacted", func(t *testing.T) { check(t, s) })
+
+	// Writes after compaction go to the new log file.
+	must(t, s.SetUser(&discord.User{ID: "u1", Username: "foo"}))
+	s = reopen(t, s, path)
+	t.Run("reopen", func(t *testing.T) { check(t, s) })
+
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Errorf("expected temporary compaction file to be gone; got %v", err)
+	}
+}
+
+func TestAutoCompact(t *testing.T) {
+	s, _ := openTestStore(t)
+	s.compactThreshold = 1024
+
+	for i := 0; i < 1000; i++ {
+		must(t, s.SetUser(&discord.User{ID: "u1", Username: "foo"}))
+	}
+
+	if s.end > 2*s.compactThreshold {
+		t.Errorf("expected log file to be compacted automatically; it is %d bytes", s.end)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	s, _ := openTestStore(t)
+	must(t, s.Close())
+
+	if err := s.SetUser(&discord.User{ID: "u1"}); err != ErrClosed {
+		t.Errorf("expected ErrClosed; got %v", err)
+	}
+	if _, err := s.User("u1"); err != ErrClosed {
+		t.Errorf("expected ErrClosed; got %v", err)
+	}
+	if err := s.Close(); err != ErrClosed {
+		t.Errorf("expected ErrClosed; got %v", err)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,400 @@
+package diskstore
+
+import (
+	"github.com/skwair/harmony"
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+var _ harmony.StateStore = (*Store)(nil)
+
+// Buckets in which entities are stored. Entities that belong to a guild
+// are stored in a bucket per guild.
+const (
+	bucketUsers       = "users"
+	bucketGuilds      = "guilds"
+	bucketChannels    = "channels"
+	bucketPresences   = "presences"
+	bucketMembers     = "members/"
+	bucketRoles       = "roles/"
+	bucketVoiceStates = "voice_states/"
+)
+
+// User implements harmony.StateStore.
+func (s *Store) User(id string) (*discord.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var u discord.User
+	if ok, err := s.get(bucketUsers, id, &u); !ok || err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Users implements harmony.StateStore.
+func (s *Store) Users() (map[string]*discord.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make(map[string]*discord.User, len(s.index[bucketUsers]))
+	err := s.each(bucketUsers, func(key string, e entry) error {
+		var u discord.User
+		if err := s.read(e, &u); err != nil {
+			return err
+		}
+		users[key] = &u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetUser implements harmony.StateStore.
+func (s *Store) SetUser(u *discord.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketUsers, u.ID, u)
+}
+
+// DeleteUser implements harmony.StateStore.
+func (s *Store) DeleteUser(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(bucketUsers, id)
+}
+
+// Guild implements harmony.StateStore.
+func (s *Store) Guild(id string) (*discord.Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var g discord.Guild
+	if ok, err := s.get(bucketGuilds, id, &g); !ok || err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Guilds implements harmony.StateStore.
+func (s *Store) Guilds() (map[string]*discord.Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	guilds := make(map[string]*discord.Guild, len(s.index[bucketGuilds]))
+	err := s.each(bucketGuilds, func(key string, e entry) error {
+		var g discord.Guild
+		if err := s.read(e, &g); err != nil {
+			return err
+		}
+		guilds[key] = &g
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+// SetGuild implements harmony.StateStore.
+func (s *Store) SetGuild(g *discord.Guild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketGuilds, g.ID, g)
+}
+
+// DeleteGuild implements harmony.StateStore.
+func (s *Store) DeleteGuild(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for chID := range s.guildChannels[id] {
+		if err := s.deleteChannel(chID); err != nil {
+			return err
+		}
+	}
+
+	for _, bucket := range []string{bucketMembers + id, bucketRoles + id, bucketVoiceStates + id} {
+		if err := s.deleteBucket(bucket); err != nil {
+			return err
+		}
+	}
+
+	return s.delete(bucketGuilds, id)
+}
+
+// Member implements harmony.StateStore.
+func (s *Store) Member(guildID, userID string) (*discord.GuildMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var m discord.GuildMember
+	if ok, err := s.get(bucketMembers+guildID, userID, &m); !ok || err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Members implements harmony.StateStore.
+func (s *Store) Members(guildID string) ([]discord.GuildMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := make([]discord.GuildMember, 0, len(s.index[bucketMembers+guildID]))
+	err := s.each(bucketMembers+guildID, func(_ string, e entry) error {
+		var m discord.GuildMember
+		if err := s.read(e, &m); err != nil {
+			return err
+		}
+		members = append(members, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// SetMember implements harmony.StateStore.
+func (s *Store) SetMember(guildID string, m *discord.GuildMember) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketMembers+guildID, m.User.ID, m)
+}
+
+// DeleteMember implements harmony.StateStore.
+func (s *Store) DeleteMember(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(bucketMembers+guildID, userID)
+}
+
+// Channel implements harmony.StateStore.
+func (s *Store) Channel(id string) (*discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ch discord.Channel
+	if ok, err := s.get(bucketChannels, id, &ch); !ok || err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+// Channels implements harmony.StateStore.
+func (s *Store) Channels() (map[string]*discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := make(map[string]*discord.Channel, len(s.index[bucketChannels]))
+	err := s.each(bucketChannels, func(key string, e entry) error {
+		var ch discord.Channel
+		if err := s.read(e, &ch); err != nil {
+			return err
+		}
+		channels[key] = &ch
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// GuildChannels implements harmony.StateStore.
+func (s *Store) GuildChannels(guildID string) ([]discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := make([]discord.Channel, 0, len(s.guildChannels[guildID]))
+	for chID := range s.guildChannels[guildID] {
+		var ch discord.Channel
+		ok, err := s.get(bucketChannels, chID, &ch)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			channels = append(channels, ch)
+		}
+	}
+	return channels, nil
+}
+
+// SetChannel implements harmony.StateStore.
+func (s *Store) SetChannel(ch *discord.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.put(bucketChannels, ch.ID, ch); err != nil {
+		return err
+	}
+	s.indexChannel(ch.ID, ch.GuildID)
+	return nil
+}
+
+// DeleteChannel implements harmony.StateStore.
+func (s *Store) DeleteChannel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteChannel(id)
+}
+
+func (s *Store) deleteChannel(id string) error {
+	if err := s.delete(bucketChannels, id); err != nil {
+		return err
+	}
+	s.indexChannel(id, "")
+	return nil
+}
+
+// Role implements harmony.StateStore.
+func (s *Store) Role(guildID, roleID string) (*discord.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var r discord.Role
+	if ok, err := s.get(bucketRoles+guildID, roleID, &r); !ok || err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Roles implements harmony.StateStore.
+func (s *Store) Roles(guildID string) ([]discord.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]discord.Role, 0, len(s.index[bucketRoles+guildID]))
+	err := s.each(bucketRoles+guildID, func(_ string, e entry) error {
+		var r discord.Role
+		if err := s.read(e, &r); err != nil {
+			return err
+		}
+		roles = append(roles, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// SetRole implements harmony.StateStore.
+func (s *Store) SetRole(guildID string, r *discord.Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketRoles+guildID, r.ID, r)
+}
+
+// DeleteRole implements harmony.StateStore.
+func (s *Store) DeleteRole(guildID, roleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(bucketRoles+guildID, roleID)
+}
+
+// Presence implements harmony.StateStore.
+func (s *Store) Presence(userID string) (*discord.Presence, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var p discord.Presence
+	if ok, err := s.get(bucketPresences, userID, &p); !ok || err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Presences implements harmony.StateStore.
+func (s *Store) Presences() (map[string]*discord.Presence, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	presences := make(map[string]*discord.Presence, len(s.index[bucketPresences]))
+	err := s.each(bucketPresences, func(key string, e entry) error {
+		var p discord.Presence
+		if err := s.read(e, &p); err != nil {
+			return err
+		}
+		presences[key] = &p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return presences, nil
+}
+
+// SetPresence implements harmony.StateStore.
+func (s *Store) SetPresence(p *discord.Presence) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketPresences, p.User.ID, p)
+}
+
+// DeletePresence implements harmony.StateStore.
+func (s *Store) DeletePresence(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(bucketPresences, userID)
+}
+
+// VoiceState implements harmony.StateStore.
+func (s *Store) VoiceState(guildID, userID string) (*voice.State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var vs voice.State
+	if ok, err := s.get(bucketVoiceStates+guildID, userID, &vs); !ok || err != nil {
+		return nil, err
+	}
+	return &vs, nil
+}
+
+// VoiceStates implements harmony.StateStore.
+func (s *Store) VoiceStates(guildID string) ([]voice.State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]voice.State, 0, len(s.index[bucketVoiceStates+guildID]))
+	err := s.each(bucketVoiceStates+guildID, func(_ string, e entry) error {
+		var vs voice.State
+		if err := s.read(e, &vs); err != nil {
+			return err
+		}
+		states = append(states, vs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SetVoiceState implements harmony.StateStore.
+func (s *Store) SetVoiceState(vs *voice.State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(bucketVoiceStates+vs.GuildID, vs.UserID, vs)
+}
+
+// DeleteVoiceState implements harmony.StateStore.
+func (s *Store) DeleteVoiceState(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(bucketVoiceStates+guildID, userID)
+}
@@ -9,45 +9,58 @@ import (
 )
 
 const (
-	eventHello                      = "HELLO"
-	eventReady                      = "READY"
-	eventResumed                    = "RESUMED"
-	eventInvalidSession             = "INVALID_SESSION"
-	eventChannelCreate              = "CHANNEL_CREATE"
-	eventChannelUpdate              = "CHANNEL_UPDATE"
-	eventChannelDelete              = "CHANNEL_DELETE"
-	eventChannelPinsUpdate          = "CHANNEL_PINS_UPDATE"
-	eventGuildCreate                = "GUILD_CREATE"
-	eventGuildUpdate                = "GUILD_UPDATE"
-	eventGuildDelete                = "GUILD_DELETE"
-	eventGuildBanAdd                = "GUILD_BAN_ADD"
-	eventGuildBanRemove             = "GUILD_BAN_REMOVE"
-	eventGuildEmojisUpdate          = "GUILD_EMOJIS_UPDATE"
-	eventGuildIntegrationsUpdate    = "GUILD_INTEGRATIONS_UPDATE"
-	eventGuildMemberAdd             = "GUILD_MEMBER_ADD"
-	eventGuildMemberRemove          = "GUILD_MEMBER_REMOVE"
-	eventGuildMemberUpdate          = "GUILD_MEMBER_UPDATE"
-	eventGuildMembersChunk          = "GUILD_MEMBERS_CHUNK"
-	eventGuildRoleCreate            = "GUILD_ROLE_CREATE"
-	eventGuildRoleUpdate            = "GUILD_ROLE_UPDATE"
-	eventGuildRoleDelete            = "GUILD_ROLE_DELETE"
-	eventGuildInviteCreate          = "INVITE_CREATE"
-	eventGuildInviteDelete          = "INVITE_DELETE"
-	eventMessageCreate              = "MESSAGE_CREATE"
-	eventMessageUpdate              = "MESSAGE_UPDATE"
-	eventMessageDelete              = "MESSAGE_DELETE"
-	eventMessageDeleteBulk          = "MESSAGE_DELETE_BULK"
-	eventMessageAck                 = "MESSAGE_ACK"
-	eventMessageReactionAdd         = "MESSAGE_REACTION_ADD"
-	eventMessageReactionRemove      = "MESSAGE_REACTION_REMOVE"
-	eventMessageReactionRemoveAll   = "MESSAGE_REACTION_REMOVE_ALL"
-	eventMessageReactionRemoveEmoji = "MESSAGE_REACTION_REMOVE_EMOJI"
-	eventPresenceUpdate             = "PRESENCE_UPDATE"
-	eventTypingStart                = "TYPING_START"
-	eventUserUpdate                 = "USER_UPDATE"
-	eventVoiceStateUpdate           = "VOICE_STATE_UPDATE"
-	eventVoiceServerUpdate          = "VOICE_SERVER_UPDATE"
-	eventWebhooksUpdate             = "WEBHOOKS_UPDATE"
+	eventHello                         = "HELLO"
+	eventReady                         = "READY"
+	eventResumed                       = "RESUMED"
+	eventInvalidSession                = "INVALID_SESSION"
+	eventAutoModerationRuleCreate      = "AUTO_MODERATION_RULE_CREATE"
+	eventAutoModerationRuleUpdate      = "AUTO_MODERATION_RULE_UPDATE"
+	eventAutoModerationRuleDelete      = "AUTO_MODERATION_RULE_DELETE"
+	eventAutoModerationActionExecution = "AUTO_MODERATION_ACTION_EXECUTION"
+	eventChannelCreate                 = "CHANNEL_CREATE"
+	eventChannelUpdate                 = "CHANNEL_UPDATE"
+	eventChannelDelete                 = "CHANNEL_DELETE"
+	eventChannelPinsUpdate             = "CHANNEL_PINS_UPDATE"
+	eventGuildCreate                   = "GUILD_CREATE"
+	eventGuildUpdate                   = "GUILD_UPDATE"
+	eventGuildDelete                   = "GUILD_DELETE"
+	eventGuildBanAdd                   = "GUILD_BAN_ADD"
+	eventGuildBanRemove                = "GUILD_BAN_REMOVE"
+	eventGuildEmojisUpdate             = "GUILD_EMOJIS_UPDATE"
+	eventGuildStickersUpdate           = "GUILD_STICKERS_UPDATE"
+	eventGuildIntegrationsUpdate       = "GUILD_INTEGRATIONS_UPDATE"
+	eventGuildMemberAdd                = "GUILD_MEMBER_ADD"
+	eventGuildMemberRemove             = "GUILD_MEMBER_REMOVE"
+	eventGuildMemberUpdate             = "GUILD_MEMBER_UPDATE"
+	eventGuildMembersChunk             = "GUILD_MEMBERS_CHUNK"
+	eventGuildRoleCreate               = "GUILD_ROLE_CREATE"
+	eventGuildRoleUpdate               = "GUILD_ROLE_UPDATE"
+	eventGuildRoleDelete               = "GUILD_ROLE_DELETE"
+	eventGuildScheduledEventCreate     = "GUILD_SCHEDULED_EVENT_CREATE"
+	eventGuildScheduledEventUpdate     = "GUILD_SCHEDULED_EVENT_UPDATE"
+	eventGuildScheduledEventDelete     = "GUILD_SCHEDULED_EVENT_DELETE"
+	eventGuildScheduledEventUserAdd    = "GUILD_SCHEDULED_EVENT_USER_ADD"
+	eventGuildScheduledEventUserRemove = "GUILD_SCHEDULED_EVENT_USER_REMOVE"
+	eventGuildInviteCreate             = "INVITE_CREATE"
+	eventGuildInviteDelete             = "INVITE_DELETE"
+	eventMessageCreate                 = "MESSAGE_CREATE"
+	eventMessageUpdate                 = "MESSAGE_UPDATE"
+	eventMessageDelete                 = "MESSAGE_DELETE"
+	eventMessageDeleteBulk             = "MESSAGE_DELETE_BULK"
+	eventMessageAck                    = "MESSAGE_ACK"
+	eventMessageReactionAdd            = "MESSAGE_REACTION_ADD"
+	eventMessageReactionRemove         = "MESSAGE_REACTION_REMOVE"
+	eventMessageReactionRemoveAll      = "MESSAGE_REACTION_REMOVE_ALL"
+	eventMessageReactionRemoveEmoji    = "MESSAGE_REACTION_REMOVE_EMOJI"
+	eventPresenceUpdate                = "PRESENCE_UPDATE"
+	eventStageInstanceCreate           = "STAGE_INSTANCE_CREATE"
+	eventStageInstanceUpdate           = "STAGE_INSTANCE_UPDATE"
+	eventStageInstanceDelete           = "STAGE_INSTANCE_DELETE"
+	eventTypingStart                   = "TYPING_START"
+	eventUserUpdate                    = "USER_UPDATE"
+	eventVoiceStateUpdate              = "VOICE_STATE_UPDATE"
+	eventVoiceServerUpdate             = "VOICE_SERVER_UPDATE"
+	eventWebhooksUpdate                = "WEBHOOKS_UPDATE"
 )
 
 // NOTE: consider using a map[string]sync.Pool to cache event objects.
@@ -64,9 +77,19 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &r); err != nil {
 			return fmt.Errorf("unmarshal ready event: %w", err)
 		}
+		// Ready events can also be received after a failed attempt
+		// to resume a session, not only when connecting.
+		c.sessionID.Store(r.SessionID)
+		c.userID = r.User.ID
+		if c.withStateTracking {
+			c.logger.Debug("initializing state tracker")
+			c.State.setInitialState(&r)
+		}
 		c.handle(eventReady, &r)
+		c.startGuildsLoading(&r)
 	case eventResumed:
 		c.connected.Store(true)
+		c.handle(eventResumed, &Resumed{})
 	case eventInvalidSession:
 
 	case eventChannelCreate:
@@ -83,10 +106,11 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &ch); err != nil {
 			return fmt.Errorf("unmarshal channel update event: %w", err)
 		}
+		cu := &ChannelUpdate{Channel: &ch}
 		if c.withStateTracking {
-			c.State.updateChannel(&ch)
+			cu.Old = c.State.updateChannel(&ch)
 		}
-		c.handle(eventChannelUpdate, &ch)
+		c.handle(eventChannelUpdate, cu)
 	case eventChannelDelete:
 		var ch discord.Channel
 		if err = json.Unmarshal(data, &ch); err != nil {
@@ -116,15 +140,22 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 			c.State.updateGuild(&g)
 		}
 		c.handle(eventGuildCreate, &g)
+		switch c.guildCreated(g.ID) {
+		case guildCreateJoin:
+			c.handle(eventGuildJoin, &g)
+		case guildCreateAvailable:
+			c.handle(eventGuildAvailable, &g)
+		}
 	case eventGuildUpdate:
 		var g discord.Guild
 		if err = json.Unmarshal(data, &g); err != nil {
 			return fmt.Errorf("unmarshal guild update event: %w", err)
 		}
+		gu := &GuildUpdate{Guild: &g}
 		if c.withStateTracking {
-			c.State.updateGuild(&g)
+			gu.Old = c.State.updateGuild(&g)
 		}
-		c.handle(eventGuildUpdate, &g)
+		c.handle(eventGuildUpdate, gu)
 	case eventGuildDelete:
 		var g discord.UnavailableGuild
 		if err = json.Unmarshal(data, &g); err != nil {
@@ -133,6 +164,7 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if c.withStateTracking {
 			c.State.removeGuild(&g)
 		}
+		c.guildDeleted(&g)
 		c.handle(eventGuildDelete, &g)
 
 	case eventGuildBanAdd:
@@ -158,6 +190,16 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		}
 		c.handle(eventGuildEmojisUpdate, &ge)
 
+	case eventGuildStickersUpdate:
+		var gs GuildStickers
+		if err = json.Unmarshal(data, &gs); err != nil {
+			return fmt.Errorf("unmarshal guild stickers update event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.updateGuildStickers(gs.GuildID, gs.Stickers)
+		}
+		c.handle(eventGuildStickersUpdate, &gs)
+
 	case eventGuildIntegrationsUpdate:
 		var giu GuildIntegrationUpdate
 		if err = json.Unmarshal(data, &giu); err != nil {
@@ -189,7 +231,7 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 			return fmt.Errorf("unmarshal guild member update event: %w", err)
 		}
 		if c.withStateTracking {
-			c.State.guildMemberUpdate(&m)
+			m.Old = c.State.guildMemberUpdate(&m)
 		}
 		c.handle(eventGuildMemberUpdate, &m)
 
@@ -199,16 +241,13 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 			return fmt.Errorf("unmarshal guild members chunk event: %w", err)
 		}
 		if c.withStateTracking {
-			for _, m := range chunk.Members {
-				c.State.guildMemberUpdate(&GuildMemberUpdate{
-					GuildID: chunk.GuildID,
-					Roles:   m.Roles,
-					User:    m.User,
-					Nick:    m.Nick,
-				})
-			}
+			c.State.guildMembersChunk(&chunk)
+		}
+		// Chunks requested with FetchGuildMembers are
+		// not sent to the user handler.
+		if !c.completeMembersRequest(&chunk) {
+			c.handle(eventGuildMembersChunk, &chunk)
 		}
-		c.handle(eventGuildMembersChunk, &chunk)
 
 	case eventGuildRoleCreate:
 		var gr GuildRole
@@ -224,10 +263,11 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &gr); err != nil {
 			return fmt.Errorf("unmarshal guild role update event: %w", err)
 		}
+		gru := &GuildRoleUpdate{GuildRole: &gr}
 		if c.withStateTracking {
-			c.State.guildRoleUpdate(&gr)
+			gru.Old = c.State.guildRoleUpdate(&gr)
 		}
-		c.handle(eventGuildRoleUpdate, &gr)
+		c.handle(eventGuildRoleUpdate, gru)
 	case eventGuildRoleDelete:
 		var gr GuildRoleDelete
 		if err = json.Unmarshal(data, &gr); err != nil {
@@ -237,6 +277,105 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 			c.State.guildRoleRemove(&gr)
 		}
 		c.handle(eventGuildRoleDelete, &gr)
+	case eventGuildScheduledEventCreate:
+		var se discord.ScheduledEvent
+		if err = json.Unmarshal(data, &se); err != nil {
+			return fmt.Errorf("unmarshal guild scheduled event create event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.setScheduledEvent(&se)
+		}
+		c.handle(eventGuildScheduledEventCreate, &se)
+	case eventGuildScheduledEventUpdate:
+		var se discord.ScheduledEvent
+		if err = json.Unmarshal(data, &se); err != nil {
+			return fmt.Errorf("unmarshal guild scheduled event update event: %w", err)
+		}
+		seu := &ScheduledEventUpdate{ScheduledEvent: &se}
+		if c.withStateTracking {
+			seu.Old = c.State.setScheduledEvent(&se)
+		}
+		c.handle(eventGuildScheduledEventUpdate, seu)
+	case eventGuildScheduledEventDelete:
+		var se discord.ScheduledEvent
+		if err = json.Unmarshal(data, &se); err != nil {
+			return fmt.Errorf("unmarshal guild scheduled event delete event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.deleteScheduledEvent(se.GuildID, se.ID)
+		}
+		c.handle(eventGuildScheduledEventDelete, &se)
+	case eventGuildScheduledEventUserAdd:
+		var seu GuildScheduledEventUser
+		if err = json.Unmarshal(data, &seu); err != nil {
+			return fmt.Errorf("unmarshal guild scheduled event user add event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.updateScheduledEventUserCount(seu.GuildID, seu.ScheduledEventID, 1)
+		}
+		c.handle(eventGuildScheduledEventUserAdd, &seu)
+	case eventGuildScheduledEventUserRemove:
+		var seu GuildScheduledEventUser
+		if err = json.Unmarshal(data, &seu); err != nil {
+			return fmt.Errorf("unmarshal guild scheduled event user remove event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.updateScheduledEventUserCount(seu.GuildID, seu.ScheduledEventID, -1)
+		}
+		c.handle(eventGuildScheduledEventUserRemove, &seu)
+
+	case eventStageInstanceCreate:
+		var si discord.StageInstance
+		if err = json.Unmarshal(data, &si); err != nil {
+			return fmt.Errorf("unmarshal stage instance create event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.setStageInstance(&si)
+		}
+		c.handle(eventStageInstanceCreate, &si)
+	case eventStageInstanceUpdate:
+		var si discord.StageInstance
+		if err = json.Unmarshal(data, &si); err != nil {
+			return fmt.Errorf("unmarshal stage instance update event: %w", err)
+		}
+		siu := &StageInstanceUpdate{StageInstance: &si}
+		if c.withStateTracking {
+			siu.Old = c.State.setStageInstance(&si)
+		}
+		c.handle(eventStageInstanceUpdate, siu)
+	case eventStageInstanceDelete:
+		var si discord.StageInstance
+		if err = json.Unmarshal(data, &si); err != nil {
+			return fmt.Errorf("unmarshal stage instance delete event: %w", err)
+		}
+		if c.withStateTracking {
+			c.State.deleteStageInstance(si.GuildID, si.ID)
+		}
+		c.handle(eventStageInstanceDelete, &si)
+	case eventAutoModerationRuleCreate:
+		var rule discord.AutoModerationRule
+		if err = json.Unmarshal(data, &rule); err != nil {
+			return fmt.Errorf("unmarshal auto moderation rule create event: %w", err)
+		}
+		c.handle(eventAutoModerationRuleCreate, &rule)
+	case eventAutoModerationRuleUpdate:
+		var rule discord.AutoModerationRule
+		if err = json.Unmarshal(data, &rule); err != nil {
+			return fmt.Errorf("unmarshal auto moderation rule update event: %w", err)
+		}
+		c.handle(eventAutoModerationRuleUpdate, &rule)
+	case eventAutoModerationRuleDelete:
+		var rule discord.AutoModerationRule
+		if err = json.Unmarshal(data, &rule); err != nil {
+			return fmt.Errorf("unmarshal auto moderation rule delete event: %w", err)
+		}
+		c.handle(eventAutoModerationRuleDelete, &rule)
+	case eventAutoModerationActionExecution:
+		var ae AutoModerationActionExecution
+		if err = json.Unmarshal(data, &ae); err != nil {
+			return fmt.Errorf("unmarshal auto moderation action execution event: %w", err)
+		}
+		c.handle(eventAutoModerationActionExecution, &ae)
 	case eventGuildInviteCreate:
 		var gic GuildInviteCreate
 		if err = json.Unmarshal(data, &gic); err != nil {
@@ -255,24 +394,37 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &msg); err != nil {
 			return fmt.Errorf("unmarshal message create event: %w", err)
 		}
+		if c.withStateTracking {
+			c.State.addMessage(&msg)
+		}
 		c.handle(eventMessageCreate, &msg)
 	case eventMessageUpdate:
 		var msg discord.Message
 		if err = json.Unmarshal(data, &msg); err != nil {
 			return fmt.Errorf("unmarshal message update event: %w", err)
 		}
-		c.handle(eventMessageUpdate, &msg)
+		mu := &MessageUpdate{Message: &msg}
+		if c.withStateTracking {
+			mu.Old = c.State.updateMessage(&msg, data)
+		}
+		c.handle(eventMessageUpdate, mu)
 	case eventMessageDelete:
 		var md MessageDelete
 		if err = json.Unmarshal(data, &md); err != nil {
 			return fmt.Errorf("unmarshal message delete event: %w", err)
 		}
+		if c.withStateTracking {
+			md.Message = c.State.removeMessage(md.MessageID)
+		}
 		c.handle(eventMessageDelete, &md)
 	case eventMessageDeleteBulk:
 		var md MessageDeleteBulk
 		if err = json.Unmarshal(data, &md); err != nil {
 			return fmt.Errorf("unmarshal message delete bulk event: %w", err)
 		}
+		if c.withStateTracking {
+			md.Messages = c.State.removeMessages(md.IDs)
+		}
 		c.handle(eventMessageDeleteBulk, &md)
 	case eventMessageAck:
 		var ma MessageAck
@@ -311,10 +463,11 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal presence update event: %w", err)
 		}
+		pu := &PresenceUpdate{Presence: &p}
 		if c.withStateTracking {
-			c.State.updatePresence(&p)
+			pu.Old = c.State.updatePresence(&p)
 		}
-		c.handle(eventPresenceUpdate, &p)
+		c.handle(eventPresenceUpdate, pu)
 
 	case eventTypingStart:
 		var ts TypingStart
@@ -328,10 +481,11 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 		if err = json.Unmarshal(data, &u); err != nil {
 			return fmt.Errorf("unmarshal user update event: %w", err)
 		}
+		uu := &UserUpdate{User: &u}
 		if c.withStateTracking {
-			c.State.updateUser(&u)
+			uu.Old = c.State.updateUser(&u)
 		}
-		c.handle(eventUserUpdate, &u)
+		c.handle(eventUserUpdate, uu)
 
 	case eventVoiceStateUpdate:
 		var vs voice.StateUpdate
@@ -352,10 +506,28 @@ func (c *Client) dispatch(typ string, data json.RawMessage) error {
 			}
 		}
 
+		var (
+			old     *voice.State
+			tracked bool
+		)
 		if c.withStateTracking {
-			c.State.updateGuildVoiceStates(&vs)
+			// The voice package can not depend on the discord package,
+			// decode the member this voice state is for separately.
+			var withMember struct {
+				Member *discord.GuildMember `json:"member"`
+			}
+			if err = json.Unmarshal(data, &withMember); err != nil {
+				return fmt.Errorf("unmarshal voice state update event member: %w", err)
+			}
+			old, tracked = c.State.updateGuildVoiceStates(&vs, withMember.Member)
 		}
 		c.handle(eventVoiceStateUpdate, &vs)
+		// Without the previous voice state, there is no telling
+		// whether the user joined, left or moved. The derived event gets
+		// its own copy so handlers of both events can not share it.
+		if tracked {
+			c.handleVoiceChannelChange(old, vs.State.Clone())
+		}
 	case eventVoiceServerUpdate:
 		var vs voice.ServerUpdate
 		if err = json.Unmarshal(data, &vs); err != nil {
@@ -398,9 +570,6 @@ func (c *Client) handle(event string, d interface{}) {
 	h, ok := c.handlers[event]
 	c.handlersMu.RUnlock()
 	if ok {
-		// Call the registered handler in its own goroutine
-		// so it does not block the dispatcher and events
-		// can continue to be treated as we receive them.
-		go h.handle(d)
+		c.dispatcher.dispatch(event, h, d)
 	}
 }
@@ -0,0 +1,362 @@
+package harmony
+
+import (
+	"hash/fnv"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/log"
+	"github.com/skwair/harmony/voice"
+	"go.uber.org/atomic"
+)
+
+// DispatchMode determines how registered event handlers are called
+// when events are received from the Gateway.
+type DispatchMode int
+
+const (
+	// DispatchModeAsync calls each handler in its own goroutine. Handlers
+	// never block the Gateway connection but there is no bound on the number
+	// of goroutines and no ordering guarantee between events.
+	// This is the default mode.
+	DispatchModeAsync DispatchMode = iota
+	// DispatchModeSync calls handlers one after the other, from the goroutine
+	// that reads the Gateway connection. Events are handled in the exact order
+	// they are received, but a slow handler delays every following event and
+	// a handler that blocks for too long will eventually cause the connection
+	// to time out. Handlers must not call Disconnect or methods that wait for
+	// Gateway events themselves (such as JoinVoiceChannel) in this mode, since
+	// those wait for the goroutine that is calling the handler.
+	DispatchModeSync
+	// DispatchModeGuildPool calls handlers from a fixed number of workers.
+	// Events are assigned to workers based on their guild ID, meaning events
+	// of a given guild are always handled in the order they were received.
+	// Events that are not related to a guild are assigned based on their channel
+	// ID (e.g. direct messages). Events related to neither (such as lifecycle
+	// events) are handled once all events received before them have been handled.
+	// Workers are started when the Client connects and stopped once it is
+	// disconnected for good, events sent in between are handled as with
+	// DispatchModeAsync.
+	DispatchModeGuildPool
+	// DispatchModeChannelPool is like DispatchModeGuildPool except events are
+	// assigned to workers based on their channel ID, falling back to their guild
+	// ID for events that are not related to a channel.
+	// Events related to neither are handled once all events received before them
+	// have been handled.
+	DispatchModeChannelPool
+)
+
+// dispatchQueueSize is the number of events that can wait for a worker
+// before the Gateway listener starts blocking.
+const dispatchQueueSize = 256
+
+// HandlerPanicFunc is called when an event handler panics. It receives the
+// name of the event being handled, the value that was recovered and the stack
+// trace of the goroutine that panicked.
+type HandlerPanicFunc func(event string, recovered interface{}, stack []byte)
+
+// dispatchJob is a single handler call waiting to be executed by a worker.
+type dispatchJob struct {
+	event string
+	h     handler
+	d     interface{}
+	// remaining is set for events sent to all workers, it is the number
+	// of workers that did not reach the event yet. The last one calls h.
+	remaining *atomic.Int32
+}
+
+// reached records that a worker reached the job, reporting whether the
+// handler must be called now.
+func (j dispatchJob) reached() bool {
+	return j.remaining == nil || j.remaining.Dec() == 0
+}
+
+// dispatcher calls event handlers according to a DispatchMode.
+type dispatcher struct {
+	mode    DispatchMode
+	workers int
+	onPanic HandlerPanicFunc
+	logger  log.Logger
+
+	// Workers, nil when they are not running.
+	mu   sync.RWMutex
+	pool *workerPool
+}
+
+// workerPool is a set of running workers, each with its own queue.
+type workerPool struct {
+	queues []chan dispatchJob
+	// stopped is closed when the workers are stopped, so events
+	// waiting for room in a full queue stop waiting.
+	stopped chan struct{}
+	// senders tracks events being sent to the queues, which
+	// must not be closed until they are all sent.
+	senders sync.WaitGroup
+}
+
+// newDispatcher returns a new dispatcher for the given mode. If the mode
+// requires workers, they are not running until start is called.
+func newDispatcher(mode DispatchMode, workers int, onPanic HandlerPanicFunc, logger log.Logger) *dispatcher {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &dispatcher{
+		mode:    mode,
+		workers: workers,
+		onPanic: onPanic,
+		logger:  logger,
+	}
+}
+
+// usesWorkers reports whether the dispatch mode requires workers.
+func (d *dispatcher) usesWorkers() bool {
+	return d.mode == DispatchModeGuildPool || d.mode == DispatchModeChannelPool
+}
+
+// start starts the workers if the dispatch mode requires them and
+// they are not already running.
+func (d *dispatcher) start() {
+	if !d.usesWorkers() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pool != nil {
+		return
+	}
+
+	d.pool = &workerPool{
+		queues:  make([]chan dispatchJob, d.workers),
+		stopped: make(chan struct{}),
+	}
+	for i := range d.pool.queues {
+		d.pool.queues[i] = make(chan dispatchJob, dispatchQueueSize)
+		go d.work(d.pool.queues[i])
+	}
+}
+
+// stop stops the workers, if they are running. Jobs that are already queued
+// are still executed but stop does not wait for them. Events that were waiting
+// for room in a full queue are handled as with DispatchModeAsync instead, so
+// stop never blocks, even when called from a handler.
+func (d *dispatcher) stop() {
+	d.mu.Lock()
+	pool := d.pool
+	d.pool = nil
+	d.mu.Unlock()
+
+	if pool == nil {
+		return
+	}
+
+	close(pool.stopped)
+	go func() {
+		pool.senders.Wait()
+		for _, q := range pool.queues {
+			close(q)
+		}
+	}()
+}
+
+// dispatch calls h with the event data d, according to the dispatch mode.
+func (d *dispatcher) dispatch(event string, h handler, data interface{}) {
+	switch d.mode {
+	case DispatchModeSync:
+		d.call(event, h, data)
+
+	case DispatchModeGuildPool, DispatchModeChannelPool:
+		d.mu.RLock()
+		pool := d.pool
+		if pool != nil {
+			pool.senders.Add(1)
+		}
+		d.mu.RUnlock()
+
+		// Workers are not running, which happens for events sent
+		// while the Client is not connected, e.g. Disconnected.
+		if pool == nil {
+			go d.call(event, h, data)
+			return
+		}
+		defer pool.senders.Done()
+
+		job := dispatchJob{event: event, h: h, d: data}
+		queues := pool.queues
+		if i, ok := d.queueFor(data, len(pool.queues)); ok {
+			queues = queues[i : i+1]
+		} else {
+			// Events with no key are sent to all workers and handled by
+			// the last one to reach them, after all previous events.
+			job.remaining = atomic.NewInt32(int32(len(queues)))
+		}
+
+		// The lock is not held while waiting for room in the queues,
+		// so the workers can be stopped in the meantime.
+		for i, q := range queues {
+			select {
+			case q <- job:
+			case <-pool.stopped:
+				for range queues[i:] {
+					if job.reached() {
+						go d.call(event, h, data)
+					}
+				}
+				return
+			}
+		}
+
+	default:
+		// Call the registered handler in its own goroutine
+		// so it does not block the dispatcher and events
+		// can continue to be treated as we receive them.
+		go d.call(event, h, data)
+	}
+}
+
+// work executes jobs sent through q, one at a time.
+func (d *dispatcher) work(q chan dispatchJob) {
+	for job := range q {
+		if job.reached() {
+			d.call(job.event, job.h, job.d)
+		}
+	}
+}
+
+// queueFor returns the index of the queue the given event should be
+// sent to, out of the given number of queues. It reports false if the
+// event has no key, in which case it must be sent to all queues.
+func (d *dispatcher) queueFor(data interface{}, queues int) (int, bool) {
+	guildID, channelID := eventKeys(data)
+
+	key := guildID
+	if key == "" || (d.mode == DispatchModeChannelPool && channelID != "") {
+		key = channelID
+	}
+	if key == "" {
+		return 0, false
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(queues)), true
+}
+
+// call calls the handler, recovering from any panic it could trigger.
+func (d *dispatcher) call(event string, h handler, data interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			if d.onPanic != nil {
+				d.onPanic(event, r, stack)
+				return
+			}
+			d.logger.Errorf("recovered from panic in %s handler: %v\n%s", event, r, stack)
+		}
+	}()
+
+	h.handle(data)
+}
+
+// eventKeys returns the guild and channel IDs an event relates to, if any.
+// They are used to assign events to workers when dispatching with a pool.
+func eventKeys(d interface{}) (guildID, channelID string) {
+	switch e := d.(type) {
+	case *discord.Channel:
+		return e.GuildID, e.ID
+	case *ChannelUpdate:
+		return e.GuildID, e.ID
+	case *ChannelPinsUpdate:
+		return e.GuildID, e.ChannelID
+	case *discord.Guild:
+		return e.ID, ""
+	case *GuildUpdate:
+		return e.ID, ""
+	case *discord.UnavailableGuild:
+		return e.ID, ""
+	case *GuildsReady:
+		return "", ""
+	case *GuildBan:
+		return e.GuildID, ""
+	case *GuildEmojis:
+		return e.GuildID, ""
+	case *GuildStickers:
+		return e.GuildID, ""
+	case *discord.ScheduledEvent:
+		return e.GuildID, ""
+	case *ScheduledEventUpdate:
+		return e.GuildID, ""
+	case *GuildScheduledEventUser:
+		return e.GuildID, ""
+	case *discord.StageInstance:
+		return e.GuildID, e.ChannelID
+	case *StageInstanceUpdate:
+		return e.GuildID, e.ChannelID
+	case *discord.AutoModerationRule:
+		return e.GuildID, ""
+	case *AutoModerationActionExecution:
+		return e.GuildID, e.ChannelID
+	case *GuildIntegrationUpdate:
+		return e.GuildID, ""
+	case *GuildMemberAdd:
+		return e.GuildID, ""
+	case *GuildMemberRemove:
+		return e.GuildID, ""
+	case *GuildMemberUpdate:
+		return e.GuildID, ""
+	case *GuildMembersChunk:
+		return e.GuildID, ""
+	case *GuildRole:
+		return e.GuildID, ""
+	case *GuildRoleUpdate:
+		return e.GuildID, ""
+	case *GuildRoleDelete:
+		return e.GuildID, ""
+	case *GuildInviteCreate:
+		return e.GuildID, e.ChannelID
+	case *GuildInviteDelete:
+		return e.GuildID, e.ChannelID
+	case *discord.Message:
+		return e.GuildID, e.ChannelID
+	case *MessageUpdate:
+		return e.GuildID, e.ChannelID
+	case *MessageDelete:
+		return e.GuildID, e.ChannelID
+	case *MessageDeleteBulk:
+		return e.GuildID, e.ChannelID
+	case *MessageAck:
+		return "", e.ChannelID
+	case *MessageReaction:
+		return e.GuildID, e.ChannelID
+	case *MessageReactionRemoveAll:
+		return e.GuildID, e.ChannelID
+	case *MessageReactionRemoveEmoji:
+		return e.GuildID, e.ChannelID
+	case *discord.Presence:
+		return e.GuildID, ""
+	case *PresenceUpdate:
+		return e.GuildID, ""
+	case *TypingStart:
+		return e.GuildID, e.ChannelID
+	case *voice.StateUpdate:
+		if e.ChannelID != nil {
+			return e.GuildID, *e.ChannelID
+		}
+		return e.GuildID, ""
+	case *VoiceChannelJoin:
+		return e.GuildID, *e.ChannelID
+	case *VoiceChannelLeave:
+		return e.GuildID, *e.Old.ChannelID
+	case *VoiceChannelMove:
+		return e.GuildID, *e.ChannelID
+	case *voice.ServerUpdate:
+		return e.GuildID, ""
+	case *WebhooksUpdate:
+		return e.GuildID, e.ChannelID
+	}
+	return "", ""
+}
@@ -0,0 +1,257 @@
+package harmony
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/log"
+)
+
+var testLogger = log.NewStd(io.Discard, log.LevelError)
+
+type testHandler func(interface{})
+
+func (h testHandler) handle(v interface{}) { h(v) }
+
+func TestDispatcherPoolOrdering(t *testing.T) {
+	tests := []struct {
+		name string
+		mode DispatchMode
+		// Event i of this test is sent for guild guilds[i%len(guilds)] and
+		// channel channels[i%len(channels)].
+		guilds   []string
+		channels []string
+		// Returns the key under which events must be received in order.
+		key func(m *discord.Message) string
+	}{
+		{
+			name:     "guild pool",
+			mode:     DispatchModeGuildPool,
+			guilds:   []string{"g1", "g2", "g3"},
+			channels: []string{"c1", "c2", "c3", "c4"},
+			key:      func(m *discord.Message) string { return m.GuildID },
+		},
+		{
+			name:     "channel pool",
+			mode:     DispatchModeChannelPool,
+			guilds:   []string{"g1"},
+			channels: []string{"c1", "c2", "c3", "c4"},
+			key:      func(m *discord.Message) string { return m.ChannelID },
+		},
+		{
+			name:     "guild pool falls back to channels",
+			mode:     DispatchModeGuildPool,
+			guilds:   []string{""},
+			channels: []string{"dm1", "dm2"},
+			key:      func(m *discord.Message) string { return m.ChannelID },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 1000
+
+			var (
+				mu   sync.Mutex
+				wg   sync.WaitGroup
+				last = make(map[string]int)
+			)
+			wg.Add(n)
+			h := testHandler(func(v interface{}) {
+				defer wg.Done()
+
+				m := v.(*discord.Message)
+				i, _ := strconv.Atoi(m.Content)
+
+				mu.Lock()
+				defer mu.Unlock()
+				key := tt.key(m)
+				if prev, ok := last[key]; ok && prev > i {
+					t.Errorf("event %d for key %q handled after event %d", i, key, prev)
+				}
+				last[key] = i
+			})
+
+			d := newDispatcher(tt.mode, 4, nil, testLogger)
+			d.start()
+			defer d.stop()
+
+			for i := 0; i < n; i++ {
+				m := &discord.Message{
+					GuildID:   tt.guilds[i%len(tt.guilds)],
+					ChannelID: tt.channels[i%len(tt.channels)],
+					Content:   strconv.Itoa(i),
+				}
+				d.dispatch("MESSAGE_CREATE", h, m)
+			}
+
+			waitGroup(t, &wg)
+		})
+	}
+}
+
+func TestDispatcherQueueFor(t *testing.T) {
+	d := newDispatcher(DispatchModeGuildPool, 8, nil, testLogger)
+
+	// Events with the same guild always go to the same worker.
+	a, _ := d.queueFor(&discord.Message{GuildID: "g1", ChannelID: "c1"}, 8)
+	b, _ := d.queueFor(&discord.Message{GuildID: "g1", ChannelID: "c2"}, 8)
+	if a != b {
+		t.Errorf("events of the same guild sent to workers %d and %d", a, b)
+	}
+
+	// Events with no key go to all workers.
+	if _, ok := d.queueFor(&GuildsReady{}, 8); ok {
+		t.Error("expected event with no key to be sent to all workers")
+	}
+}
+
+func TestDispatcherEventsWithNoKey(t *testing.T) {
+	d := newDispatcher(DispatchModeGuildPool, 4, nil, testLogger)
+	d.start()
+	defer d.stop()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		handled []string
+	)
+	h := testHandler(func(v interface{}) {
+		defer wg.Done()
+
+		// Slow down guild events so they would be handled last if
+		// lifecycle events did not wait for them.
+		id := "ready"
+		if m, ok := v.(*discord.Message); ok {
+			time.Sleep(time.Millisecond)
+			id = m.GuildID
+		}
+		mu.Lock()
+		handled = append(handled, id)
+		mu.Unlock()
+	})
+
+	guilds := []string{"g1", "g2", "g3", "g4", "g5", "g6", "g7", "g8"}
+	wg.Add(len(guilds) + 1)
+	for _, id := range guilds {
+		d.dispatch("MESSAGE_CREATE", h, &discord.Message{GuildID: id})
+	}
+	d.dispatch("GUILDS_READY", h, &GuildsReady{})
+	waitGroup(t, &wg)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != len(guilds)+1 || handled[len(guilds)] != "ready" {
+		t.Errorf("expected event with no key to be handled once, last; got %v", handled)
+	}
+}
+
+func TestDispatcherStop(t *testing.T) {
+	d := newDispatcher(DispatchModeChannelPool, 2, nil, testLogger)
+
+	var wg sync.WaitGroup
+	h := testHandler(func(v interface{}) { wg.Done() })
+
+	// Not started yet, handlers are still called.
+	wg.Add(1)
+	d.dispatch("MESSAGE_CREATE", h, &discord.Message{ChannelID: "c1"})
+	waitGroup(t, &wg)
+
+	d.start()
+	d.start() // No-op.
+	if len(d.pool.queues) != 2 {
+		t.Fatalf("expected 2 workers; got %d", len(d.pool.queues))
+	}
+
+	wg.Add(1)
+	d.dispatch("MESSAGE_CREATE", h, &discord.Message{ChannelID: "c1"})
+	waitGroup(t, &wg)
+
+	d.stop()
+	d.stop() // No-op.
+	if d.pool != nil {
+		t.Fatal("expected workers to be stopped")
+	}
+
+	// Stopped, handlers are still called.
+	wg.Add(1)
+	d.dispatch("MESSAGE_CREATE", h, &discord.Message{ChannelID: "c1"})
+	waitGroup(t, &wg)
+}
+
+func TestDispatcherStopWhileFull(t *testing.T) {
+	d := newDispatcher(DispatchModeGuildPool, 1, nil, testLogger)
+	d.start()
+
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+	h := testHandler(func(v interface{}) {
+		<-release
+		wg.Done()
+	})
+
+	// The first event is being handled and the next ones fill the queue.
+	wg.Add(dispatchQueueSize + 2)
+	for i := 0; i < dispatchQueueSize+1; i++ {
+		d.dispatch("MESSAGE_CREATE", h, &discord.Message{GuildID: "g1"})
+	}
+
+	// This one waits for room in the queue until the workers are stopped,
+	// as when a handler calls Disconnect while the Gateway listener waits.
+	dispatched := make(chan struct{})
+	go func() {
+		d.dispatch("MESSAGE_CREATE", h, &discord.Message{GuildID: "g1"})
+		close(dispatched)
+	}()
+	d.stop()
+
+	select {
+	case <-dispatched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for dispatch to return")
+	}
+
+	// All events are still handled.
+	close(release)
+	waitGroup(t, &wg)
+}
+
+func TestDispatcherRecoversPanics(t *testing.T) {
+	recovered := make(chan interface{}, 1)
+	onPanic := func(event string, r interface{}, stack []byte) {
+		recovered <- r
+	}
+
+	d := newDispatcher(DispatchModeSync, 0, onPanic, testLogger)
+	d.dispatch("MESSAGE_CREATE", testHandler(func(interface{}) { panic("boom") }), &discord.Message{})
+
+	select {
+	case r := <-recovered:
+		if r != "boom" {
+			t.Errorf("expected to recover %q; got %v", "boom", r)
+		}
+	default:
+		t.Error("expected panic hook to be called")
+	}
+}
+
+// waitGroup waits for wg, failing the test if it takes too long.
+func waitGroup(t *testing.T, wg *sync.WaitGroup) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for handlers")
+	}
+}
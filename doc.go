@@ -30,6 +30,16 @@ It is only when successfully connected to the Gateway that your bot will
 appear as online and your Client will be able to receive events and send
 messages.
 
+If the connection to the Gateway is lost, the Client automatically tries to
+reconnect. Some errors can not be recovered from though (an invalid token or
+disallowed intents for example). The Done method returns a channel that is
+closed when this happens, and Err reports what went wrong:
+
+	<-client.Done()
+	if err := client.Err(); err != nil {
+		// Handle error, it can be a *discord.GatewayCloseError.
+	}
+
 Using the HTTP API
 
 Harmony's HTTP API is organized by resource. A resource maps to a core
@@ -68,8 +78,19 @@ bot is in with the message as a parameter.
 
 To register handlers for other types of events, see Client.On* methods.
 
-Note that your handlers are called in their own goroutine, meaning
-whatever you do inside of them won't block future events.
+By default, your handlers are called in their own goroutine, meaning
+whatever you do inside of them won't block future events. This also means
+there is no guarantee on the order in which handlers are called. If you need
+events to be handled in order, use the WithDispatchMode option to either call
+handlers synchronously or from a bounded pool of workers that preserves
+ordering per guild or per channel:
+
+	client, err := harmony.NewClient(token,
+		harmony.WithDispatchMode(harmony.DispatchModeChannelPool, 8),
+	)
+
+Panics occurring in handlers are recovered and logged. Use WithHandlerPanicHook
+to report them differently.
 
 Using the state
 
@@ -86,6 +107,55 @@ query the current state like so:
 Because this state might become memory hungry for bots that are in a very
 large number of servers, you can fine-tune events you want to track with the
 WithGatewayIntents option. State can also be completely disabled using the
-WithStateTracking option while creating the harmony client.
+WithStateTracking option while creating the harmony client. For finer control,
+WithStateCache selects which entities are cached, WithMemberCachePolicy which
+guild members are cached (e.g. only those in a voice channel or those that
+recently sent a message) and WithStateGuilds restricts the state to a set of
+guilds. The state can also be kept outside of the Go heap with the WithStateStore option, the
+diskstore package provides a store that keeps it on disk.
+
+The state can be saved to a file with SaveStateSnapshot and loaded back with
+LoadStateSnapshot before connecting. The Client then tries to resume the saved
+session and the state is available right away, without waiting for Discord to
+send guilds again.
+
+Messages are not cached by default. Use the WithMessageCache option to keep
+recent messages in the state, so message delete handlers and handlers registered
+with OnMessageUpdateWithOld receive the previous version of the messages:
+
+	client.OnMessageDelete(func(md *harmony.MessageDelete) {
+		if md.Message != nil {
+			fmt.Println("deleted:", md.Message.Content)
+		}
+	})
+
+Similarly, when state tracking is enabled, guild member update handlers and handlers
+registered with OnGuildUpdateWithOld, OnChannelUpdateWithOld, OnGuildRoleUpdateWithOld,
+OnPresenceUpdateWithOld and OnUserUpdateWithOld receive the previous version of the
+updated object, if it was in the state:
+
+	client.OnGuildMemberUpdate(func(m *harmony.GuildMemberUpdate) {
+		if m.Old != nil && m.Old.Nick != m.Nick {
+			fmt.Printf("nickname changed from %q to %q\n", m.Old.Nick, m.Nick)
+		}
+	})
+
+Changes of the state itself can also be observed, independently of Gateway events,
+by subscribing to it. Subscribers can filter changes by entity kind and guild:
+
+	unsubscribe := client.State.Subscribe(harmony.StateFilter{
+		Entities: harmony.StateEntityChannel | harmony.StateEntityRole,
+		GuildID:  guildID,
+	}, func(c *harmony.StateChange) {
+		// Handle the change.
+	})
+	defer unsubscribe()
+
+Changes are queued for each subscriber and dropped if it falls too far behind,
+see StateFilter.QueueSize.
+
+State.Stats reports how many entities the state holds by kind and guild and the
+approximate memory they use. To bound this memory, use WithStateMemoryBudget:
+least recently used guild members and presences are evicted when it is exceeded.
 */
 package harmony
@@ -11,6 +11,9 @@ type handler interface {
 
 var intents = map[string]discord.GatewayIntent{
 	eventGuildCreate:       discord.GatewayIntentGuild,
+	eventGuildJoin:         discord.GatewayIntentGuild,
+	eventGuildAvailable:    discord.GatewayIntentGuild,
+	eventGuildsReady:       discord.GatewayIntentGuild,
 	eventGuildUpdate:       discord.GatewayIntentGuild,
 	eventGuildRoleCreate:   discord.GatewayIntentGuild,
 	eventGuildRoleUpdate:   discord.GatewayIntentGuild,
@@ -20,6 +23,10 @@ var intents = map[string]discord.GatewayIntent{
 	eventChannelDelete:     discord.GatewayIntentGuild,
 	eventChannelPinsUpdate: discord.GatewayIntentGuild,
 
+	eventStageInstanceCreate: discord.GatewayIntentGuild,
+	eventStageInstanceUpdate: discord.GatewayIntentGuild,
+	eventStageInstanceDelete: discord.GatewayIntentGuild,
+
 	eventGuildMemberAdd:    discord.GatewayIntentGuildMembers,
 	eventGuildMemberUpdate: discord.GatewayIntentGuildMembers,
 	eventGuildMemberRemove: discord.GatewayIntentGuildMembers,
@@ -27,16 +34,32 @@ var intents = map[string]discord.GatewayIntent{
 	eventGuildBanAdd:    discord.GatewayIntentGuildBans,
 	eventGuildBanRemove: discord.GatewayIntentGuildBans,
 
-	eventGuildEmojisUpdate: discord.GatewayIntentGuildEmojis,
+	eventGuildEmojisUpdate:   discord.GatewayIntentGuildEmojis,
+	eventGuildStickersUpdate: discord.GatewayIntentGuildEmojis,
 
 	eventGuildIntegrationsUpdate: discord.GatewayIntentGuildIntegrations,
 
+	eventGuildScheduledEventCreate:     discord.GatewayIntentGuildScheduledEvents,
+	eventGuildScheduledEventUpdate:     discord.GatewayIntentGuildScheduledEvents,
+	eventGuildScheduledEventDelete:     discord.GatewayIntentGuildScheduledEvents,
+	eventGuildScheduledEventUserAdd:    discord.GatewayIntentGuildScheduledEvents,
+	eventGuildScheduledEventUserRemove: discord.GatewayIntentGuildScheduledEvents,
+
+	eventAutoModerationRuleCreate: discord.GatewayIntentAutoModerationConfiguration,
+	eventAutoModerationRuleUpdate: discord.GatewayIntentAutoModerationConfiguration,
+	eventAutoModerationRuleDelete: discord.GatewayIntentAutoModerationConfiguration,
+
+	eventAutoModerationActionExecution: discord.GatewayIntentAutoModerationExecution,
+
 	eventWebhooksUpdate: discord.GatewayIntentGuildWebhooks,
 
 	eventGuildInviteCreate: discord.GatewayIntentGuildInvites,
 	eventGuildInviteDelete: discord.GatewayIntentGuildInvites,
 
-	eventVoiceStateUpdate: discord.GatewayIntentGuildVoiceStates,
+	eventVoiceStateUpdate:  discord.GatewayIntentGuildVoiceStates,
+	eventVoiceChannelJoin:  discord.GatewayIntentGuildVoiceStates,
+	eventVoiceChannelLeave: discord.GatewayIntentGuildVoiceStates,
+	eventVoiceChannelMove:  discord.GatewayIntentGuildVoiceStates,
 
 	eventPresenceUpdate: discord.GatewayIntentGuildPresences,
 
@@ -57,7 +80,7 @@ func (c *Client) registerHandler(event string, h handler) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if missing := intents[event]; missing&c.intents == 0 {
+	if missing, ok := intents[event]; ok && missing&c.intents == 0 {
 		c.logger.Warnf("registering handler for event %q without required intent %q", event, missing)
 	}
 
@@ -96,19 +119,43 @@ func (c *Client) OnChannelCreate(f func(c *discord.Channel)) {
 	c.registerHandler(eventChannelCreate, channelCreateHandler(f))
 }
 
+// ChannelUpdate is sent when a channel is updated.
+type ChannelUpdate struct {
+	*discord.Channel
+	// Old is the channel as it was before this update. It is
+	// only set if the channel was in the State.
+	Old *discord.Channel `json:"-"`
+}
+
 type channelUpdateHandler func(*discord.Channel)
 
 // handle implements the handler interface.
 func (h channelUpdateHandler) handle(v interface{}) {
-	h(v.(*discord.Channel))
+	h(v.(*ChannelUpdate).Channel)
 }
 
 // OnChannelUpdate registers the handler function for the "CHANNEL_UPDATE" event.
 // This event is fired when a channel is updated, relevant to the current user.
+// Use OnChannelUpdateWithOld to also receive the channel as it was before this update.
 func (c *Client) OnChannelUpdate(f func(c *discord.Channel)) {
 	c.registerHandler(eventChannelUpdate, channelUpdateHandler(f))
 }
 
+type channelUpdateWithOldHandler func(*ChannelUpdate)
+
+// handle implements the handler interface.
+func (h channelUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*ChannelUpdate))
+}
+
+// OnChannelUpdateWithOld is like OnChannelUpdate but the handler also receives the channel
+// as it was before this update, if it was in the State.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnChannelUpdate, if any, and vice versa.
+func (c *Client) OnChannelUpdateWithOld(f func(c *ChannelUpdate)) {
+	c.registerHandler(eventChannelUpdate, channelUpdateWithOldHandler(f))
+}
+
 type channelDeleteHandler func(*discord.Channel)
 
 // handle implements the handler interface.
@@ -124,6 +171,7 @@ func (c *Client) OnChannelDelete(f func(c *discord.Channel)) {
 
 // ChannelPinsUpdate is Fired when a message is pinned or unpinned in a text channel.
 type ChannelPinsUpdate struct {
+	GuildID          string       `json:"guild_id"`
 	ChannelID        string       `json:"channel_id"`
 	LastPinTimestamp discord.Time `json:"last_pin_timestamp"`
 }
@@ -154,22 +202,48 @@ func (h guildCreateHandler) handle(v interface{}) {
 // 	1. When a user is initially connecting, to lazily load and backfill information for all unavailable guilds sent in the Ready event.
 // 	2. When a Guild becomes available again to the client.
 // 	3. When the current user joins a new Guild.
+// Use OnGuildsReady, OnGuildAvailable or OnGuildJoin to only handle one of those scenarios.
 func (c *Client) OnGuildCreate(f func(g *discord.Guild)) {
 	c.registerHandler(eventGuildCreate, guildCreateHandler(f))
 }
 
+// GuildUpdate is sent when a guild is updated.
+type GuildUpdate struct {
+	*discord.Guild
+	// Old is the guild as it was before this update. It is only set if the
+	// guild was in the State. Its roles are set, but not its channels, members,
+	// presences nor voice states.
+	Old *discord.Guild `json:"-"`
+}
+
 type guildUpdateHandler func(*discord.Guild)
 
 // handle implements the handler interface.
 func (h guildUpdateHandler) handle(v interface{}) {
-	h(v.(*discord.Guild))
+	h(v.(*GuildUpdate).Guild)
 }
 
 // HandleGuildUpdate registers the handler function for the "GUILD_UPDATE" event.
+// Use OnGuildUpdateWithOld to also receive the guild as it was before this update.
 func (c *Client) OnGuildUpdate(f func(g *discord.Guild)) {
 	c.registerHandler(eventGuildUpdate, guildUpdateHandler(f))
 }
 
+type guildUpdateWithOldHandler func(*GuildUpdate)
+
+// handle implements the handler interface.
+func (h guildUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*GuildUpdate))
+}
+
+// OnGuildUpdateWithOld is like OnGuildUpdate but the handler also receives the guild
+// as it was before this update, if it was in the State.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnGuildUpdate, if any, and vice versa.
+func (c *Client) OnGuildUpdateWithOld(f func(g *GuildUpdate)) {
+	c.registerHandler(eventGuildUpdate, guildUpdateWithOldHandler(f))
+}
+
 type guildDeleteHandler func(*discord.UnavailableGuild)
 
 // handle implements the handler interface.
@@ -233,6 +307,26 @@ func (c *Client) OnGuildEmojisUpdate(f func(emojis *GuildEmojis)) {
 	c.registerHandler(eventGuildEmojisUpdate, guildEmojisUpdateHandler(f))
 }
 
+// GuildStickers is sent when the stickers of a guild are updated.
+// Stickers holds all the stickers of the guild, not only those that changed.
+type GuildStickers struct {
+	Stickers []discord.Sticker `json:"stickers"`
+	GuildID  string            `json:"guild_id"`
+}
+
+type guildStickersUpdateHandler func(*GuildStickers)
+
+// handle implements the handler interface.
+func (h guildStickersUpdateHandler) handle(v interface{}) {
+	h(v.(*GuildStickers))
+}
+
+// OnGuildStickersUpdate registers the handler function for the "GUILD_STICKERS_UPDATE" event.
+// Fired when a guild's stickers have been updated.
+func (c *Client) OnGuildStickersUpdate(f func(stickers *GuildStickers)) {
+	c.registerHandler(eventGuildStickersUpdate, guildStickersUpdateHandler(f))
+}
+
 type GuildIntegrationUpdate struct {
 	GuildID string `json:"guild_id"`
 }
@@ -291,6 +385,11 @@ type GuildMemberUpdate struct {
 	Roles   []string      `json:"roles"`
 	User    *discord.User `json:"user"`
 	Nick    string        `json:"nick"`
+	// Time until which the member is timed out, zero if it is not.
+	CommunicationDisabledUntil discord.Time `json:"communication_disabled_until"`
+	// Old is the member as it was before this update. It
+	// is only set if the member was in the State.
+	Old *discord.GuildMember `json:"-"`
 }
 
 type guildMemberUpdateHandler func(*GuildMemberUpdate)
@@ -309,6 +408,17 @@ func (c *Client) OnGuildMemberUpdate(f func(m *GuildMemberUpdate)) {
 type GuildMembersChunk struct {
 	GuildID string                `json:"guild_id"`
 	Members []discord.GuildMember `json:"members"`
+	// Index of this chunk in the expected chunks for this response
+	// (0 <= ChunkIndex < ChunkCount).
+	ChunkIndex int `json:"chunk_index"`
+	// Total number of expected chunks for this response.
+	ChunkCount int `json:"chunk_count"`
+	// IDs of users that were requested but not found.
+	NotFound []string `json:"not_found"`
+	// Presences of the members, if they were requested.
+	Presences []discord.Presence `json:"presences"`
+	// Nonce used in the request, if any.
+	Nonce string `json:"nonce"`
 }
 
 type guildMembersChunkHandler func(*GuildMembersChunk)
@@ -342,19 +452,43 @@ func (c *Client) OnGuildRoleCreate(f func(r *GuildRole)) {
 	c.registerHandler(eventGuildRoleCreate, guildRoleCreateHandler(f))
 }
 
+// GuildRoleUpdate is sent when a guild role is updated.
+type GuildRoleUpdate struct {
+	*GuildRole
+	// Old is the role as it was before this update. It
+	// is only set if the role was in the State.
+	Old *discord.Role `json:"-"`
+}
+
 type guildRoleUpdateHandler func(*GuildRole)
 
 // handle implements the handler interface.
 func (h guildRoleUpdateHandler) handle(v interface{}) {
-	h(v.(*GuildRole))
+	h(v.(*GuildRoleUpdate).GuildRole)
 }
 
 // OnGuildRoleUpdate registers the handler function for the "GUILD_ROLE_UPDATE" event.
 // Fired when a guild role is updated.
+// Use OnGuildRoleUpdateWithOld to also receive the role as it was before this update.
 func (c *Client) OnGuildRoleUpdate(f func(r *GuildRole)) {
 	c.registerHandler(eventGuildRoleUpdate, guildRoleUpdateHandler(f))
 }
 
+type guildRoleUpdateWithOldHandler func(*GuildRoleUpdate)
+
+// handle implements the handler interface.
+func (h guildRoleUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*GuildRoleUpdate))
+}
+
+// OnGuildRoleUpdateWithOld is like OnGuildRoleUpdate but the handler also receives the role
+// as it was before this update, if it was in the State.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnGuildRoleUpdate, if any, and vice versa.
+func (c *Client) OnGuildRoleUpdateWithOld(f func(r *GuildRoleUpdate)) {
+	c.registerHandler(eventGuildRoleUpdate, guildRoleUpdateWithOldHandler(f))
+}
+
 type GuildRoleDelete struct {
 	GuildID string `json:"guild_id"`
 	RoleID  string `json:"role_id"`
@@ -373,6 +507,214 @@ func (c *Client) OnGuildRoleDelete(f func(r *GuildRoleDelete)) {
 	c.registerHandler(eventGuildRoleDelete, guildRoleDeleteHandler(f))
 }
 
+type guildScheduledEventCreateHandler func(*discord.ScheduledEvent)
+
+// handle implements the handler interface.
+func (h guildScheduledEventCreateHandler) handle(v interface{}) {
+	h(v.(*discord.ScheduledEvent))
+}
+
+// OnGuildScheduledEventCreate registers the handler function for the "GUILD_SCHEDULED_EVENT_CREATE" event.
+// Fired when a scheduled event is created in a guild.
+func (c *Client) OnGuildScheduledEventCreate(f func(e *discord.ScheduledEvent)) {
+	c.registerHandler(eventGuildScheduledEventCreate, guildScheduledEventCreateHandler(f))
+}
+
+// ScheduledEventUpdate is sent when a guild scheduled event is updated.
+type ScheduledEventUpdate struct {
+	*discord.ScheduledEvent
+	// Old is the scheduled event as it was before this
+	// update. It is only set if the event was in the State.
+	Old *discord.ScheduledEvent `json:"-"`
+}
+
+type guildScheduledEventUpdateHandler func(*ScheduledEventUpdate)
+
+// handle implements the handler interface.
+func (h guildScheduledEventUpdateHandler) handle(v interface{}) {
+	h(v.(*ScheduledEventUpdate))
+}
+
+// OnGuildScheduledEventUpdate registers the handler function for the "GUILD_SCHEDULED_EVENT_UPDATE" event.
+// Fired when a scheduled event of a guild is updated, which includes when it starts, ends or is canceled.
+func (c *Client) OnGuildScheduledEventUpdate(f func(e *ScheduledEventUpdate)) {
+	c.registerHandler(eventGuildScheduledEventUpdate, guildScheduledEventUpdateHandler(f))
+}
+
+type guildScheduledEventDeleteHandler func(*discord.ScheduledEvent)
+
+// handle implements the handler interface.
+func (h guildScheduledEventDeleteHandler) handle(v interface{}) {
+	h(v.(*discord.ScheduledEvent))
+}
+
+// OnGuildScheduledEventDelete registers the handler function for the "GUILD_SCHEDULED_EVENT_DELETE" event.
+// Fired when a scheduled event of a guild is deleted.
+func (c *Client) OnGuildScheduledEventDelete(f func(e *discord.ScheduledEvent)) {
+	c.registerHandler(eventGuildScheduledEventDelete, guildScheduledEventDeleteHandler(f))
+}
+
+// GuildScheduledEventUser is sent when a user subscribes to
+// or unsubscribes from a scheduled event of a guild.
+type GuildScheduledEventUser struct {
+	ScheduledEventID string `json:"guild_scheduled_event_id"`
+	UserID           string `json:"user_id"`
+	GuildID          string `json:"guild_id"`
+}
+
+type guildScheduledEventUserAddHandler func(*GuildScheduledEventUser)
+
+// handle implements the handler interface.
+func (h guildScheduledEventUserAddHandler) handle(v interface{}) {
+	h(v.(*GuildScheduledEventUser))
+}
+
+// OnGuildScheduledEventUserAdd registers the handler function for the "GUILD_SCHEDULED_EVENT_USER_ADD" event.
+// Fired when a user subscribes to a scheduled event of a guild.
+func (c *Client) OnGuildScheduledEventUserAdd(f func(u *GuildScheduledEventUser)) {
+	c.registerHandler(eventGuildScheduledEventUserAdd, guildScheduledEventUserAddHandler(f))
+}
+
+type guildScheduledEventUserRemoveHandler func(*GuildScheduledEventUser)
+
+// handle implements the handler interface.
+func (h guildScheduledEventUserRemoveHandler) handle(v interface{}) {
+	h(v.(*GuildScheduledEventUser))
+}
+
+// OnGuildScheduledEventUserRemove registers the handler function for the "GUILD_SCHEDULED_EVENT_USER_REMOVE" event.
+// Fired when a user unsubscribes from a scheduled event of a guild.
+func (c *Client) OnGuildScheduledEventUserRemove(f func(u *GuildScheduledEventUser)) {
+	c.registerHandler(eventGuildScheduledEventUserRemove, guildScheduledEventUserRemoveHandler(f))
+}
+
+type stageInstanceCreateHandler func(*discord.StageInstance)
+
+// handle implements the handler interface.
+func (h stageInstanceCreateHandler) handle(v interface{}) {
+	h(v.(*discord.StageInstance))
+}
+
+// OnStageInstanceCreate registers the handler function for the "STAGE_INSTANCE_CREATE" event.
+// Fired when a stage instance is created, i.e. when a stage channel goes live.
+func (c *Client) OnStageInstanceCreate(f func(s *discord.StageInstance)) {
+	c.registerHandler(eventStageInstanceCreate, stageInstanceCreateHandler(f))
+}
+
+// StageInstanceUpdate is sent when a stage instance is updated.
+type StageInstanceUpdate struct {
+	*discord.StageInstance
+	// Old is the stage instance as it was before this
+	// update. It is only set if it was in the State.
+	Old *discord.StageInstance `json:"-"`
+}
+
+type stageInstanceUpdateHandler func(*StageInstanceUpdate)
+
+// handle implements the handler interface.
+func (h stageInstanceUpdateHandler) handle(v interface{}) {
+	h(v.(*StageInstanceUpdate))
+}
+
+// OnStageInstanceUpdate registers the handler function for the "STAGE_INSTANCE_UPDATE" event.
+// Fired when a stage instance is updated.
+func (c *Client) OnStageInstanceUpdate(f func(s *StageInstanceUpdate)) {
+	c.registerHandler(eventStageInstanceUpdate, stageInstanceUpdateHandler(f))
+}
+
+type stageInstanceDeleteHandler func(*discord.StageInstance)
+
+// handle implements the handler interface.
+func (h stageInstanceDeleteHandler) handle(v interface{}) {
+	h(v.(*discord.StageInstance))
+}
+
+// OnStageInstanceDelete registers the handler function for the "STAGE_INSTANCE_DELETE" event.
+// Fired when a stage instance is deleted, i.e. when a stage channel stops being live.
+func (c *Client) OnStageInstanceDelete(f func(s *discord.StageInstance)) {
+	c.registerHandler(eventStageInstanceDelete, stageInstanceDeleteHandler(f))
+}
+
+type autoModerationRuleCreateHandler func(*discord.AutoModerationRule)
+
+// handle implements the handler interface.
+func (h autoModerationRuleCreateHandler) handle(v interface{}) {
+	h(v.(*discord.AutoModerationRule))
+}
+
+// OnAutoModerationRuleCreate registers the handler function for the "AUTO_MODERATION_RULE_CREATE" event.
+// Fired when an auto moderation rule is created.
+func (c *Client) OnAutoModerationRuleCreate(f func(r *discord.AutoModerationRule)) {
+	c.registerHandler(eventAutoModerationRuleCreate, autoModerationRuleCreateHandler(f))
+}
+
+type autoModerationRuleUpdateHandler func(*discord.AutoModerationRule)
+
+// handle implements the handler interface.
+func (h autoModerationRuleUpdateHandler) handle(v interface{}) {
+	h(v.(*discord.AutoModerationRule))
+}
+
+// OnAutoModerationRuleUpdate registers the handler function for the "AUTO_MODERATION_RULE_UPDATE" event.
+// Fired when an auto moderation rule is updated.
+func (c *Client) OnAutoModerationRuleUpdate(f func(r *discord.AutoModerationRule)) {
+	c.registerHandler(eventAutoModerationRuleUpdate, autoModerationRuleUpdateHandler(f))
+}
+
+type autoModerationRuleDeleteHandler func(*discord.AutoModerationRule)
+
+// handle implements the handler interface.
+func (h autoModerationRuleDeleteHandler) handle(v interface{}) {
+	h(v.(*discord.AutoModerationRule))
+}
+
+// OnAutoModerationRuleDelete registers the handler function for the "AUTO_MODERATION_RULE_DELETE" event.
+// Fired when an auto moderation rule is deleted.
+func (c *Client) OnAutoModerationRuleDelete(f func(r *discord.AutoModerationRule)) {
+	c.registerHandler(eventAutoModerationRuleDelete, autoModerationRuleDeleteHandler(f))
+}
+
+// AutoModerationActionExecution is sent when an auto moderation rule is
+// triggered and an action is executed (e.g. a message is blocked).
+type AutoModerationActionExecution struct {
+	GuildID         string                            `json:"guild_id"`
+	Action          discord.AutoModerationAction      `json:"action"`
+	RuleID          string                            `json:"rule_id"`
+	RuleTriggerType discord.AutoModerationTriggerType `json:"rule_trigger_type"`
+	// ID of the user which generated the content which triggered the rule.
+	UserID string `json:"user_id"`
+	// ID of the channel in which user content was posted, if any.
+	ChannelID string `json:"channel_id"`
+	// ID of any user message which content belongs to. Not set if
+	// the message was blocked by auto moderation or content was not
+	// part of any message.
+	MessageID string `json:"message_id"`
+	// ID of any system auto moderation messages posted as a
+	// result of this action.
+	AlertSystemMessageID string `json:"alert_system_message_id"`
+	// User-generated text content. Empty for applications
+	// that do not have access to message content.
+	Content string `json:"content"`
+	// Word or phrase configured in the rule that triggered the rule.
+	MatchedKeyword string `json:"matched_keyword"`
+	// Substring in content that triggered the rule. Empty for
+	// applications that do not have access to message content.
+	MatchedContent string `json:"matched_content"`
+}
+
+type autoModerationActionExecutionHandler func(*AutoModerationActionExecution)
+
+// handle implements the handler interface.
+func (h autoModerationActionExecutionHandler) handle(v interface{}) {
+	h(v.(*AutoModerationActionExecution))
+}
+
+// OnAutoModerationActionExecution registers the handler function for the "AUTO_MODERATION_ACTION_EXECUTION" event.
+// Fired when an auto moderation rule is triggered and an action is executed.
+func (c *Client) OnAutoModerationActionExecution(f func(e *AutoModerationActionExecution)) {
+	c.registerHandler(eventAutoModerationActionExecution, autoModerationActionExecutionHandler(f))
+}
+
 type GuildInviteCreate struct {
 	ChannelID      string        `json:"channel_id"`
 	Code           string        `json:"code"`
@@ -432,23 +774,53 @@ func (c *Client) OnMessageCreate(f func(m *discord.Message)) {
 	c.registerHandler(eventMessageCreate, messageCreateHandler(f))
 }
 
+// MessageUpdate is sent when a message is updated.
+type MessageUpdate struct {
+	*discord.Message
+	// Old is the message as it was before this update. It is only
+	// set if the message was in the State's message cache.
+	// See WithMessageCache for more information.
+	Old *discord.Message `json:"-"`
+}
+
 type messageUpdateHandler func(*discord.Message)
 
 // handle implements the handler interface.
 func (h messageUpdateHandler) handle(v interface{}) {
-	h(v.(*discord.Message))
+	h(v.(*MessageUpdate).Message)
 }
 
 // OnMessageUpdate registers the handler function for the "MESSAGE_UPDATE" event.
 // Fired when a message is updated. Unlike creates, message updates may contain only
 // a subset of the full message object payload (but will always contain an id and channel_id).
+// Use OnMessageUpdateWithOld to also receive the message as it was before this update.
 func (c *Client) OnMessageUpdate(f func(m *discord.Message)) {
 	c.registerHandler(eventMessageUpdate, messageUpdateHandler(f))
 }
 
+type messageUpdateWithOldHandler func(*MessageUpdate)
+
+// handle implements the handler interface.
+func (h messageUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*MessageUpdate))
+}
+
+// OnMessageUpdateWithOld is like OnMessageUpdate but the handler also receives the
+// message as it was before this update, if it was in the State's message cache.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnMessageUpdate, if any, and vice versa.
+func (c *Client) OnMessageUpdateWithOld(f func(m *MessageUpdate)) {
+	c.registerHandler(eventMessageUpdate, messageUpdateWithOldHandler(f))
+}
+
 type MessageDelete struct {
+	GuildID   string `json:"guild_id"`
 	ChannelID string `json:"channel_id"`
 	MessageID string `json:"id"`
+	// Message is the deleted message. It is only set if the message
+	// was in the State's message cache.
+	// See WithMessageCache for more information.
+	Message *discord.Message `json:"-"`
 }
 
 type messageDeleteHandler func(*MessageDelete)
@@ -468,6 +840,9 @@ type MessageDeleteBulk struct {
 	GuildID   string   `json:"guild_id"`
 	ChannelID string   `json:"channel_id"`
 	IDs       []string `json:"ids"`
+	// Messages are the deleted messages that were in the State's
+	// message cache. See WithMessageCache for more information.
+	Messages []discord.Message `json:"-"`
 }
 
 type messageDeleteBulkHandler func(*MessageDeleteBulk)
@@ -573,11 +948,19 @@ func (c *Client) OnMessageReactionRemoveEmoji(f func(r *MessageReactionRemoveEmo
 	c.registerHandler(eventMessageReactionRemoveEmoji, messageReactionRemoveEmojiHandler(f))
 }
 
+// PresenceUpdate is sent when a user's presence is updated.
+type PresenceUpdate struct {
+	*discord.Presence
+	// Old is the presence as it was before this update. It
+	// is only set if the presence was in the State.
+	Old *discord.Presence `json:"-"`
+}
+
 type presenceUpdateHandler func(*discord.Presence)
 
 // handle implements the handler interface.
 func (h presenceUpdateHandler) handle(v interface{}) {
-	h(v.(*discord.Presence))
+	h(v.(*PresenceUpdate).Presence)
 }
 
 // OnPresenceUpdate registers the handler function for the "PRESENCE_UPDATE" event.
@@ -586,10 +969,26 @@ func (h presenceUpdateHandler) handle(v interface{}) {
 // is the id field, everything else is optional. Along with this limitation, no fields
 // are required, and the types of the fields are not validated. Your client should expect
 // any combination of fields and types within this event.
+// Use OnPresenceUpdateWithOld to also receive the presence as it was before this update.
 func (c *Client) OnPresenceUpdate(f func(p *discord.Presence)) {
 	c.registerHandler(eventPresenceUpdate, presenceUpdateHandler(f))
 }
 
+type presenceUpdateWithOldHandler func(*PresenceUpdate)
+
+// handle implements the handler interface.
+func (h presenceUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*PresenceUpdate))
+}
+
+// OnPresenceUpdateWithOld is like OnPresenceUpdate but the handler also receives the presence
+// as it was before this update, if it was in the State.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnPresenceUpdate, if any, and vice versa.
+func (c *Client) OnPresenceUpdateWithOld(f func(p *PresenceUpdate)) {
+	c.registerHandler(eventPresenceUpdate, presenceUpdateWithOldHandler(f))
+}
+
 type TypingStart struct {
 	ChannelID string `json:"channel_id"`
 	GuildID   string `json:"guild_id"`
@@ -610,19 +1009,43 @@ func (c *Client) OnTypingStart(f func(ts *TypingStart)) {
 	c.registerHandler(eventTypingStart, typingStartHandler(f))
 }
 
+// UserUpdate is sent when properties about the current user change.
+type UserUpdate struct {
+	*discord.User
+	// Old is the user as it was before this update.
+	// It is only set if state tracking is enabled.
+	Old *discord.User `json:"-"`
+}
+
 type userUpdateHandler func(*discord.User)
 
 // handle implements the handler interface.
 func (h userUpdateHandler) handle(v interface{}) {
-	h(v.(*discord.User))
+	h(v.(*UserUpdate).User)
 }
 
 // OnUserUpdate registers the handler function for the "USER_UPDATE" event.
 // Fired when properties about the user change.
+// Use OnUserUpdateWithOld to also receive the user as it was before this update.
 func (c *Client) OnUserUpdate(f func(u *discord.User)) {
 	c.registerHandler(eventUserUpdate, userUpdateHandler(f))
 }
 
+type userUpdateWithOldHandler func(*UserUpdate)
+
+// handle implements the handler interface.
+func (h userUpdateWithOldHandler) handle(v interface{}) {
+	h(v.(*UserUpdate))
+}
+
+// OnUserUpdateWithOld is like OnUserUpdate but the handler also receives the user
+// as it was before this update, if it was tracked by the State.
+// Only one handler can be registered for an event, so this replaces the handler
+// registered with OnUserUpdate, if any, and vice versa.
+func (c *Client) OnUserUpdateWithOld(f func(u *UserUpdate)) {
+	c.registerHandler(eventUserUpdate, userUpdateWithOldHandler(f))
+}
+
 type voiceStateUpdateHandler func(*voice.StateUpdate)
 
 // handle implements the handler interface.
@@ -632,6 +1055,8 @@ func (h voiceStateUpdateHandler) handle(v interface{}) {
 
 // OnVoiceStateUpdate registers the handler function for the "VOICE_STATE_UPDATE" event.
 // Fired when someone joins/leaves/moves voice channels.
+// Use OnVoiceChannelJoin, OnVoiceChannelLeave or OnVoiceChannelMove to only
+// handle one of those scenarios, if the State caches voice states.
 func (c *Client) OnVoiceStateUpdate(f func(update *voice.StateUpdate)) {
 	c.registerHandler(eventVoiceStateUpdate, voiceStateUpdateHandler(f))
 }
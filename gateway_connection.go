@@ -20,19 +20,63 @@ const gatewayEncoding = "json"
 // errMustReconnect is an internal error used to signal that we need to reconnect to the Gateway.
 var errMustReconnect = errors.New("must reconnect to the Gateway")
 
+// errReconnectAborted is an internal error used to signal that Disconnect
+// was called while the client was trying to reconnect to the Gateway.
+var errReconnectAborted = errors.New("client disconnected while reconnecting")
+
 // Connect connects and identifies the client to the Discord Gateway.
+// See WithConnectWaitForGuilds to make it block until all guilds are received.
 func (c *Client) Connect(ctx context.Context) error {
+	resuming, err := c.connectAndNotify(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Wait without holding the lock so concurrent calls to
+	// Disconnect or Connect are not blocked in the meantime.
+	if c.connectWaitForGuilds && !resuming {
+		c.logger.Debug("waiting for guilds to be received")
+		c.waitForGuilds(ctx)
+	}
+
+	return nil
+}
+
+// connectAndNotify is like connect but also fires the Connected event on success.
+func (c *Client) connectAndNotify(ctx context.Context) (resuming bool, err error) {
+	resuming, err = c.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Fired once the lock is released so handlers can
+	// call Connect or Disconnect, whatever the dispatch mode.
+	c.handle(eventConnected, &Connected{Resuming: resuming})
+	return resuming, nil
+}
+
+// connect opens a new connection to the Gateway, identifying or resuming
+// the previous session. It reports whether the session is being resumed.
+func (c *Client) connect(ctx context.Context) (resuming bool, err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.isConnected() {
-		return discord.ErrGatewayAlreadyConnected
+		return false, discord.ErrGatewayAlreadyConnected
+	}
+
+	// If this is not an automatic reconnection, this is a fresh
+	// start and the previous termination, if any, is forgotten.
+	// Otherwise, Disconnect may have been called while waiting for the lock.
+	if !c.isReconnecting() {
+		c.resetDone()
+	} else if c.isDone() {
+		return false, errReconnectAborted
 	}
 
 	c.connecting.Store(true)
 	defer c.connecting.Store(false)
 
-	var err error
 	// Get the Gateway endpoint if we don't have one cached yet.
 	if c.gatewayURL == "" {
 		// NOTE: not using GatewayBot here because a Client has no
@@ -40,7 +84,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		// when creating a Client with the WithSharding option.
 		c.gatewayURL, err = c.Gateway(ctx)
 		if err != nil {
-			return fmt.Errorf("could not get gateway URL: %w", err)
+			return false, fmt.Errorf("could not get gateway URL: %w", err)
 		}
 	}
 
@@ -62,7 +106,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.logger.Debugf("connecting to the gateway: %s", gwURL)
 	c.conn, _, err = websocket.Dial(ctx, gwURL, &websocket.DialOptions{HTTPHeader: header})
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// If any error occurs during the connection process, we
@@ -85,39 +129,51 @@ func (c *Client) Connect(ctx context.Context) error {
 	// interval we must use when we connect to the websocket.
 	p, err := c.recvPayload()
 	if err != nil {
-		return fmt.Errorf("could not receive payload from gateway: %w", err)
+		return false, fmt.Errorf("could not receive payload from gateway: %w", err)
 	}
 	if p.Op != gatewayOpcodeHello {
-		return fmt.Errorf("expected Opcode 10 Hello; got Opcode %d", p.Op)
+		return false, fmt.Errorf("expected Opcode 10 Hello; got Opcode %d", p.Op)
 	}
 
 	var hello struct {
 		HeartbeatInterval int `json:"heartbeat_interval"`
 	}
 	if err = json.Unmarshal(p.D, &hello); err != nil {
-		return err
+		return false, err
 	}
 
+	// Start the dispatcher workers before receiving any event, so the Ready
+	// event is handled in order with the events that follow it.
+	c.dispatcher.start()
+	defer func() {
+		// Leave them running when reconnecting, the Client will either
+		// try again or terminate, which stops them.
+		if err != nil && !c.isReconnecting() {
+			c.dispatcher.stop()
+		}
+	}()
+
 	// If the sequence number is 0 and we don't have a
 	// session ID, we must identify to the Gateway to
 	// create a new session, else this means we have already
 	// been connected to the Gateway with this client and
 	// we should try to resume a previous connection.
 	seq := c.sequence.Load()
-	if seq == 0 && c.sessionID == "" {
+	resuming = seq != 0 || c.sessionID.Load() != ""
+	if !resuming {
 		c.logger.Debug("identifying to the gateway")
 		if err = c.identify(ctx); err != nil {
-			return err
+			return false, err
 		}
 
 		// The Gateway should send us a Ready event if we successfully authenticated.
 		if err = c.recvReady(); err != nil {
-			return err
+			return false, err
 		}
 	} else {
-		c.logger.Debugf("trying to resume an existing session (seq=%d; sessID=%q)", seq, c.sessionID)
+		c.logger.Debugf("trying to resume an existing session (seq=%d; sessID=%q)", seq, c.sessionID.Load())
 		if err = c.resume(ctx); err != nil {
-			return err
+			return false, err
 		}
 		// The Gateway should replay events we missed since we were disconnected
 		// and then send us a Resumed payload. All of this is handled by the
@@ -135,7 +191,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.wg.Add(1)
 	go c.listenAndHandlePayloads()
 
-	return nil
+	return resuming, nil
 }
 
 // Disconnect closes the connection to the Discord Gateway.
@@ -171,10 +227,92 @@ func (c *Client) Disconnect() {
 	// Wait for all voice connections to be closed.
 	wg.Wait()
 
-	// Then, signal the connection manager and other goroutines that we want to disconnect.
-	close(c.stop)
-	// Properly wait for all goroutines to exit.
-	c.wg.Wait()
+	select {
+	case <-c.stop:
+		// The connection is already closed, meaning the client is waiting
+		// between two reconnection attempts. Terminating is enough to stop it.
+	default:
+		// Signal the connection manager and other goroutines that we want to disconnect.
+		close(c.stop)
+		// Stop the dispatcher workers now, in case the Gateway listener is waiting for
+		// room in the queue of the worker that called Disconnect from a handler.
+		c.dispatcher.stop()
+		// Properly wait for all goroutines to exit.
+		c.wg.Wait()
+	}
+
+	c.terminate(nil)
+}
+
+// Done returns a channel that is closed when the connection to the Gateway is
+// terminated for good, either because Disconnect was called or because an error
+// the client can not recover from occurred (see Err). Temporary failures the client
+// automatically recovers from do not close this channel.
+// Calling Connect again after Done is closed makes it return a new channel.
+func (c *Client) Done() <-chan struct{} {
+	c.doneMu.Lock()
+	defer c.doneMu.Unlock()
+
+	return c.done
+}
+
+// Err returns the error that terminated the connection to the Gateway. It returns
+// nil if Done is not yet closed or if the connection was terminated by a call to
+// Disconnect. Errors sent by the Gateway when it closes the connection are returned
+// as *discord.GatewayCloseError.
+func (c *Client) Err() error {
+	c.doneMu.Lock()
+	defer c.doneMu.Unlock()
+
+	return c.doneErr
+}
+
+// terminate closes the done channel, setting err as the reason of the termination,
+// and stops the dispatcher workers, if any.
+// Calls made after the done channel has been closed are no-ops.
+func (c *Client) terminate(err error) {
+	c.doneMu.Lock()
+	defer c.doneMu.Unlock()
+
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+
+	c.doneErr = err
+	close(c.done)
+	c.dispatcher.stop()
+}
+
+// isDone reports whether the done channel is closed.
+func (c *Client) isDone() bool {
+	select {
+	case <-c.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// resetDone replaces the done channel with a new one if it was closed.
+func (c *Client) resetDone() {
+	c.doneMu.Lock()
+	defer c.doneMu.Unlock()
+
+	select {
+	case <-c.done:
+		c.done = make(chan struct{})
+		c.doneErr = nil
+	default:
+	}
+}
+
+// fail reports an error the client can not recover from and terminates the
+// connection to the Gateway.
+func (c *Client) fail(err error) {
+	c.handle(eventPermanentFailure, &PermanentFailure{Err: err})
+	c.terminate(err)
 }
 
 // wait waits for an error to happen while connected to the Gateway
@@ -202,12 +340,26 @@ func (c *Client) wait() {
 	c.cancel()
 	c.connected.Store(false)
 
+	// Chunks of pending guild members requests will never be received.
+	c.failMembersRequests(err)
+
 	c.wg.Done()
 	c.wg.Wait()
 
+	reconnect := shouldReconnect(err)
+	code := -1
+	if err == nil {
+		code = int(websocket.StatusNormalClosure)
+	} else if status := websocket.CloseStatus(err); status != -1 {
+		code = int(status)
+	}
+	c.handle(eventDisconnected, &Disconnected{Code: code, Err: err, WillReconnect: reconnect})
+
 	// If there was an error, try to reconnect depending on its code.
-	if shouldReconnect(err) {
-		c.reconnectWithBackoff()
+	if reconnect {
+		c.reconnectWithBackoff(err)
+	} else if err != nil {
+		c.fail(err)
 	}
 }
 
@@ -222,56 +374,76 @@ func shouldReconnect(err error) bool {
 		return true
 	}
 
-	switch websocket.CloseStatus(err) {
-	case 4001, 4002, 4003, 4004, 4005, 4010, 4011, 4012, 4013, 4014:
-		return false
-	case 4000, 4007, 4008, 4009:
-		return true
-	case -1: // Not a websocket error.
-		return true
-	default: // New (or undocumented?) close status code.
-		return true
+	var closeErr *discord.GatewayCloseError
+	if errors.As(err, &closeErr) {
+		return closeErr.Code.Recoverable()
+	}
+
+	// Not a Gateway close error.
+	return true
+}
+
+// closeError converts errors caused by the Gateway closing the connection with one of
+// its close codes to a *discord.GatewayCloseError. Other errors are returned as is.
+func (c *Client) closeError(err error) error {
+	var ce websocket.CloseError
+	if !errors.As(err, &ce) || ce.Code < 4000 || ce.Code > 4999 {
+		return err
 	}
+
+	return discord.NewGatewayCloseError(discord.GatewayCloseCode(ce.Code), ce.Reason, c.intents, err)
 }
 
 // reconnectWithBackoff attempts to reconnect to the Gateway using the Client's
-// backoff strategy.
-func (c *Client) reconnectWithBackoff() {
+// backoff strategy. err is the error that caused the connection to be lost.
+// Unlike Connect, it never waits for guilds to be received, even if the
+// session could not be resumed.
+// The stop channel of the lost connection is already closed at this point,
+// calling Disconnect terminates the client instead, which stops the attempts.
+func (c *Client) reconnectWithBackoff(err error) {
 	c.reconnecting.Store(true)
 	defer c.reconnecting.Store(false)
 
 	c.logger.Debug("trying to reconnect to the gateway")
 
 	for i := 0; true; i++ {
-		// Try to establish a new connection with a 30 seconds timeout.
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-
-		if err := c.Connect(ctx); err != nil {
-			cancel()
-
-			if !shouldReconnect(err) {
-				c.logger.Error("invalid Gateway session, can not recover: %v", err)
-				return
-			}
+		// The first attempt is made right away.
+		var delay time.Duration
+		if i > 0 {
+			delay = c.backoff.ForAttempt(i - 1)
+			c.logger.Errorf("failed to reconnect: %v, retrying in %s", err, delay)
+		}
+		if !c.isDone() {
+			c.handle(eventReconnectAttempt, &ReconnectAttempt{Attempt: i + 1, Delay: delay, Err: err})
+		}
 
-			duration := c.backoff.ForAttempt(i)
-			c.logger.Errorf("failed to reconnect: %v, retrying in %s", err, duration)
+		select {
+		case <-time.After(delay):
+		case <-c.Done():
+		}
+		// Checked before each attempt, including the first one.
+		if c.isDone() {
+			// Client called Disconnect(), stop trying to reconnect.
+			c.logger.Info("client called Disconnect while trying to reconnect to the gateway, aborting")
+			return
+		}
 
-			select {
-			case <-time.After(duration):
-				continue // Make a new connection attempt.
-			case <-c.stop:
-				// Client called Disconnect(), stop trying to reconnect.
-				c.logger.Info("client called Disconnect while trying to reconnect to the gateway, aborting")
-				return
-			}
-		} else {
-			cancel()
+		// Try to establish a new connection with a 30 seconds timeout.
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, err = c.connectAndNotify(ctx)
+		cancel()
 
+		if err == nil {
 			// We could reconnect.
 			c.logger.Info("successfully reconnected to the gateway")
 			return
 		}
+
+		if !shouldReconnect(err) {
+			c.logger.Errorf("invalid Gateway session, can not recover: %v", err)
+			c.fail(err)
+			return
+		}
 	}
 }
 
@@ -349,6 +521,6 @@ func (c *Client) isReconnecting() bool {
 // After a session reset, a call to Connect will send an Identify payload and
 // start a new fresh session, instead of trying to resume an existing session.
 func (c *Client) resetGatewaySession() {
-	c.sessionID = ""
+	c.sessionID.Store("")
 	c.sequence.Store(0)
 }
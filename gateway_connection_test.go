@@ -0,0 +1,295 @@
+package harmony
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/payload"
+	"nhooyr.io/websocket"
+)
+
+func TestLifecycleOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		// Whether the Gateway accepts to resume the session after closing
+		// the first connection. If not, the client keeps trying to reconnect.
+		resume bool
+		// Events expected in order, the first beforeDisconnect
+		// of them before the client calls Disconnect.
+		expected         []string
+		beforeDisconnect int
+	}{
+		{
+			name:   "resumed",
+			resume: true,
+			expected: []string{
+				"connected",
+				"disconnected 4000, will reconnect",
+				"reconnect attempt 1",
+				"connected, resuming",
+				"disconnected 1000",
+			},
+			beforeDisconnect: 4,
+		},
+		{
+			name: "disconnect while reconnecting",
+			expected: []string{
+				"connected",
+				"disconnected 4000, will reconnect",
+				"reconnect attempt 1",
+				"reconnect attempt 2",
+			},
+			beforeDisconnect: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			closeFirst := make(chan struct{})
+			gw := &fakeGateway{t: t, closeFirst: closeFirst, resume: tt.resume}
+			srv := httptest.NewServer(gw)
+			defer srv.Close()
+
+			c, err := NewClient("token",
+				WithLogger(testLogger),
+				WithDispatchMode(DispatchModeSync, 0),
+				WithBackoffStrategy(time.Hour, time.Hour, 1, 0),
+				WithConnectWaitForGuilds(true),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.gatewayURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+			events := make(chan string, 16)
+			c.OnConnected(func(e *Connected) {
+				if e.Resuming {
+					events <- "connected, resuming"
+					return
+				}
+				events <- "connected"
+			})
+			c.OnDisconnected(func(e *Disconnected) {
+				if e.WillReconnect {
+					events <- fmt.Sprintf("disconnected %d, will reconnect", e.Code)
+					return
+				}
+				events <- fmt.Sprintf("disconnected %d", e.Code)
+			})
+			c.OnReconnectAttempt(func(e *ReconnectAttempt) {
+				events <- fmt.Sprintf("reconnect attempt %d", e.Attempt)
+			})
+			// Not recorded since it can be handled before or after Connected.
+			resumed := make(chan struct{})
+			c.OnResumed(func(*Resumed) { close(resumed) })
+
+			var got []string
+			next := func() {
+				t.Helper()
+				select {
+				case e := <-events:
+					got = append(got, e)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for events, got %q", got)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			// Ready has no guilds, Connect must not wait.
+			if err = c.Connect(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			close(closeFirst)
+			for len(got) < tt.beforeDisconnect {
+				next()
+			}
+			if tt.resume {
+				select {
+				case <-resumed:
+				case <-time.After(5 * time.Second):
+					t.Fatal("timed out waiting for the session to be resumed")
+				}
+			}
+
+			disconnected := make(chan struct{})
+			go func() {
+				c.Disconnect()
+				close(disconnected)
+			}()
+			select {
+			case <-disconnected:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for Disconnect to return")
+			}
+			select {
+			case <-c.Done():
+			default:
+				t.Error("expected client to be terminated")
+			}
+			for len(got) < len(tt.expected) {
+				next()
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected events %q; got %q", tt.expected, got)
+			}
+			select {
+			case e := <-events:
+				t.Errorf("unexpected event %q after disconnecting", e)
+			case <-time.After(10 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestFatalClose(t *testing.T) {
+	closeFirst := make(chan struct{})
+	gw := &fakeGateway{t: t, closeFirst: closeFirst, closeCode: discord.GatewayCloseCodeDisallowedIntents}
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+
+	c, err := NewClient("token", WithLogger(testLogger), WithGatewayIntents(discord.GatewayIntentGuildPresences))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.gatewayURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	failed := make(chan *PermanentFailure, 1)
+	c.OnPermanentFailure(func(f *PermanentFailure) { failed <- f })
+
+	if err = c.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.Err() != nil {
+		t.Errorf("expected no error while connected; got %v", c.Err())
+	}
+	close(closeFirst)
+
+	select {
+	case <-c.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the client to terminate")
+	}
+
+	var closeErr *discord.GatewayCloseError
+	if !errors.As(c.Err(), &closeErr) {
+		t.Fatalf("expected a gateway close error; got %v", c.Err())
+	}
+	if closeErr.Code != discord.GatewayCloseCodeDisallowedIntents || closeErr.Intents != discord.GatewayIntentGuildPresences {
+		t.Errorf("expected disallowed presence intent; got %v", closeErr)
+	}
+	select {
+	case f := <-failed:
+		if f.Err != c.Err() {
+			t.Errorf("expected permanent failure with %v; got %v", c.Err(), f.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("timed out waiting for the permanent failure event")
+	}
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if gw.conns != 1 {
+		t.Errorf("expected client not to reconnect; got %d connections", gw.conns)
+	}
+}
+
+// fakeGateway is a minimal Discord Gateway. It closes the first connection with
+// closeCode (unknown error by default) once closeFirst is closed and, if resume is
+// set, accepts to resume the session on the next connection. Otherwise, it refuses
+// new connections.
+type fakeGateway struct {
+	t          *testing.T
+	closeFirst chan struct{}
+	closeCode  discord.GatewayCloseCode
+	resume     bool
+
+	mu    sync.Mutex
+	conns int
+}
+
+func (g *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	g.conns++
+	n := g.conns
+	g.mu.Unlock()
+
+	if n > 1 && !g.resume {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		g.t.Error(err)
+		return
+	}
+	defer conn.Close(websocket.StatusInternalError, "")
+	ctx := r.Context()
+
+	g.send(ctx, conn, &payload.Payload{Op: gatewayOpcodeHello, D: json.RawMessage(`{"heartbeat_interval":45000}`)})
+
+	if n == 1 {
+		g.expect(ctx, conn, gatewayOpcodeIdentify)
+		g.send(ctx, conn, &payload.Payload{
+			Op: gatewayOpcodeDispatch,
+			T:  eventReady,
+			S:  1,
+			D:  json.RawMessage(`{"session_id":"session","user":{"id":"me"},"guilds":[]}`),
+		})
+		<-g.closeFirst
+		code := g.closeCode
+		if code == 0 {
+			code = discord.GatewayCloseCodeUnknownError
+		}
+		_ = conn.Close(websocket.StatusCode(code), code.String())
+		return
+	}
+
+	g.expect(ctx, conn, gatewayOpcodeResume)
+	g.send(ctx, conn, &payload.Payload{Op: gatewayOpcodeDispatch, T: eventResumed, S: 2})
+	// Ignore heartbeats until the client disconnects.
+	for {
+		if _, _, err = conn.Read(ctx); err != nil {
+			return
+		}
+	}
+}
+
+func (g *fakeGateway) send(ctx context.Context, conn *websocket.Conn, p *payload.Payload) {
+	if err := payload.Send(ctx, conn, p); err != nil {
+		g.t.Error(err)
+	}
+}
+
+// expect reads payloads until one with the given opcode is received, ignoring heartbeats.
+func (g *fakeGateway) expect(ctx context.Context, conn *websocket.Conn, op int) {
+	for {
+		var mu sync.Mutex
+		p, err := payload.Recv(ctx, &mu, conn)
+		if err != nil {
+			g.t.Error(err)
+			return
+		}
+		if p.Op == op {
+			return
+		}
+		if p.Op != gatewayOpcodeHeartbeat {
+			g.t.Errorf("expected opcode %d; got %d", op, p.Op)
+			return
+		}
+	}
+}
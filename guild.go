@@ -8,16 +8,17 @@ import (
 	"github.com/skwair/harmony/discord"
 	"github.com/skwair/harmony/internal/endpoint"
 	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/optional"
 )
 
-// CreateGuild creates a new guild with the given name.
+// CreateGuild creates a new guild with the given name. It can be further configured
+// with options, such as the roles and channels it is created with. AFK and system
+// channels set with options must be placeholder IDs of channels it is created with.
+// Can only be used by bots in less than 10 guilds.
 // Returns the created guild on success. Fires a Guild Create Gateway event.
-func (c *Client) CreateGuild(ctx context.Context, name string) (*discord.Guild, error) {
-	s := struct {
-		Name string `json:"name"`
-	}{
-		Name: name,
-	}
+func (c *Client) CreateGuild(ctx context.Context, name string, opts ...discord.GuildSetting) (*discord.Guild, error) {
+	s := discord.NewGuildSettings(opts...)
+	s.Name = optional.NewString(name)
 	b, err := json.Marshal(s)
 	if err != nil {
 		return nil, err
@@ -49,6 +50,7 @@ func (c *Client) CreateGuild(ctx context.Context, name string) (*discord.Guild,
 // to 1000 members per chunk until all members that match the request have been sent.
 // query is a string that username starts with, or an empty string to return all members.
 // limit is the maximum number of members to send or 0 to request all members matched.
+// See FetchGuildMembers to wait for all requested members to be received instead.
 // You need to be connected to the Gateway to call this method, else it will
 // return ErrGatewayNotConnected.
 func (c *Client) RequestGuildMembers(guildID, query string, limit int) error {
@@ -56,13 +58,9 @@ func (c *Client) RequestGuildMembers(guildID, query string, limit int) error {
 		return discord.ErrGatewayNotConnected
 	}
 
-	req := struct {
-		GuildID string `json:"guild_id"`
-		Query   string `json:"query"`
-		Limit   int    `json:"limit"`
-	}{
+	req := requestGuildMembers{
 		GuildID: guildID,
-		Query:   query,
+		Query:   &query,
 		Limit:   limit,
 	}
 	return c.sendPayload(c.ctx, gatewayOpcodeRequestGuildMembers, &req)
@@ -0,0 +1,183 @@
+package harmony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// requestGuildMembers is the payload sent to the Gateway to request guild members.
+type requestGuildMembers struct {
+	GuildID   string   `json:"guild_id"`
+	Query     *string  `json:"query,omitempty"`
+	Limit     int      `json:"limit"`
+	Presences bool     `json:"presences,omitempty"`
+	UserIDs   []string `json:"user_ids,omitempty"`
+	Nonce     string   `json:"nonce,omitempty"`
+}
+
+// GuildMembersOption allows to customize which guild members are fetched
+// by FetchGuildMembers.
+type GuildMembersOption func(*requestGuildMembers)
+
+// WithGuildMembersQuery only fetches members whose username starts with query.
+// limit is the maximum number of members to fetch, or 0 to fetch all members
+// matching the query. An empty query with a limit of 0 fetches all members and
+// requires the GUILD_MEMBERS intent.
+func WithGuildMembersQuery(query string, limit int) GuildMembersOption {
+	return func(r *requestGuildMembers) {
+		r.Query = &query
+		r.Limit = limit
+	}
+}
+
+// WithGuildMembersUserIDs only fetches members with the given user IDs.
+// Up to 100 user IDs can be given. This option can not be used along
+// with WithGuildMembersQuery.
+func WithGuildMembersUserIDs(ids ...string) GuildMembersOption {
+	return func(r *requestGuildMembers) {
+		r.UserIDs = append(r.UserIDs, ids...)
+	}
+}
+
+// WithGuildMembersPresences also fetches presences of the fetched members.
+// Requires the GUILD_PRESENCES intent.
+func WithGuildMembersPresences() GuildMembersOption {
+	return func(r *requestGuildMembers) {
+		r.Presences = true
+	}
+}
+
+// membersRequest is a guild members request waiting for
+// all of its chunks to be received.
+type membersRequest struct {
+	// Members received so far, by chunk index.
+	chunks map[int][]discord.GuildMember
+	// Set if the request failed before all chunks were received.
+	err  error
+	done chan struct{}
+}
+
+// members returns the members of all chunks, in chunk order.
+func (mr *membersRequest) members() []discord.GuildMember {
+	var members []discord.GuildMember
+	for i := 0; i < len(mr.chunks); i++ {
+		members = append(members, mr.chunks[i]...)
+	}
+	return members
+}
+
+// FetchGuildMembers requests members of a guild through the Gateway and waits for
+// all of them to be received before returning them. By default, all members of
+// the guild are fetched, use GuildMembersOption to fetch only some of them.
+// Unlike RequestGuildMembers, members are not sent to the OnGuildMembersChunk
+// handler. They are however added to the State if it is enabled.
+// You need to be connected to the Gateway to call this method, else it will
+// return ErrGatewayNotConnected. If the connection is lost before all members are
+// received, an error wrapping ErrGatewayNotConnected is returned.
+func (c *Client) FetchGuildMembers(ctx context.Context, guildID string, opts ...GuildMembersOption) ([]discord.GuildMember, error) {
+	if !c.isConnected() {
+		return nil, discord.ErrGatewayNotConnected
+	}
+
+	req := &requestGuildMembers{GuildID: guildID}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	if req.Query != nil && len(req.UserIDs) > 0 {
+		return nil, errors.New("can not fetch guild members using both a query and user IDs")
+	}
+	if req.Query == nil && len(req.UserIDs) == 0 {
+		all := ""
+		req.Query = &all
+	}
+
+	req.Nonce = c.newMembersRequestNonce()
+	mr := &membersRequest{
+		chunks: make(map[int][]discord.GuildMember),
+		done:   make(chan struct{}),
+	}
+
+	c.membersRequestsMu.Lock()
+	c.membersRequests[req.Nonce] = mr
+	c.membersRequestsMu.Unlock()
+
+	defer func() {
+		c.membersRequestsMu.Lock()
+		delete(c.membersRequests, req.Nonce)
+		c.membersRequestsMu.Unlock()
+	}()
+
+	if err := c.sendPayload(ctx, gatewayOpcodeRequestGuildMembers, req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-mr.done:
+		if mr.err != nil {
+			return nil, mr.err
+		}
+		return mr.members(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newMembersRequestNonce returns a new nonce that can be used to
+// identify a guild members request. Nonces are at most 32 bytes long.
+func (c *Client) newMembersRequestNonce() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatInt(c.membersRequestsSeq.Inc(), 36)
+}
+
+// completeMembersRequest adds the members of the given chunk to the pending
+// members request it belongs to. It reports whether the chunk belonged to
+// a pending request.
+func (c *Client) completeMembersRequest(chunk *GuildMembersChunk) bool {
+	if chunk.Nonce == "" {
+		return false
+	}
+
+	c.membersRequestsMu.Lock()
+	defer c.membersRequestsMu.Unlock()
+
+	mr, ok := c.membersRequests[chunk.Nonce]
+	if !ok {
+		return false
+	}
+
+	// Ignore chunks we already received, the request would be
+	// considered completed before actually receiving all chunks.
+	if _, ok = mr.chunks[chunk.ChunkIndex]; ok {
+		return true
+	}
+	mr.chunks[chunk.ChunkIndex] = chunk.Members
+
+	if len(mr.chunks) >= chunk.ChunkCount {
+		close(mr.done)
+		delete(c.membersRequests, chunk.Nonce)
+	}
+	return true
+}
+
+// failMembersRequests fails all pending members requests. It is called when the
+// connection to the Gateway is lost, since remaining chunks will never be sent.
+func (c *Client) failMembersRequests(cause error) {
+	c.membersRequestsMu.Lock()
+	defer c.membersRequestsMu.Unlock()
+
+	err := fmt.Errorf("connection lost before all guild members were received: %w", discord.ErrGatewayNotConnected)
+	if cause != nil {
+		err = fmt.Errorf("%w: %v", err, cause)
+	}
+
+	for nonce, mr := range c.membersRequests {
+		mr.err = err
+		close(mr.done)
+		delete(c.membersRequests, nonce)
+	}
+}
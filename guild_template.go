@@ -0,0 +1,66 @@
+package harmony
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// GetGuildTemplate returns a guild template given its code.
+func (c *Client) GetGuildTemplate(ctx context.Context, code string) (*discord.GuildTemplate, error) {
+	e := endpoint.GetGuildTemplate(code)
+	resp, err := c.restClient.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var t discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateGuildFromTemplate creates a new guild with the given name from the guild template
+// with the given code. icon is an optional base64 encoded 128x128 image for the guild icon.
+// Can only be used by bots in less than 10 guilds.
+// Returns the created guild on success. Fires a Guild Create Gateway event.
+func (c *Client) CreateGuildFromTemplate(ctx context.Context, code, name, icon string) (*discord.Guild, error) {
+	s := struct {
+		Name string `json:"name"`
+		Icon string `json:"icon,omitempty"`
+	}{
+		Name: name,
+		Icon: icon,
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateGuildFromGuildTemplate(code)
+	resp, err := c.restClient.Do(ctx, e, rest.JSONPayload(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var g discord.Guild
+	if err = json.NewDecoder(resp.Body).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
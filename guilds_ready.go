@@ -0,0 +1,248 @@
+package harmony
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// Those events are not sent by the Gateway but derived by the Client
+// from Ready, Guild Create and Guild Delete events.
+const (
+	eventGuildsReady    = "GUILDS_READY"
+	eventGuildJoin      = "GUILD_JOIN"
+	eventGuildAvailable = "GUILD_AVAILABLE"
+)
+
+// defaultGuildsReadyTimeout is the default maximum time to wait for all
+// guilds announced in the Ready event to be received.
+const defaultGuildsReadyTimeout = 30 * time.Second
+
+// guildCreateKind describes why a Guild Create event was sent.
+type guildCreateKind int
+
+const (
+	// The guild was announced in the Ready event and is lazily loaded.
+	guildCreateLazyLoad guildCreateKind = iota
+	// The guild was unavailable because of an outage and is available again.
+	guildCreateAvailable
+	// The current user joined a new guild.
+	guildCreateJoin
+)
+
+// guildsTracker keeps track of guilds announced in the Ready event that have not
+// been received yet, as well as guilds that are unavailable because of an outage.
+type guildsTracker struct {
+	mu sync.Mutex
+
+	// Guilds announced in the Ready event we are still waiting for.
+	pending map[string]struct{}
+	// Guilds announced in the Ready event that were received.
+	loaded []string
+	// Guilds that became unavailable after the initial loading.
+	unavailable map[string]struct{}
+
+	timer *time.Timer
+	// ready is closed once all pending guilds are received or when the
+	// timeout expired.
+	ready chan struct{}
+}
+
+// GuildsReady is sent once all guilds announced in the Ready event have been
+// received through Guild Create events, or when the timeout set with
+// WithGuildsReadyTimeout expired.
+type GuildsReady struct {
+	// IDs of the guilds that were received.
+	Guilds []string
+	// IDs of the guilds that were not received before the timeout expired.
+	// If not empty, those guilds are probably unavailable because of an
+	// outage and will be sent later, as regular Guild Create events.
+	Unavailable []string
+}
+
+type guildsReadyHandler func(*GuildsReady)
+
+// handle implements the handler interface.
+func (h guildsReadyHandler) handle(v interface{}) {
+	h(v.(*GuildsReady))
+}
+
+// OnGuildsReady registers the handler function for the GuildsReady event.
+// Fired once per session, when all guilds announced in the Ready event have been
+// received or when the timeout set with WithGuildsReadyTimeout expired. When it is
+// fired, the State contains all guilds that could be loaded.
+func (c *Client) OnGuildsReady(f func(r *GuildsReady)) {
+	c.registerHandler(eventGuildsReady, guildsReadyHandler(f))
+}
+
+type guildJoinHandler func(*discord.Guild)
+
+// handle implements the handler interface.
+func (h guildJoinHandler) handle(v interface{}) {
+	h(v.(*discord.Guild))
+}
+
+// OnGuildJoin registers the handler function for the GuildJoin event.
+// Fired when the current user joins a new guild. Unlike OnGuildCreate, this
+// is not fired for guilds that are loaded after the Ready event or for guilds
+// that become available again after an outage.
+func (c *Client) OnGuildJoin(f func(g *discord.Guild)) {
+	c.registerHandler(eventGuildJoin, guildJoinHandler(f))
+}
+
+type guildAvailableHandler func(*discord.Guild)
+
+// handle implements the handler interface.
+func (h guildAvailableHandler) handle(v interface{}) {
+	h(v.(*discord.Guild))
+}
+
+// OnGuildAvailable registers the handler function for the GuildAvailable event.
+// Fired when a guild that became unavailable because of an outage is available again.
+func (c *Client) OnGuildAvailable(f func(g *discord.Guild)) {
+	c.registerHandler(eventGuildAvailable, guildAvailableHandler(f))
+}
+
+// startGuildsLoading starts tracking guilds announced in the given Ready event.
+// The GuildsReady event is fired right away if there is no guild to wait for.
+func (c *Client) startGuildsLoading(r *Ready) {
+	t := c.guilds
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	t.pending = make(map[string]struct{})
+	t.loaded = nil
+	t.unavailable = make(map[string]struct{})
+	t.ready = make(chan struct{})
+
+	for _, g := range r.Guilds {
+		if g.Unavailable == nil {
+			// We were removed from this guild.
+			continue
+		}
+		t.pending[g.ID] = struct{}{}
+	}
+
+	if len(t.pending) == 0 {
+		c.guildsReady()
+		return
+	}
+
+	ready := t.ready
+	t.timer = time.AfterFunc(c.guildsReadyTimeout, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		// Make sure this timer did not fire for a previous session.
+		if t.ready != ready {
+			return
+		}
+		c.guildsReady()
+	})
+}
+
+// guildCreated reports why a Guild Create event for the given guild was sent,
+// and fires the GuildsReady event if it was the last guild we were waiting for.
+func (c *Client) guildCreated(id string) guildCreateKind {
+	t := c.guilds
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.pending[id]; ok {
+		delete(t.pending, id)
+		t.loaded = append(t.loaded, id)
+		if len(t.pending) == 0 {
+			c.guildsReady()
+		}
+		return guildCreateLazyLoad
+	}
+
+	if _, ok := t.unavailable[id]; ok {
+		delete(t.unavailable, id)
+		return guildCreateAvailable
+	}
+
+	return guildCreateJoin
+}
+
+// guildDeleted updates tracked guilds given a Guild Delete event.
+func (c *Client) guildDeleted(g *discord.UnavailableGuild) {
+	t := c.guilds
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// We were removed from this guild, it will never be sent.
+	if g.Unavailable == nil {
+		delete(t.unavailable, g.ID)
+		if _, ok := t.pending[g.ID]; ok {
+			delete(t.pending, g.ID)
+			if len(t.pending) == 0 {
+				c.guildsReady()
+			}
+		}
+		return
+	}
+
+	// Guilds still pending are already expected to be sent later.
+	if _, ok := t.pending[g.ID]; !ok {
+		t.unavailable[g.ID] = struct{}{}
+	}
+}
+
+// guildsReady fires the GuildsReady event. It must be called with
+// the guilds tracker lock held.
+func (c *Client) guildsReady() {
+	t := c.guilds
+
+	// Already fired for this session.
+	select {
+	case <-t.ready:
+		return
+	default:
+	}
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	r := &GuildsReady{Guilds: t.loaded}
+	// Guilds that were not received in time are now considered
+	// unavailable so they are reported accordingly when received.
+	for id := range t.pending {
+		r.Unavailable = append(r.Unavailable, id)
+		t.unavailable[id] = struct{}{}
+	}
+	t.pending = make(map[string]struct{})
+
+	close(t.ready)
+	c.handle(eventGuildsReady, r)
+}
+
+// waitForGuilds blocks until the GuildsReady event of the current session is
+// fired, the connection is terminated or the given context is done.
+// It must not be called with the client lock held, since it can take as long
+// as the guilds ready timeout.
+func (c *Client) waitForGuilds(ctx context.Context) {
+	c.guilds.mu.Lock()
+	ready := c.guilds.ready
+	c.guilds.mu.Unlock()
+
+	if ready == nil {
+		return
+	}
+
+	select {
+	case <-ready:
+	case <-c.Done():
+	case <-ctx.Done():
+	}
+}
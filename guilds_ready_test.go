@@ -0,0 +1,147 @@
+package harmony
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+func TestGuildsReady(t *testing.T) {
+	// Gateway events are written as "TYPE data", "wait" waits for GuildsReady.
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		events   []string
+		expected []string
+	}{
+		{
+			name: "no guilds",
+			events: []string{
+				`READY {"guilds":[]}`,
+			},
+			expected: []string{"ready [] unavailable []"},
+		},
+		{
+			name: "all guilds received",
+			events: []string{
+				`READY {"guilds":[{"id":"g1","unavailable":true},{"id":"g2","unavailable":true},{"id":"g3"}]}`,
+				`GUILD_CREATE {"id":"g2"}`,
+				`GUILD_CREATE {"id":"g1"}`,
+				`GUILD_CREATE {"id":"g3"}`,
+			},
+			expected: []string{"ready [g2 g1] unavailable []", "join g3"},
+		},
+		{
+			name:    "timeout",
+			timeout: 10 * time.Millisecond,
+			events: []string{
+				`READY {"guilds":[{"id":"g1","unavailable":true},{"id":"g2","unavailable":true}]}`,
+				`GUILD_CREATE {"id":"g1"}`,
+				"wait",
+				`GUILD_CREATE {"id":"g2"}`,
+			},
+			expected: []string{"ready [g1] unavailable [g2]", "available g2"},
+		},
+		{
+			name: "removed while pending",
+			events: []string{
+				`READY {"guilds":[{"id":"g1","unavailable":true},{"id":"g2","unavailable":true}]}`,
+				`GUILD_DELETE {"id":"g2"}`,
+				`GUILD_CREATE {"id":"g1"}`,
+			},
+			expected: []string{"ready [g1] unavailable []"},
+		},
+		{
+			name: "join and available after ready",
+			events: []string{
+				`READY {"guilds":[{"id":"g1","unavailable":true}]}`,
+				`GUILD_CREATE {"id":"g1"}`,
+				`GUILD_DELETE {"id":"g1","unavailable":true}`,
+				`GUILD_CREATE {"id":"g1"}`,
+				`GUILD_CREATE {"id":"g2"}`,
+				`GUILD_DELETE {"id":"g2"}`,
+				`GUILD_CREATE {"id":"g2"}`,
+			},
+			expected: []string{"ready [g1] unavailable []", "available g1", "join g2", "join g2"},
+		},
+		{
+			name: "unavailable while pending",
+			events: []string{
+				`READY {"guilds":[{"id":"g1","unavailable":true},{"id":"g2","unavailable":true}]}`,
+				`GUILD_DELETE {"id":"g2","unavailable":true}`,
+				`GUILD_CREATE {"id":"g2"}`,
+				`GUILD_CREATE {"id":"g1"}`,
+			},
+			expected: []string{"ready [g2 g1] unavailable []"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Hour
+			}
+			c, err := NewClient("token",
+				WithLogger(testLogger),
+				WithDispatchMode(DispatchModeSync, 0),
+				WithGuildsReadyTimeout(timeout),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				mu  sync.Mutex
+				got []string
+			)
+			record := func(event string) {
+				mu.Lock()
+				got = append(got, event)
+				mu.Unlock()
+			}
+			c.OnGuildsReady(func(r *GuildsReady) {
+				sort.Strings(r.Unavailable)
+				record(fmt.Sprintf("ready %v unavailable %v", r.Guilds, r.Unavailable))
+			})
+			c.OnGuildJoin(func(g *discord.Guild) { record("join " + g.ID) })
+			c.OnGuildAvailable(func(g *discord.Guild) { record("available " + g.ID) })
+
+			for _, e := range tt.events {
+				if e == "wait" {
+					c.guilds.mu.Lock()
+					ready := c.guilds.ready
+					c.guilds.mu.Unlock()
+					select {
+					case <-ready:
+					case <-time.After(5 * time.Second):
+						t.Fatal("timed out waiting for guilds to be ready")
+					}
+					continue
+				}
+
+				typ, data, _ := strings.Cut(e, " ")
+				if typ == eventReady {
+					data = strings.Replace(data, "{", `{"session_id":"session","user":{"id":"me"},`, 1)
+				}
+				if err = c.dispatch(typ, json.RawMessage(data)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected events %q; got %q", tt.expected, got)
+			}
+		})
+	}
+}
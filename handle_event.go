@@ -43,6 +43,7 @@ func (c *Client) handleEvent(p *payload.Payload) error {
 		if err := json.Unmarshal(p.D, &resumable); err != nil {
 			return fmt.Errorf("unmarshal resume: %w", err)
 		}
+		c.handle(eventInvalidSession, &InvalidSession{Resumable: resumable})
 
 		if resumable {
 			if err := c.resume(c.ctx); err != nil {
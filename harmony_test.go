@@ -9,6 +9,7 @@ import (
 
 	"github.com/skwair/harmony"
 	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/resource/channel"
 )
 
 func TestHarmony(t *testing.T) {
@@ -124,7 +125,7 @@ func TestHarmony(t *testing.T) {
 	})
 
 	t.Run("edit message", func(t *testing.T) {
-		if _, err = client.Channel(txtCh.ID).EditMessage(context.TODO(), lastMsgID, "foobar edited"); err != nil {
+		if _, err = client.Channel(txtCh.ID).EditMessage(context.TODO(), lastMsgID, channel.WithMessageContent("foobar edited")); err != nil {
 			t.Fatalf("could not edit message: %v", err)
 		}
 	})
@@ -240,7 +241,7 @@ func TestHarmony(t *testing.T) {
 	var testRole *discord.Role
 
 	t.Run("new role", func(t *testing.T) {
-		perms := discord.PermissionReadMessageHistory | discord.PermissionSendMessages
+		var perms int64 = discord.PermissionReadMessageHistory | discord.PermissionSendMessages
 
 		settings := discord.NewRoleSettings(
 			discord.WithRoleName("test-role"),
@@ -53,7 +53,7 @@ type resume struct {
 func (c *Client) resume(ctx context.Context) error {
 	r := &resume{
 		Token:     c.token,
-		SessionID: c.sessionID,
+		SessionID: c.sessionID.Load(),
 		Seq:       c.sequence.Load(),
 	}
 	return c.sendPayload(ctx, gatewayOpcodeResume, r)
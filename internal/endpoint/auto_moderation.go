@@ -0,0 +1,43 @@
+package endpoint
+
+import "net/http"
+
+func ListAutoModerationRules(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/auto-moderation/rules",
+		Key:    "/guilds/" + guildID + "/auto-moderation/rules",
+	}
+}
+
+func GetAutoModerationRule(guildID, ruleID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/auto-moderation/rules/" + ruleID,
+		Key:    "/guilds/" + guildID + "/auto-moderation/rules",
+	}
+}
+
+func CreateAutoModerationRule(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPost,
+		Path:   "/guilds/" + guildID + "/auto-moderation/rules",
+		Key:    "/guilds/" + guildID + "/auto-moderation/rules",
+	}
+}
+
+func ModifyAutoModerationRule(guildID, ruleID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/guilds/" + guildID + "/auto-moderation/rules/" + ruleID,
+		Key:    "/guilds/" + guildID + "/auto-moderation/rules",
+	}
+}
+
+func DeleteAutoModerationRule(guildID, ruleID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodDelete,
+		Path:   "/guilds/" + guildID + "/auto-moderation/rules/" + ruleID,
+		Key:    "/guilds/" + guildID + "/auto-moderation/rules",
+	}
+}
@@ -74,6 +74,22 @@ func ListGuildMembers(guildID, query string) *Endpoint {
 	}
 }
 
+func SearchGuildMembers(guildID, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/members/search?" + query,
+		Key:    "/guilds/" + guildID + "/members/search",
+	}
+}
+
+func GetCurrentUserGuildMember(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/users/@me/guilds/" + guildID + "/member",
+		Key:    "/users/@me/guilds/" + guildID + "/member",
+	}
+}
+
 func AddGuildMember(guildID, userID string) *Endpoint {
 	return &Endpoint{
 		Method: http.MethodPut,
@@ -98,6 +114,14 @@ func ModifyGuildMember(guildID, userID string) *Endpoint {
 	}
 }
 
+func ModifyCurrentMember(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/guilds/" + guildID + "/members/@me",
+		Key:    "/guilds/" + guildID + "/members/@me",
+	}
+}
+
 func ModifyCurrentUserNick(guildID string) *Endpoint {
 	return &Endpoint{
 		Method: http.MethodPatch,
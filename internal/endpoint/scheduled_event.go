@@ -0,0 +1,51 @@
+package endpoint
+
+import "net/http"
+
+func ListGuildScheduledEvents(guildID, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/scheduled-events?" + query,
+		Key:    "/guilds/" + guildID + "/scheduled-events",
+	}
+}
+
+func CreateGuildScheduledEvent(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPost,
+		Path:   "/guilds/" + guildID + "/scheduled-events",
+		Key:    "/guilds/" + guildID + "/scheduled-events",
+	}
+}
+
+func GetGuildScheduledEvent(guildID, eventID, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/scheduled-events/" + eventID + "?" + query,
+		Key:    "/guilds/" + guildID + "/scheduled-events",
+	}
+}
+
+func ModifyGuildScheduledEvent(guildID, eventID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/guilds/" + guildID + "/scheduled-events/" + eventID,
+		Key:    "/guilds/" + guildID + "/scheduled-events",
+	}
+}
+
+func DeleteGuildScheduledEvent(guildID, eventID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodDelete,
+		Path:   "/guilds/" + guildID + "/scheduled-events/" + eventID,
+		Key:    "/guilds/" + guildID + "/scheduled-events",
+	}
+}
+
+func GetGuildScheduledEventUsers(guildID, eventID, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/guilds/" + guildID + "/scheduled-events/" + eventID + "/users?" + query,
+		Key:    "/guilds/" + guildID + "/scheduled-events/users",
+	}
+}
@@ -0,0 +1,35 @@
+package endpoint
+
+import "net/http"
+
+func CreateStageInstance() *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPost,
+		Path:   "/stage-instances",
+		Key:    "/stage-instances",
+	}
+}
+
+func GetStageInstance(channelID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/stage-instances/" + channelID,
+		Key:    "/stage-instances/" + channelID,
+	}
+}
+
+func ModifyStageInstance(channelID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/stage-instances/" + channelID,
+		Key:    "/stage-instances/" + channelID,
+	}
+}
+
+func DeleteStageInstance(channelID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodDelete,
+		Path:   "/stage-instances/" + channelID,
+		Key:    "/stage-instances/" + channelID,
+	}
+}
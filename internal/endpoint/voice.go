@@ -9,3 +9,19 @@ func GetVoiceRegions() *Endpoint {
 		Key:    "/voice/regions",
 	}
 }
+
+func ModifyCurrentUserVoiceState(guildID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/guilds/" + guildID + "/voice-states/@me",
+		Key:    "/guilds/" + guildID + "/voice-states",
+	}
+}
+
+func ModifyUserVoiceState(guildID, userID string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/guilds/" + guildID + "/voice-states/" + userID,
+		Key:    "/guilds/" + guildID + "/voice-states",
+	}
+}
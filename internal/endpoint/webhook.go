@@ -81,3 +81,55 @@ func ExecuteWebhook(whID, token, query string) *Endpoint {
 		Key:    "/webhooks/" + whID,
 	}
 }
+
+func ExecuteSlackCompatibleWebhook(whID, token, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPost,
+		Path:   "/webhooks/" + whID + "/" + token + "/slack?" + query,
+		Key:    "/webhooks/" + whID,
+	}
+}
+
+func ExecuteGitHubCompatibleWebhook(whID, token, query string) *Endpoint {
+	return &Endpoint{
+		Method: http.MethodPost,
+		Path:   "/webhooks/" + whID + "/" + token + "/github?" + query,
+		Key:    "/webhooks/" + whID,
+	}
+}
+
+func GetWebhookMessage(whID, token, msgID, query string) *Endpoint {
+	if query != "" {
+		query = "?" + query
+	}
+
+	return &Endpoint{
+		Method: http.MethodGet,
+		Path:   "/webhooks/" + whID + "/" + token + "/messages/" + msgID + query,
+		Key:    "/webhooks/" + whID + "/messages",
+	}
+}
+
+func EditWebhookMessage(whID, token, msgID, query string) *Endpoint {
+	if query != "" {
+		query = "?" + query
+	}
+
+	return &Endpoint{
+		Method: http.MethodPatch,
+		Path:   "/webhooks/" + whID + "/" + token + "/messages/" + msgID + query,
+		Key:    "/webhooks/" + whID + "/messages",
+	}
+}
+
+func DeleteWebhookMessage(whID, token, msgID, query string) *Endpoint {
+	if query != "" {
+		query = "?" + query
+	}
+
+	return &Endpoint{
+		Method: http.MethodDelete,
+		Path:   "/webhooks/" + whID + "/" + token + "/messages/" + msgID + query,
+		Key:    "/webhooks/" + whID + "/messages",
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httputil"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/skwair/harmony/internal/endpoint"
@@ -80,8 +82,11 @@ func (c *Client) DoWithHeader(ctx context.Context, e *endpoint.Endpoint, p *Payl
 	if p.hasBody() {
 		req.Header.Set("Content-Type", p.contentType)
 	}
-	// Add the Authorization header.
-	req.Header.Set("Authorization", c.token)
+	// Add the Authorization header, unless this client
+	// is used to request endpoints that do not need it.
+	if c.token != "" {
+		req.Header.Set("Authorization", c.token)
+	}
 	// Finally, set the User-Agent header.
 	ua := fmt.Sprintf("%s (github.com/skwair/harmony, %s)", c.name, version.Module())
 	req.Header.Set("User-Agent", ua)
@@ -145,63 +150,36 @@ func (c *Client) DoWithHeader(ctx context.Context, e *endpoint.Endpoint, p *Payl
 	return resp, nil
 }
 
+var (
+	noAuthOnce   sync.Once
+	noAuthClient *Client
+)
+
+// noAuth returns the client used to request endpoints that do not need authentication,
+// creating it the first time it is needed. It has its own rate limiter, shared by all
+// callers of Do and DoWithHeader.
+func noAuth() *Client {
+	noAuthOnce.Do(func() {
+		noAuthClient = NewClient(
+			&http.Client{Timeout: 30 * time.Second},
+			"",
+			"Harmony",
+			log.NewStd(os.Stderr, log.LevelInfo),
+		)
+	})
+	return noAuthClient
+}
+
 // Do is used to request endpoints that do not need authentication.
 // If you need more control over headers you send, use DoWithHeader directly.
 func Do(ctx context.Context, e *endpoint.Endpoint, p *Payload) (*http.Response, error) {
 	return DoWithHeader(ctx, e, p, nil)
 }
 
-// Do is used to request endpoints that do not need authentication. It is
-// like DoWithHeader otherwise, except for rate limiting where it is more likely
-// to result in 429's if abused.
+// DoWithHeader is used to request endpoints that do not need authentication. It is
+// like Client.DoWithHeader otherwise, except no Authorization header is sent.
 func DoWithHeader(ctx context.Context, e *endpoint.Endpoint, p *Payload, h http.Header) (*http.Response, error) {
-	var (
-		err error
-		req *http.Request
-	)
-	if p.hasBody() {
-		req, err = http.NewRequest(e.Method, baseURL+e.Path, bytes.NewReader(p.body))
-	} else {
-		req, err = http.NewRequest(e.Method, baseURL+e.Path, nil)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	req = req.WithContext(ctx)
-
-	for k, vs := range h {
-		for _, v := range vs {
-			req.Header.Add(k, v)
-		}
-	}
-	// Add the Content-Type header accordingly to the payload's body, if any.
-	if p.hasBody() {
-		req.Header.Set("Content-Type", p.contentType)
-	}
-	ua := fmt.Sprintf("%s (github.com/skwair/harmony, %s)", "Harmony", version.Module())
-	req.Header.Set("User-Agent", ua)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	// We are being rate limited, wait a bit and resend the request.
-	// NOTE: maybe use HTTP headers (if set) instead of having to
-	// parse some JSON.
-	if resp.StatusCode == http.StatusTooManyRequests {
-		var r rateLimitResp
-		if err = json.NewDecoder(resp.Body).Decode(&r); err != nil {
-			return nil, err
-		}
-
-		time.Sleep(time.Millisecond * time.Duration(r.RetryAfter*100))
-
-		return DoWithHeader(ctx, e, p, h)
-	}
-
-	return resp, nil
+	return noAuth().DoWithHeader(ctx, e, p, h)
 }
 
 // ReasonHeader returns an HTTP header with the Audit Log reason set to r.
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/textproto"
+	"path"
+	"sort"
 
 	"github.com/skwair/harmony/discord"
 )
@@ -67,3 +70,58 @@ func MultipartFromFiles(payload MultipartPayload, files ...discord.File) ([]byte
 
 	return buf.Bytes(), w.FormDataContentType(), nil
 }
+
+// MultipartForm holds the fields of a multipart form, for endpoints
+// that do not accept a "payload_json" part.
+type MultipartForm map[string]string
+
+// MultipartFromForm generates a multipart body given form fields and a file. Fields
+// are sent as regular form fields and the file is sent in a part named "file", with
+// a content type guessed from its name.
+// It returns the raw generated body along the content type of this body.
+func MultipartFromForm(form MultipartForm, file discord.File) ([]byte, string, error) {
+	// Underlying buffer the multipart body will be written to.
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	// Send fields in a stable order.
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := w.WriteField(k, form[k]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	contentType := "application/octet-stream"
+	if t := mime.TypeByExtension(path.Ext(file.Name)); t != "" {
+		contentType = t
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, file.Name))
+	h.Set("Content-Type", contentType)
+
+	pw, err := w.CreatePart(h)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err = io.Copy(pw, file.Reader); err != nil {
+		return nil, "", err
+	}
+
+	if err = file.Reader.Close(); err != nil {
+		return nil, "", err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
@@ -0,0 +1,147 @@
+package harmony
+
+import (
+	"time"
+)
+
+// Those events are not sent by the Gateway but by the Client itself,
+// to report changes in the state of its connection to the Gateway.
+const (
+	eventConnected        = "CLIENT_CONNECTED"
+	eventDisconnected     = "CLIENT_DISCONNECTED"
+	eventReconnectAttempt = "CLIENT_RECONNECT_ATTEMPT"
+	eventPermanentFailure = "CLIENT_PERMANENT_FAILURE"
+)
+
+// Connected is sent when the client successfully opened a connection to the Gateway.
+type Connected struct {
+	// Resuming is set to true if the client is resuming a previous
+	// session instead of starting a new one. In this case, a Resumed
+	// event will follow once missed events have been replayed.
+	Resuming bool
+}
+
+type connectedHandler func(*Connected)
+
+// handle implements the handler interface.
+func (h connectedHandler) handle(v interface{}) {
+	h(v.(*Connected))
+}
+
+// OnConnected registers the handler function for the Connected event.
+// Fired each time the client connects to the Gateway, including when it
+// automatically reconnects after an error.
+func (c *Client) OnConnected(f func(c *Connected)) {
+	c.registerHandler(eventConnected, connectedHandler(f))
+}
+
+// Disconnected is sent when the connection to the Gateway is closed.
+type Disconnected struct {
+	// Code is the websocket close code of the connection, or -1 if the
+	// connection was not closed with a close frame (network error, etc.).
+	Code int
+	// Err is the error that caused the disconnection. It is nil if the
+	// client called Disconnect.
+	Err error
+	// WillReconnect reports whether the client is going to automatically
+	// try to reconnect to the Gateway.
+	WillReconnect bool
+}
+
+type disconnectedHandler func(*Disconnected)
+
+// handle implements the handler interface.
+func (h disconnectedHandler) handle(v interface{}) {
+	h(v.(*Disconnected))
+}
+
+// OnDisconnected registers the handler function for the Disconnected event.
+// Fired when the connection to the Gateway is closed, either because an error
+// occurred or because Disconnect was called.
+func (c *Client) OnDisconnected(f func(d *Disconnected)) {
+	c.registerHandler(eventDisconnected, disconnectedHandler(f))
+}
+
+// ReconnectAttempt is sent when the client is about to try to reconnect to the Gateway.
+type ReconnectAttempt struct {
+	// Attempt is the number of the upcoming attempt, starting at 1.
+	Attempt int
+	// Delay is the time the client will wait before this attempt.
+	// It is always 0 for the first attempt.
+	Delay time.Duration
+	// Err is the error that made the previous attempt fail or, for the first
+	// attempt, the error that caused the connection to be lost.
+	Err error
+}
+
+type reconnectAttemptHandler func(*ReconnectAttempt)
+
+// handle implements the handler interface.
+func (h reconnectAttemptHandler) handle(v interface{}) {
+	h(v.(*ReconnectAttempt))
+}
+
+// OnReconnectAttempt registers the handler function for the ReconnectAttempt event.
+// Fired before each attempt to reconnect to the Gateway, including the first one. Attempts
+// after the first one are delayed according to the backoff strategy of the client
+// (see WithBackoffStrategy).
+func (c *Client) OnReconnectAttempt(f func(a *ReconnectAttempt)) {
+	c.registerHandler(eventReconnectAttempt, reconnectAttemptHandler(f))
+}
+
+// Resumed is sent when the client successfully resumed a previous session
+// and all missed events have been replayed.
+type Resumed struct{}
+
+type resumedHandler func(*Resumed)
+
+// handle implements the handler interface.
+func (h resumedHandler) handle(v interface{}) {
+	h(v.(*Resumed))
+}
+
+// OnResumed registers the handler function for the "RESUMED" event.
+func (c *Client) OnResumed(f func(r *Resumed)) {
+	c.registerHandler(eventResumed, resumedHandler(f))
+}
+
+// InvalidSession is sent when the Gateway reports the current session is invalid.
+type InvalidSession struct {
+	// Resumable reports whether the session can be resumed. If it can not,
+	// the client waits a bit and starts a new session, meaning events sent
+	// in between are lost.
+	Resumable bool
+}
+
+type invalidSessionHandler func(*InvalidSession)
+
+// handle implements the handler interface.
+func (h invalidSessionHandler) handle(v interface{}) {
+	h(v.(*InvalidSession))
+}
+
+// OnInvalidSession registers the handler function for the "INVALID_SESSION" event.
+func (c *Client) OnInvalidSession(f func(s *InvalidSession)) {
+	c.registerHandler(eventInvalidSession, invalidSessionHandler(f))
+}
+
+// PermanentFailure is sent when the client lost its connection to the Gateway
+// and will not try to reconnect, because the error it got can not be recovered
+// from (invalid token, invalid intents, etc.).
+type PermanentFailure struct {
+	Err error
+}
+
+type permanentFailureHandler func(*PermanentFailure)
+
+// handle implements the handler interface.
+func (h permanentFailureHandler) handle(v interface{}) {
+	h(v.(*PermanentFailure))
+}
+
+// OnPermanentFailure registers the handler function for the PermanentFailure event.
+// Once this event is fired, the client is disconnected and Connect must be called
+// again to establish a new connection.
+func (c *Client) OnPermanentFailure(f func(pf *PermanentFailure)) {
+	c.registerHandler(eventPermanentFailure, permanentFailureHandler(f))
+}
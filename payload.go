@@ -23,7 +23,7 @@ func (c *Client) sendPayload(ctx context.Context, op int, d interface{}) error {
 func (c *Client) recvPayload() (*payload.Payload, error) {
 	p, err := payload.Recv(c.ctx, &c.connRMu, c.conn)
 	if err != nil {
-		return nil, err
+		return nil, c.closeError(err)
 	}
 
 	c.logger.Debugf("received payload: %s", p)
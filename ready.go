@@ -1,7 +1,6 @@
 package harmony
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/skwair/harmony/discord"
@@ -30,19 +29,7 @@ func (c *Client) recvReady() error {
 		return fmt.Errorf("expected Opcode 0 Ready; got Opcode %d %s", p.Op, p.T)
 	}
 
-	var rdy Ready
-	if err = json.Unmarshal(p.D, &rdy); err != nil {
-		return err
-	}
-	c.sessionID = rdy.SessionID
-	c.userID = rdy.User.ID
-
-	if c.withStateTracking {
-		c.logger.Debug("initializing state tracker")
-		c.State.setInitialState(&rdy)
-	}
-
-	// Let this event be dispatched so the user
-	// can get the initial state of the connection.
+	// Let this event be dispatched so the session is set, the State
+	// is initialized and the user can get the initial state of the connection.
 	return c.handleEvent(p)
 }
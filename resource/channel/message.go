@@ -11,6 +11,7 @@ import (
 	"github.com/skwair/harmony/discord"
 	"github.com/skwair/harmony/internal/endpoint"
 	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/optional"
 )
 
 // Messages returns messages in the channel. If operating on a guild channel, this
@@ -145,18 +146,33 @@ func (r *Resource) DeleteMessageBulk(ctx context.Context, messageIDs []string) e
 // MessageOption allows to customize the content of a message.
 type MessageOption func(*createMessage)
 
-// WithMessageContent sets the content of a message, up to 2000 characters
+// WithMessageContent sets the content of a message, up to 2000 characters.
 func WithMessageContent(text string) MessageOption {
 	return func(m *createMessage) {
 		m.Content = text
+		m.noContent = false
 	}
 }
 
-// WithMessageEmbed sets the embed of a message.
+// WithMessageEmbed adds an embed to a message. A nil embed is ignored.
 // See embed sub package for more information about embeds.
 func WithMessageEmbed(e *discord.MessageEmbed) MessageOption {
 	return func(m *createMessage) {
-		m.Embed = e
+		if e == nil {
+			return
+		}
+		m.Embeds = append(m.Embeds, *e)
+		m.noEmbeds = false
+	}
+}
+
+// WithMessageEmbeds adds embeds to a message. A message can have up to 10 embeds.
+// Nil embeds are ignored. See embed sub package for more information about embeds.
+func WithMessageEmbeds(embeds ...*discord.MessageEmbed) MessageOption {
+	return func(m *createMessage) {
+		for _, e := range embeds {
+			WithMessageEmbed(e)(m)
+		}
 	}
 }
 
@@ -169,6 +185,15 @@ func WithMessageFiles(files ...*discord.File) MessageOption {
 	}
 }
 
+// WithMessageStickers sets the stickers of a message, up to 3 sticker IDs.
+// Stickers can be standard stickers or stickers of the guild the message
+// is sent in.
+func WithMessageStickers(ids ...string) MessageOption {
+	return func(m *createMessage) {
+		m.StickerIDs = append(m.StickerIDs, ids...)
+	}
+}
+
 // WithMessageTTS enables text to speech for a message.
 func WithMessageTTS() MessageOption {
 	return func(m *createMessage) {
@@ -176,6 +201,65 @@ func WithMessageTTS() MessageOption {
 	}
 }
 
+// WithMessageReply makes a message a reply to the message with the given ID, which must
+// be in the same channel. If failIfNotExists is false, the message is sent as a regular
+// message when the message it replies to does not exist instead of returning an error.
+// Whether the author of the original message is mentioned is controlled by the
+// RepliedUser field of allowed mentions, see WithMessageAllowedMentions.
+func WithMessageReply(messageID string, failIfNotExists bool) MessageOption {
+	return func(m *createMessage) {
+		m.MessageReference = &messageReference{
+			MessageID:       messageID,
+			FailIfNotExists: failIfNotExists,
+		}
+	}
+}
+
+// WithMessageAllowedMentions sets which mentions in the content of a message actually
+// notify their targets. If not set, all mentions are parsed from the content.
+func WithMessageAllowedMentions(mentions *discord.AllowedMentions) MessageOption {
+	return func(m *createMessage) {
+		m.AllowedMentions = mentions
+	}
+}
+
+// WithMessageFlags sets the flags of a message. Only MessageFlagSuppressEmbeds
+// and MessageFlagSuppressNotifications can be set when sending a message and
+// only MessageFlagSuppressEmbeds can be changed when editing it.
+func WithMessageFlags(flags discord.MessageFlag) MessageOption {
+	return func(m *createMessage) {
+		m.Flags = optional.NewInt(int(flags))
+	}
+}
+
+// WithMessageNoContent removes the content of a message when editing it.
+// It has no effect when sending a message.
+func WithMessageNoContent() MessageOption {
+	return func(m *createMessage) {
+		m.Content = ""
+		m.noContent = true
+	}
+}
+
+// WithMessageNoEmbeds removes all embeds of a message when editing it.
+// It has no effect when sending a message.
+func WithMessageNoEmbeds() MessageOption {
+	return func(m *createMessage) {
+		m.Embeds = nil
+		m.noEmbeds = true
+	}
+}
+
+// WithMessageAttachments sets the IDs of the existing attachments of a message to keep
+// when editing it. Attachments that are not listed are removed, meaning that calling it
+// without any ID removes all attachments of the message. If not set, all attachments are
+// kept. It has no effect when sending a message.
+func WithMessageAttachments(ids ...string) MessageOption {
+	return func(m *createMessage) {
+		m.attachments = append(make([]string, 0, len(ids)), ids...)
+	}
+}
+
 // WithMessageNonce sets the nonce of a message.
 // The nonce will be returned in the result and also transmitted to other clients.
 func WithMessageNonce(n string) MessageOption {
@@ -197,7 +281,7 @@ func (r *Resource) Send(ctx context.Context, opts ...MessageOption) (*discord.Me
 		opt(&msg)
 	}
 
-	if msg.Content == "" && msg.Embed == nil && len(msg.files) == 0 {
+	if msg.Content == "" && len(msg.Embeds) == 0 && len(msg.files) == 0 && len(msg.StickerIDs) == 0 {
 		return nil, discord.ErrInvalidMessageSend
 	}
 
@@ -211,12 +295,29 @@ func (r *Resource) SendMessage(ctx context.Context, text string) (*discord.Messa
 
 // createMessage describes a message creation.
 type createMessage struct {
-	Content string                `json:"content,omitempty"` // Up to 2000 characters.
-	Nonce   string                `json:"nonce,omitempty"`
-	TTS     bool                  `json:"tts,omitempty"`
-	Embed   *discord.MessageEmbed `json:"embed,omitempty"`
+	Content string                 `json:"content,omitempty"` // Up to 2000 characters.
+	Nonce   string                 `json:"nonce,omitempty"`
+	TTS     bool                   `json:"tts,omitempty"`
+	Embeds  []discord.MessageEmbed `json:"embeds,omitempty"` // Up to 10 embeds.
+
+	AllowedMentions  *discord.AllowedMentions `json:"allowed_mentions,omitempty"`
+	MessageReference *messageReference        `json:"message_reference,omitempty"`
+	Flags            *optional.Int            `json:"flags,omitempty"`
+
+	StickerIDs []string `json:"sticker_ids,omitempty"` // Up to 3 stickers.
 
 	files []discord.File
+
+	// Only used when editing a message.
+	noContent   bool
+	noEmbeds    bool
+	attachments []string
+}
+
+// messageReference is the reference to the message a message replies to.
+type messageReference struct {
+	MessageID       string `json:"message_id"`
+	FailIfNotExists bool   `json:"fail_if_not_exists"`
 }
 
 // Bytes implements the rest.MultipartPayload interface so createMessage can be used as
@@ -226,9 +327,7 @@ func (cm *createMessage) Bytes() ([]byte, error) {
 }
 
 func (r *Resource) sendMessage(ctx context.Context, channelID string, msg *createMessage) (*discord.Message, error) {
-	if msg.Embed != nil && msg.Embed.Type == "" {
-		msg.Embed.Type = "rich"
-	}
+	setRichEmbeds(msg.Embeds)
 
 	var payload *rest.Payload
 	if len(msg.files) > 0 {
@@ -263,31 +362,104 @@ func (r *Resource) sendMessage(ctx context.Context, channelID string, msg *creat
 	return &m, nil
 }
 
+// setRichEmbeds sets the type of embeds that have none to "rich".
+func setRichEmbeds(embeds []discord.MessageEmbed) {
+	for i := range embeds {
+		if embeds[i].Type == "" {
+			embeds[i].Type = "rich"
+		}
+	}
+}
+
+// editMessage describes a message edition. Fields that are nil are left unchanged.
 type editMessage struct {
-	Content string                `json:"content,omitempty"`
-	Embed   *discord.MessageEmbed `json:"embed,omitempty"`
+	Content         *optional.String         `json:"content,omitempty"`
+	Embeds          *[]discord.MessageEmbed  `json:"embeds,omitempty"`
+	AllowedMentions *discord.AllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           *optional.Int            `json:"flags,omitempty"`
+	Attachments     *[]attachmentRef         `json:"attachments,omitempty"`
+
+	files []discord.File
 }
 
-// EditMessage edits a previously sent message. You can only edit messages that have
-// been sent by the current user. Fires a Message Update Gateway event. See EditEmbed
-// if you need to edit some emended content.
-func (r *Resource) EditMessage(ctx context.Context, messageID, content string) (*discord.Message, error) {
-	return r.editMessage(ctx, r.channelID, messageID, &editMessage{content, nil})
+// attachmentRef references an existing attachment of a message.
+type attachmentRef struct {
+	ID string `json:"id"`
 }
 
-// EditEmbed is like EditMessage but with embedded content support.
-func (r *Resource) EditEmbed(ctx context.Context, messageID, content string, embed *discord.MessageEmbed) (*discord.Message, error) {
-	return r.editMessage(ctx, r.channelID, messageID, &editMessage{content, embed})
+// Bytes implements the rest.MultipartPayload interface so editMessage can be used as
+// a payload with the rest.MultipartFromFiles function.
+func (em *editMessage) Bytes() ([]byte, error) {
+	return json.Marshal(em)
+}
+
+// EditMessage edits a previously sent message. It accepts the same options as Send, with
+// a few differences: only options that are explicitly set are changed, WithMessageNoContent
+// and WithMessageNoEmbeds can be used to remove the content and embeds of the message and
+// WithMessageAttachments sets which of its existing attachments are kept. Files set with
+// WithMessageFiles are added to the message. WithMessageTTS, WithMessageNonce,
+// WithMessageReply and WithMessageStickers have no effect.
+// You can only edit messages that have been sent by the current user, except for flags
+// which can be edited on other messages with the 'MANAGE_MESSAGES' permission.
+// Fires a Message Update Gateway event.
+func (r *Resource) EditMessage(ctx context.Context, messageID string, opts ...MessageOption) (*discord.Message, error) {
+	var msg createMessage
+
+	for _, opt := range opts {
+		opt(&msg)
+	}
+
+	edit := &editMessage{
+		AllowedMentions: msg.AllowedMentions,
+		Flags:           msg.Flags,
+		files:           msg.files,
+	}
+
+	if msg.noContent {
+		edit.Content = optional.NewNilString()
+	} else if msg.Content != "" {
+		edit.Content = optional.NewString(msg.Content)
+	}
+
+	if msg.noEmbeds {
+		edit.Embeds = &[]discord.MessageEmbed{}
+	} else if len(msg.Embeds) > 0 {
+		edit.Embeds = &msg.Embeds
+	}
+
+	if msg.attachments != nil {
+		attachments := make([]attachmentRef, 0, len(msg.attachments))
+		for _, id := range msg.attachments {
+			attachments = append(attachments, attachmentRef{ID: id})
+		}
+		edit.Attachments = &attachments
+	}
+
+	return r.editMessage(ctx, r.channelID, messageID, edit)
 }
 
 func (r *Resource) editMessage(ctx context.Context, channelID, messageID string, edit *editMessage) (*discord.Message, error) {
-	b, err := json.Marshal(edit)
-	if err != nil {
-		return nil, err
+	if edit.Embeds != nil {
+		setRichEmbeds(*edit.Embeds)
+	}
+
+	var payload *rest.Payload
+	if len(edit.files) > 0 {
+		b, contentType, err := rest.MultipartFromFiles(edit, edit.files...)
+		if err != nil {
+			return nil, err
+		}
+		payload = rest.CustomPayload(b, contentType)
+	} else {
+		b, err := json.Marshal(edit)
+		if err != nil {
+			return nil, err
+		}
+		payload = rest.JSONPayload(b)
 	}
 
 	e := endpoint.EditMessage(channelID, messageID)
-	resp, err := r.client.Do(ctx, e, rest.JSONPayload(b))
+	resp, err := r.client.Do(ctx, e, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,128 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// StageInstance returns the stage instance of the channel, if it is a live stage channel.
+func (r *Resource) StageInstance(ctx context.Context) (*discord.StageInstance, error) {
+	e := endpoint.GetStageInstance(r.channelID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var stage discord.StageInstance
+	if err = json.NewDecoder(resp.Body).Decode(&stage); err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// NewStageInstance is like NewStageInstanceWithReason but with no particular reason.
+func (r *Resource) NewStageInstance(ctx context.Context, settings *discord.StageInstanceSettings) (*discord.StageInstance, error) {
+	return r.NewStageInstanceWithReason(ctx, settings, "")
+}
+
+// NewStageInstanceWithReason creates a new stage instance in the channel, which must be a
+// stage channel, making it live. Requires the current user to be a moderator of the stage
+// channel, i.e. to have the 'MANAGE_CHANNELS', 'MUTE_MEMBERS' and 'MOVE_MEMBERS' permissions.
+// Fires a Stage Instance Create Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) NewStageInstanceWithReason(ctx context.Context, settings *discord.StageInstanceSettings, reason string) (*discord.StageInstance, error) {
+	st := struct {
+		ChannelID string `json:"channel_id"`
+		*discord.StageInstanceSettings
+	}{
+		ChannelID:             r.channelID,
+		StageInstanceSettings: settings,
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateStageInstance()
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var stage discord.StageInstance
+	if err = json.NewDecoder(resp.Body).Decode(&stage); err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// ModifyStageInstance is like ModifyStageInstanceWithReason but with no particular reason.
+func (r *Resource) ModifyStageInstance(ctx context.Context, settings *discord.StageInstanceSettings) (*discord.StageInstance, error) {
+	return r.ModifyStageInstanceWithReason(ctx, settings, "")
+}
+
+// ModifyStageInstanceWithReason modifies the topic or privacy level of the stage instance of
+// the channel. Requires the current user to be a moderator of the stage channel.
+// Fires a Stage Instance Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) ModifyStageInstanceWithReason(ctx context.Context, settings *discord.StageInstanceSettings, reason string) (*discord.StageInstance, error) {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyStageInstance(r.channelID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var stage discord.StageInstance
+	if err = json.NewDecoder(resp.Body).Decode(&stage); err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// DeleteStageInstance is like DeleteStageInstanceWithReason but with no particular reason.
+func (r *Resource) DeleteStageInstance(ctx context.Context) error {
+	return r.DeleteStageInstanceWithReason(ctx, "")
+}
+
+// DeleteStageInstanceWithReason deletes the stage instance of the channel, ending the stage.
+// Requires the current user to be a moderator of the stage channel.
+// Fires a Stage Instance Delete Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) DeleteStageInstanceWithReason(ctx context.Context, reason string) error {
+	e := endpoint.DeleteStageInstance(r.channelID)
+	resp, err := r.client.DoWithHeader(ctx, e, nil, rest.ReasonHeader(reason))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package guild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// AutoModerationRules returns the list of auto moderation rules of the guild.
+// Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) AutoModerationRules(ctx context.Context) ([]discord.AutoModerationRule, error) {
+	e := endpoint.ListAutoModerationRules(r.guildID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var rules []discord.AutoModerationRule
+	if err = json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// AutoModerationRule returns an auto moderation rule of the guild.
+// Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) AutoModerationRule(ctx context.Context, ruleID string) (*discord.AutoModerationRule, error) {
+	e := endpoint.GetAutoModerationRule(r.guildID, ruleID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var rule discord.AutoModerationRule
+	if err = json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// NewAutoModerationRule is like NewAutoModerationRuleWithReason but with no particular reason.
+func (r *Resource) NewAutoModerationRule(ctx context.Context, settings *discord.AutoModerationRuleSettings) (*discord.AutoModerationRule, error) {
+	return r.NewAutoModerationRuleWithReason(ctx, settings, "")
+}
+
+// NewAutoModerationRuleWithReason creates a new auto moderation rule for the guild.
+// The settings must at least specify a name, an event type, a trigger type and an action.
+// Requires the 'MANAGE_GUILD' permission. Fires an Auto Moderation Rule Create Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) NewAutoModerationRuleWithReason(ctx context.Context, settings *discord.AutoModerationRuleSettings, reason string) (*discord.AutoModerationRule, error) {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateAutoModerationRule(r.guildID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var rule discord.AutoModerationRule
+	if err = json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ModifyAutoModerationRule is like ModifyAutoModerationRuleWithReason but with no particular reason.
+func (r *Resource) ModifyAutoModerationRule(ctx context.Context, ruleID string, settings *discord.AutoModerationRuleSettings) (*discord.AutoModerationRule, error) {
+	return r.ModifyAutoModerationRuleWithReason(ctx, ruleID, settings, "")
+}
+
+// ModifyAutoModerationRuleWithReason modifies the given auto moderation rule of the guild.
+// The trigger type of a rule can not be modified. Requires the 'MANAGE_GUILD' permission.
+// Fires an Auto Moderation Rule Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) ModifyAutoModerationRuleWithReason(ctx context.Context, ruleID string, settings *discord.AutoModerationRuleSettings, reason string) (*discord.AutoModerationRule, error) {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyAutoModerationRule(r.guildID, ruleID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var rule discord.AutoModerationRule
+	if err = json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteAutoModerationRule is like DeleteAutoModerationRuleWithReason but with no particular reason.
+func (r *Resource) DeleteAutoModerationRule(ctx context.Context, ruleID string) error {
+	return r.DeleteAutoModerationRuleWithReason(ctx, ruleID, "")
+}
+
+// DeleteAutoModerationRuleWithReason deletes the given auto moderation rule of the guild.
+// Requires the 'MANAGE_GUILD' permission. Fires an Auto Moderation Rule Delete Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) DeleteAutoModerationRuleWithReason(ctx context.Context, ruleID, reason string) error {
+	e := endpoint.DeleteAutoModerationRule(r.guildID, ruleID)
+	resp, err := r.client.DoWithHeader(ctx, e, nil, rest.ReasonHeader(reason))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
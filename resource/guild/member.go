@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/skwair/harmony/discord"
 	"github.com/skwair/harmony/internal/endpoint"
 	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/optional"
 )
 
 // Member returns a single guild member given its user ID.
@@ -68,6 +70,60 @@ func (r *Resource) Members(ctx context.Context, limit int, after string) ([]disc
 	return members, nil
 }
 
+// SearchMembers returns a list of at most limit guild members whose username
+// or nickname starts with the given query.
+// limit must be between 1 and 1000 and will be set to those values if higher/lower.
+func (r *Resource) SearchMembers(ctx context.Context, query string, limit int) ([]discord.GuildMember, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("limit", strconv.Itoa(limit))
+
+	e := endpoint.SearchGuildMembers(r.guildID, q.Encode())
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var members []discord.GuildMember
+	if err = json.NewDecoder(resp.Body).Decode(&members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// CurrentMember returns the guild member of the current user (i.e.: the bot)
+// for this guild.
+func (r *Resource) CurrentMember(ctx context.Context) (*discord.GuildMember, error) {
+	e := endpoint.GetCurrentUserGuildMember(r.guildID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var m discord.GuildMember
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 // AddMember adds a user to the guild, provided you have a valid oauth2 access
 // token for the user with the guilds.join scope. Fires a Guild Member Add Gateway event.
 // Requires the bot to have the CREATE_INSTANT_INVITE permission.
@@ -129,6 +185,66 @@ func (r *Resource) ModifyMemberWithReason(ctx context.Context, userID string, se
 	return nil
 }
 
+// ModifyCurrentMember is like ModifyCurrentMemberWithReason but with no particular reason.
+func (r *Resource) ModifyCurrentMember(ctx context.Context, nick string) (*discord.GuildMember, error) {
+	return r.ModifyCurrentMemberWithReason(ctx, nick, "")
+}
+
+// ModifyCurrentMemberWithReason modifies the nickname of the current user
+// (i.e.: the bot) for this guild and returns the updated guild member. An empty
+// nick resets it. Requires the 'CHANGE_NICKNAME' permission. Fires a Guild
+// Member Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) ModifyCurrentMemberWithReason(ctx context.Context, nick, reason string) (*discord.GuildMember, error) {
+	st := struct {
+		Nick *optional.String `json:"nick"`
+	}{
+		Nick: optional.NewNilString(),
+	}
+	if nick != "" {
+		st.Nick = optional.NewString(nick)
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyCurrentMember(r.guildID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var m discord.GuildMember
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Timeout prevents the given user from sending messages, reacting to them, joining
+// voice channels and speaking in them until the given time, which can be up to 28
+// days in the future. Requires the 'MODERATE_MEMBERS' permission. Fires a Guild
+// Member Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) Timeout(ctx context.Context, userID string, until time.Time, reason string) error {
+	settings := discord.NewGuildMemberSettings(discord.WithGuildMemberTimeout(until))
+	return r.ModifyMemberWithReason(ctx, userID, settings, reason)
+}
+
+// RemoveTimeout removes the timeout of the given user, if any. Requires the
+// 'MODERATE_MEMBERS' permission. Fires a Guild Member Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) RemoveTimeout(ctx context.Context, userID, reason string) error {
+	settings := discord.NewGuildMemberSettings(discord.WithGuildMemberTimeout(time.Time{}))
+	return r.ModifyMemberWithReason(ctx, userID, settings, reason)
+}
+
 // Kick is like KickWithReason but with no particular reason.
 func (r *Resource) Kick(ctx context.Context, userID string) error {
 	return r.KickWithReason(ctx, userID, "")
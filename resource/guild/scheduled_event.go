@@ -0,0 +1,190 @@
+package guild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/optional"
+)
+
+// ScheduledEvents returns the list of scheduled events of the guild. If withUserCount
+// is set, the number of users subscribed to each event is included.
+func (r *Resource) ScheduledEvents(ctx context.Context, withUserCount bool) ([]discord.ScheduledEvent, error) {
+	q := url.Values{}
+	q.Set("with_user_count", strconv.FormatBool(withUserCount))
+
+	e := endpoint.ListGuildScheduledEvents(r.guildID, q.Encode())
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var events []discord.ScheduledEvent
+	if err = json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ScheduledEvent returns a scheduled event of the guild. If withUserCount
+// is set, the number of users subscribed to the event is included.
+func (r *Resource) ScheduledEvent(ctx context.Context, eventID string, withUserCount bool) (*discord.ScheduledEvent, error) {
+	q := url.Values{}
+	q.Set("with_user_count", strconv.FormatBool(withUserCount))
+
+	e := endpoint.GetGuildScheduledEvent(r.guildID, eventID, q.Encode())
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var event discord.ScheduledEvent
+	if err = json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// NewScheduledEvent is like NewScheduledEventWithReason but with no particular reason.
+func (r *Resource) NewScheduledEvent(ctx context.Context, settings *discord.ScheduledEventSettings) (*discord.ScheduledEvent, error) {
+	return r.NewScheduledEventWithReason(ctx, settings, "")
+}
+
+// NewScheduledEventWithReason creates a new scheduled event for the guild. Requires the
+// 'MANAGE_EVENTS' permission. Fires a Guild Scheduled Event Create Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) NewScheduledEventWithReason(ctx context.Context, settings *discord.ScheduledEventSettings, reason string) (*discord.ScheduledEvent, error) {
+	if settings.PrivacyLevel == nil {
+		settings.PrivacyLevel = optional.NewInt(int(discord.ScheduledEventPrivacyLevelGuildOnly))
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateGuildScheduledEvent(r.guildID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var event discord.ScheduledEvent
+	if err = json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ModifyScheduledEvent is like ModifyScheduledEventWithReason but with no particular reason.
+func (r *Resource) ModifyScheduledEvent(ctx context.Context, eventID string, settings *discord.ScheduledEventSettings) (*discord.ScheduledEvent, error) {
+	return r.ModifyScheduledEventWithReason(ctx, eventID, settings, "")
+}
+
+// ModifyScheduledEventWithReason modifies the given scheduled event of the guild. Start
+// or cancel an event by changing its status. Requires the 'MANAGE_EVENTS' permission.
+// Fires a Guild Scheduled Event Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) ModifyScheduledEventWithReason(ctx context.Context, eventID string, settings *discord.ScheduledEventSettings, reason string) (*discord.ScheduledEvent, error) {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyGuildScheduledEvent(r.guildID, eventID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var event discord.ScheduledEvent
+	if err = json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// DeleteScheduledEvent is like DeleteScheduledEventWithReason but with no particular reason.
+func (r *Resource) DeleteScheduledEvent(ctx context.Context, eventID string) error {
+	return r.DeleteScheduledEventWithReason(ctx, eventID, "")
+}
+
+// DeleteScheduledEventWithReason deletes the given scheduled event of the guild. Requires
+// the 'MANAGE_EVENTS' permission. Fires a Guild Scheduled Event Delete Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) DeleteScheduledEventWithReason(ctx context.Context, eventID, reason string) error {
+	e := endpoint.DeleteGuildScheduledEvent(r.guildID, eventID)
+	resp, err := r.client.DoWithHeader(ctx, e, nil, rest.ReasonHeader(reason))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
+
+// ScheduledEventUsers returns users subscribed to the given scheduled event of the guild,
+// sorted by user ID. limit must be between 1 and 100 and defaults to 100 if set to 0. If
+// withMember is set, guild member data is included for users that are members of the guild.
+// before and after are user IDs to paginate users, leave them empty to start from the beginning.
+func (r *Resource) ScheduledEventUsers(ctx context.Context, eventID string, limit int, withMember bool, before, after string) ([]discord.ScheduledEventUser, error) {
+	if limit < 1 || limit > 100 {
+		limit = 100
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("with_member", strconv.FormatBool(withMember))
+	if before != "" {
+		q.Set("before", before)
+	}
+	if after != "" {
+		q.Set("after", after)
+	}
+
+	e := endpoint.GetGuildScheduledEventUsers(r.guildID, eventID, q.Encode())
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var users []discord.ScheduledEventUser
+	if err = json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,160 @@
+package guild
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// Stickers returns the list of stickers of the guild. Includes the user field if the
+// current user has the 'MANAGE_EMOJIS_AND_STICKERS' permission.
+func (r *Resource) Stickers(ctx context.Context) ([]discord.Sticker, error) {
+	e := endpoint.ListGuildStickers(r.guildID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var stickers []discord.Sticker
+	if err = json.NewDecoder(resp.Body).Decode(&stickers); err != nil {
+		return nil, err
+	}
+	return stickers, nil
+}
+
+// Sticker returns a sticker from the guild. Includes the user field if the
+// current user has the 'MANAGE_EMOJIS_AND_STICKERS' permission.
+func (r *Resource) Sticker(ctx context.Context, stickerID string) (*discord.Sticker, error) {
+	e := endpoint.GetGuildSticker(r.guildID, stickerID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var sticker discord.Sticker
+	if err = json.NewDecoder(resp.Body).Decode(&sticker); err != nil {
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// NewSticker is like NewStickerWithReason but with no particular reason.
+func (r *Resource) NewSticker(ctx context.Context, name, description, tags string, file *discord.File) (*discord.Sticker, error) {
+	return r.NewStickerWithReason(ctx, name, description, tags, file, "")
+}
+
+// NewStickerWithReason creates a new sticker for the guild. tags are autocomplete/suggestion
+// tags for the sticker, usually the name of a unicode emoji. file is the sticker image, a
+// PNG, APNG or Lottie JSON file of at most 500 KB, it is closed once uploaded. Requires the
+// 'MANAGE_EMOJIS_AND_STICKERS' permission. Fires a Guild Stickers Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) NewStickerWithReason(ctx context.Context, name, description, tags string, file *discord.File, reason string) (*discord.Sticker, error) {
+	if file == nil {
+		return nil, errors.New("nil sticker file")
+	}
+
+	form := rest.MultipartForm{
+		"name":        name,
+		"description": description,
+		"tags":        tags,
+	}
+	b, contentType, err := rest.MultipartFromForm(form, *file)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateGuildSticker(r.guildID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.CustomPayload(b, contentType), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var sticker discord.Sticker
+	if err = json.NewDecoder(resp.Body).Decode(&sticker); err != nil {
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// ModifySticker is like ModifyStickerWithReason but with no particular reason.
+func (r *Resource) ModifySticker(ctx context.Context, stickerID, name, description, tags string) (*discord.Sticker, error) {
+	return r.ModifyStickerWithReason(ctx, stickerID, name, description, tags, "")
+}
+
+// ModifyStickerWithReason modifies the given sticker of the guild. Requires the
+// 'MANAGE_EMOJIS_AND_STICKERS' permission. Fires a Guild Stickers Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) ModifyStickerWithReason(ctx context.Context, stickerID, name, description, tags, reason string) (*discord.Sticker, error) {
+	st := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Tags        string `json:"tags"`
+	}{
+		Name:        name,
+		Description: description,
+		Tags:        tags,
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyGuildSticker(r.guildID, stickerID)
+	resp, err := r.client.DoWithHeader(ctx, e, rest.JSONPayload(b), rest.ReasonHeader(reason))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var sticker discord.Sticker
+	if err = json.NewDecoder(resp.Body).Decode(&sticker); err != nil {
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// DeleteSticker is like DeleteStickerWithReason but with no particular reason.
+func (r *Resource) DeleteSticker(ctx context.Context, stickerID string) error {
+	return r.DeleteStickerWithReason(ctx, stickerID, "")
+}
+
+// DeleteStickerWithReason deletes the given sticker of the guild. Requires the
+// 'MANAGE_EMOJIS_AND_STICKERS' permission. Fires a Guild Stickers Update Gateway event.
+// The given reason will be set in the audit log entry for this action.
+func (r *Resource) DeleteStickerWithReason(ctx context.Context, stickerID, reason string) error {
+	e := endpoint.DeleteGuildSticker(r.guildID, stickerID)
+	resp, err := r.client.DoWithHeader(ctx, e, nil, rest.ReasonHeader(reason))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package guild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// Templates returns the list of templates of the guild. Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) Templates(ctx context.Context) ([]discord.GuildTemplate, error) {
+	e := endpoint.GetGuildTemplates(r.guildID)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var templates []discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// NewTemplate creates a template for the guild, from its current state. description is
+// optional and can be at most 120 characters long. Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) NewTemplate(ctx context.Context, name, description string) (*discord.GuildTemplate, error) {
+	st := struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}{
+		Name:        name,
+		Description: description,
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.CreateGuildTemplate(r.guildID)
+	resp, err := r.client.Do(ctx, e, rest.JSONPayload(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// The documentation does not specify whether a 200 or a 201 is returned.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var t discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SyncTemplate updates the given template of the guild so it matches the current
+// state of the guild. Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) SyncTemplate(ctx context.Context, code string) (*discord.GuildTemplate, error) {
+	e := endpoint.SyncGuildTemplate(r.guildID, code)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var t discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ModifyTemplate modifies the name and description of the given template of the guild.
+// An empty description removes it. Requires the 'MANAGE_GUILD' permission.
+func (r *Resource) ModifyTemplate(ctx context.Context, code, name, description string) (*discord.GuildTemplate, error) {
+	st := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{
+		Name:        name,
+		Description: description,
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.ModifyGuildTemplate(r.guildID, code)
+	resp, err := r.client.Do(ctx, e, rest.JSONPayload(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var t discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTemplate deletes the given template of the guild. Requires the 'MANAGE_GUILD'
+// permission. Returns the deleted template on success.
+func (r *Resource) DeleteTemplate(ctx context.Context, code string) (*discord.GuildTemplate, error) {
+	e := endpoint.DeleteGuildTemplate(r.guildID, code)
+	resp, err := r.client.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var t discord.GuildTemplate
+	if err = json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
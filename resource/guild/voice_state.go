@@ -0,0 +1,87 @@
+package guild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+)
+
+// RequestToSpeak requests to speak in the given stage channel of the guild, the current
+// user must already be connected to it. Requires the 'REQUEST_TO_SPEAK' permission.
+// Fires a Voice State Update Gateway event.
+func (r *Resource) RequestToSpeak(ctx context.Context, channelID string) error {
+	now := time.Now().UTC()
+	st := struct {
+		ChannelID               string     `json:"channel_id"`
+		RequestToSpeakTimestamp *time.Time `json:"request_to_speak_timestamp"`
+	}{
+		ChannelID:               channelID,
+		RequestToSpeakTimestamp: &now,
+	}
+	return r.modifyVoiceState(ctx, endpoint.ModifyCurrentUserVoiceState(r.guildID), st)
+}
+
+// CancelRequestToSpeak cancels a previous request to speak in
+// the given stage channel of the guild made by the current user.
+// Fires a Voice State Update Gateway event.
+func (r *Resource) CancelRequestToSpeak(ctx context.Context, channelID string) error {
+	st := struct {
+		ChannelID               string     `json:"channel_id"`
+		RequestToSpeakTimestamp *time.Time `json:"request_to_speak_timestamp"`
+	}{
+		ChannelID: channelID,
+	}
+	return r.modifyVoiceState(ctx, endpoint.ModifyCurrentUserVoiceState(r.guildID), st)
+}
+
+// SetSuppress suppresses or unsuppresses the current user in the given stage channel of the
+// guild, making them a listener or a speaker. The current user must already be connected to
+// the channel. Unsuppressing requires the 'MUTE_MEMBERS' permission, otherwise see
+// RequestToSpeak. Fires a Voice State Update Gateway event.
+func (r *Resource) SetSuppress(ctx context.Context, channelID string, suppress bool) error {
+	st := struct {
+		ChannelID string `json:"channel_id"`
+		Suppress  bool   `json:"suppress"`
+	}{
+		ChannelID: channelID,
+		Suppress:  suppress,
+	}
+	return r.modifyVoiceState(ctx, endpoint.ModifyCurrentUserVoiceState(r.guildID), st)
+}
+
+// SetUserSuppress suppresses or unsuppresses the given user in the given stage channel of the
+// guild, making them a listener or a speaker. The user must already be connected to the
+// channel. Requires the 'MUTE_MEMBERS' permission. Fires a Voice State Update Gateway event.
+func (r *Resource) SetUserSuppress(ctx context.Context, channelID, userID string, suppress bool) error {
+	st := struct {
+		ChannelID string `json:"channel_id"`
+		Suppress  bool   `json:"suppress"`
+	}{
+		ChannelID: channelID,
+		Suppress:  suppress,
+	}
+	return r.modifyVoiceState(ctx, endpoint.ModifyUserVoiceState(r.guildID, userID), st)
+}
+
+func (r *Resource) modifyVoiceState(ctx context.Context, e *endpoint.Endpoint, st interface{}) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(ctx, e, rest.JSONPayload(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skwair/harmony/internal/backoff"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/log"
+)
+
+// defaultRetries is the number of times a Client retries a failed request by default.
+const defaultRetries = 3
+
+// doFunc sends a request to an endpoint that does not need authentication.
+type doFunc func(ctx context.Context, e *endpoint.Endpoint, p *rest.Payload, h http.Header) (*http.Response, error)
+
+// Client allows to use a webhook given its ID and token, without authentication.
+// It has its own rate limiter and retries requests that fail because of network
+// errors or server errors, see WithRetries. Messages sent through a Client are sent one at a time,
+// in the order the calls were made, even when sent from multiple goroutines.
+// Create one with NewClient or NewClientFromURL. It is safe for concurrent use.
+type Client struct {
+	id    string
+	token string
+
+	httpClient *http.Client
+	logger     log.Logger
+	retries    int
+	backoff    *backoff.Exponential
+	do         doFunc
+
+	// Queue of messages waiting to be sent.
+	mu      sync.Mutex
+	queue   []*sendJob
+	sending bool
+}
+
+// ClientOption is a function that configures a Client.
+// It is used in NewClient and NewClientFromURL.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the http.Client to use when making requests. Defaults to an
+// http.Client with a 30 seconds timeout.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithRetries sets the number of times a request is retried when it fails because of
+// a network error or a server error, 0 disabling retries. Defaults to 3.
+// Requests that send messages are only retried if they failed before reaching
+// Discord (e.g. the connection could not be established), so a message is never
+// sent twice.
+func WithRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// WithBackoff sets the backoff strategy used to wait between retries.
+func WithBackoff(baseDelay, maxDelay time.Duration, factor, jitter float64) ClientOption {
+	return func(c *Client) {
+		c.backoff = backoff.NewExponential(baseDelay, maxDelay, factor, jitter)
+	}
+}
+
+// WithLogger sets the logger used by the client. Defaults to a standard logger
+// writing to stderr at the info level.
+func WithLogger(l log.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// NewClient returns a new Client for the webhook with the given ID and token.
+func NewClient(id, token string, opts ...ClientOption) *Client {
+	c := &Client{
+		id:         id,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		logger:     log.NewStd(os.Stderr, log.LevelInfo),
+		retries:    defaultRetries,
+		backoff:    backoff.NewExponential(500*time.Millisecond, 10*time.Second, 2, 0.1),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.do = rest.NewClient(c.httpClient, "", "Harmony", c.logger).DoWithHeader
+
+	return c
+}
+
+// NewClientFromURL returns a new Client for the webhook with the given URL, as found
+// in the integration settings of a channel (e.g. https://discord.com/api/webhooks/ID/TOKEN).
+func NewClientFromURL(rawURL string, opts ...ClientOption) (*Client, error) {
+	id, token, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(id, token, opts...), nil
+}
+
+// parseURL returns the ID and token of a webhook given its URL.
+func parseURL(rawURL string) (id, token string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", errors.New("invalid webhook URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", "", errors.New("invalid webhook URL: missing scheme")
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, part := range parts {
+		if part != "webhooks" {
+			continue
+		}
+		if len(parts) < i+3 || parts[i+1] == "" || parts[i+2] == "" {
+			break
+		}
+		return parts[i+1], parts[i+2], nil
+	}
+
+	return "", "", errors.New("invalid webhook URL: missing webhook ID or token")
+}
+
+// ID returns the ID of the webhook of this client.
+func (c *Client) ID() string {
+	return c.id
+}
+
+// doWithRetries is like c.do but retries requests that failed because
+// of a network error or a server error, up to c.retries times. See
+// retryable for the failures that are retried.
+func (c *Client) doWithRetries(ctx context.Context, e *endpoint.Endpoint, p *rest.Payload, h http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, e, p, h)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		if attempt >= c.retries || ctx.Err() != nil || !retryable(e.Method, err) {
+			return resp, err
+		}
+
+		if err != nil {
+			c.logger.Warnf("request to webhook %s failed (attempt %d/%d): %v", c.id, attempt+1, c.retries+1, err)
+		} else {
+			c.logger.Warnf("request to webhook %s failed (attempt %d/%d): %s", c.id, attempt+1, c.retries+1, resp.Status)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(c.backoff.ForAttempt(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryable reports whether a request with the given method that failed with err,
+// or with a server error if err is nil, can be sent again. Requests with idempotent
+// methods always can. Others, such as webhook executions, could have been handled
+// by Discord even though they failed, so they are only retried if they failed
+// before being sent, otherwise the message could be sent twice.
+func retryable(method string, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	if err == nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sendJob is a message waiting to be sent by a Client.
+type sendJob struct {
+	ctx  context.Context
+	send func()
+	done chan struct{}
+	// Whether send was called, guarded by the client's mutex.
+	started bool
+}
+
+// inOrder calls send once all previously queued sends are done. It returns
+// early with an error if ctx is done before send could be called. Once send
+// is called, it always waits for it to return, since the message may have
+// been sent even if ctx is done; send must then report the error itself.
+func (c *Client) inOrder(ctx context.Context, send func()) error {
+	j := &sendJob{ctx: ctx, send: send, done: make(chan struct{})}
+
+	c.mu.Lock()
+	c.queue = append(c.queue, j)
+	if !c.sending {
+		c.sending = true
+		go c.sendQueued()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	started := j.started
+	c.mu.Unlock()
+	if started {
+		<-j.done
+		return nil
+	}
+	return ctx.Err()
+}
+
+// sendQueued sends queued messages one at a time until the queue is empty.
+func (c *Client) sendQueued() {
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			c.sending = false
+			c.mu.Unlock()
+			return
+		}
+		j := c.queue[0]
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+		// Skip messages the caller stopped waiting for.
+		j.started = j.ctx.Err() == nil
+		c.mu.Unlock()
+
+		if j.started {
+			j.send()
+		}
+		close(j.done)
+	}
+}
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/internal/backoff"
+	"github.com/skwair/harmony/internal/endpoint"
+	"github.com/skwair/harmony/internal/rest"
+	"github.com/skwair/harmony/log"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		id    string
+		token string
+		err   bool
+	}{
+		{name: "valid", url: "https://discord.com/api/webhooks/123/abc", id: "123", token: "abc"},
+		{name: "versioned API", url: "https://discord.com/api/v10/webhooks/123/abc", id: "123", token: "abc"},
+		{name: "trailing slash", url: "https://discord.com/api/webhooks/123/abc/", id: "123", token: "abc"},
+		{name: "extra path and query", url: "https://discord.com/api/webhooks/123/abc/slack?wait=true", id: "123", token: "abc"},
+		{name: "http", url: "http://localhost/api/webhooks/123/abc", id: "123", token: "abc"},
+		{name: "missing scheme", url: "discord.com/api/webhooks/123/abc", err: true},
+		{name: "missing token", url: "https://discord.com/api/webhooks/123", err: true},
+		{name: "empty token", url: "https://discord.com/api/webhooks/123//", err: true},
+		{name: "not a webhook", url: "https://discord.com/api/channels/123/abc", err: true},
+		{name: "malformed", url: "https://discord.com/%zz", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, token, err := parseURL(tt.url)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error; got ID %q and token %q", id, token)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.id || token != tt.token {
+				t.Errorf("expected ID %q and token %q; got %q and %q", tt.id, tt.token, id, token)
+			}
+		})
+	}
+}
+
+func TestDoWithRetries(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := []struct {
+		name   string
+		method string
+		// Results of successive requests, a nil error meaning a server error.
+		errs     []error
+		attempts int
+	}{
+		{name: "get server error", method: http.MethodGet, errs: []error{nil, nil}, attempts: 3},
+		{name: "get network error", method: http.MethodGet, errs: []error{readErr}, attempts: 2},
+		{name: "post server error", method: http.MethodPost, errs: []error{nil}, attempts: 1},
+		{name: "post network error", method: http.MethodPost, errs: []error{readErr}, attempts: 1},
+		{name: "post dial error", method: http.MethodPost, errs: []error{dialErr, dialErr}, attempts: 3},
+		{name: "post DNS error", method: http.MethodPost, errs: []error{&net.DNSError{Err: "no such host"}}, attempts: 2},
+		{name: "patch server error", method: http.MethodPatch, errs: []error{nil}, attempts: 1},
+		{name: "retries exhausted", method: http.MethodDelete, errs: []error{nil, nil, nil, nil}, attempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			c := testClient(func(ctx context.Context, e *endpoint.Endpoint, p *rest.Payload, h http.Header) (*http.Response, error) {
+				attempts++
+				if attempts > len(tt.errs) {
+					return response(http.StatusOK), nil
+				}
+				if err := tt.errs[attempts-1]; err != nil {
+					return nil, err
+				}
+				return response(http.StatusInternalServerError), nil
+			})
+
+			_, _ = c.doWithRetries(context.Background(), &endpoint.Endpoint{Method: tt.method}, nil, nil)
+			if attempts != tt.attempts {
+				t.Errorf("expected %d attempts; got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestSendOrder(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+	)
+	block := make(chan struct{})
+	c := testClient(func(ctx context.Context, e *endpoint.Endpoint, p *rest.Payload, h http.Header) (*http.Response, error) {
+		if e.Key == "0" {
+			<-block
+		}
+		mu.Lock()
+		sent = append(sent, e.Key)
+		mu.Unlock()
+		return response(http.StatusOK), nil
+	})
+	send := func(ctx context.Context, key string) error {
+		var err error
+		if qErr := c.inOrder(ctx, func() {
+			_, err = c.doWithRetries(ctx, &endpoint.Endpoint{Method: http.MethodPost, Key: key}, nil, nil)
+		}); qErr != nil {
+			return qErr
+		}
+		return err
+	}
+
+	// The first message blocks the queue while the others are
+	// queued one by one, from different goroutines.
+	canceled, cancel := context.WithCancel(context.Background())
+	tests := []struct {
+		key string
+		ctx context.Context
+		err error
+	}{
+		{key: "0", ctx: context.Background()},
+		{key: "1", ctx: context.Background()},
+		{key: "2", ctx: canceled, err: context.Canceled},
+		{key: "3", ctx: context.Background()},
+		{key: "4", ctx: context.Background()},
+	}
+
+	errs := make([]chan error, len(tests))
+	for i, tt := range tests {
+		errs[i] = make(chan error, 1)
+		go func(i int, ctx context.Context, key string) {
+			errs[i] <- send(ctx, key)
+		}(i, tt.ctx, tt.key)
+
+		// Wait for the first message to be sending and the others to be queued.
+		for queued := false; !queued; {
+			c.mu.Lock()
+			queued = c.sending && len(c.queue) == i
+			c.mu.Unlock()
+		}
+	}
+	// Messages that are no longer waited for are not sent.
+	cancel()
+	if err := <-errs[2]; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled message to return early; got %v", err)
+	}
+	close(block)
+
+	for i, tt := range tests {
+		if i == 2 {
+			continue
+		}
+		if err := <-errs[i]; !errors.Is(err, tt.err) {
+			t.Errorf("message %s: expected error %v; got %v", tt.key, tt.err, err)
+		}
+	}
+	if expected := []string{"0", "1", "3", "4"}; !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected messages to be sent in order %v; got %v", expected, sent)
+	}
+}
+
+func TestSendCanceledWhileSending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := testClient(func(ctx context.Context, e *endpoint.Endpoint, p *rest.Payload, h http.Header) (*http.Response, error) {
+		// The message reaches Discord, but the caller gives up before the response.
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return response(http.StatusNoContent), nil
+	})
+
+	var sent bool
+	if err := c.inOrder(ctx, func() {
+		resp, err := c.doWithRetries(ctx, &endpoint.Endpoint{Method: http.MethodPost}, nil, nil)
+		sent = err == nil && resp.StatusCode == http.StatusNoContent
+	}); err != nil {
+		t.Fatalf("expected a message that was sent not to report an error; got %v", err)
+	}
+	if !sent {
+		t.Error("expected the message to be sent")
+	}
+}
+
+func testClient(do doFunc) *Client {
+	return &Client{
+		id:      "1",
+		token:   "token",
+		logger:  log.NewStd(io.Discard, log.LevelError),
+		retries: 2,
+		backoff: backoff.NewExponential(time.Millisecond, time.Millisecond, 1, 0),
+		do:      do,
+	}
+}
+
+func response(status int) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
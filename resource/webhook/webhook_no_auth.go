@@ -13,11 +13,80 @@ import (
 	"github.com/skwair/harmony/internal/rest"
 )
 
+// noAuth returns a Client for the webhook with the given ID and token that does not
+// retry requests and shares its rate limiter with all package-level functions.
+func noAuth(id, token string) *Client {
+	return &Client{id: id, token: token, do: rest.DoWithHeader}
+}
+
 // GetWithToken returns a webhook given its ID an a token. The user field in
 // the returned webhook will be nil.
 func GetWithToken(ctx context.Context, id, token string) (*discord.Webhook, error) {
-	e := endpoint.GetWebhookWithToken(id, token)
-	resp, err := rest.Do(ctx, e, nil)
+	return noAuth(id, token).Get(ctx)
+}
+
+// ModifyWithToken is like Modify on a Webhook resource except this call does not require
+// authentication, does not allow to change the channel_id parameter in the webhook settings,
+// and does not return a user in the webhook.
+func ModifyWithToken(ctx context.Context, id, token string, s *discord.WebhookSettings) (*discord.Webhook, error) {
+	return noAuth(id, token).Modify(ctx, s)
+}
+
+// DeleteWithToken is like Delete on a webhook resource except it does not require authentication.
+func DeleteWithToken(ctx context.Context, id, token string) error {
+	return noAuth(id, token).Delete(ctx)
+}
+
+// Exec executes the webhook with the id id given its token and some
+// execution parameters. wait indicates if we should wait for server confirmation
+// of message send before response. If wait is set to false, the returned Message
+// will be nil even if there is no error. Use WithWebhookThread to send the message
+// in a thread of the channel of the webhook.
+func Exec(ctx context.Context, id, token string, p *discord.WebhookParameters, wait bool) (*discord.Message, error) {
+	return noAuth(id, token).exec(ctx, p, wait)
+}
+
+// ExecSlack executes the webhook with the id id given its token and a Slack compatible
+// payload, see https://api.slack.com/messaging/webhooks for more information about its
+// format. threadID is optional and can be set to send the message in a thread of the
+// channel of the webhook.
+func ExecSlack(ctx context.Context, id, token string, payload []byte, threadID string) error {
+	return noAuth(id, token).execSlack(ctx, payload, threadID)
+}
+
+// ExecGitHub executes the webhook with the id id given its token and a GitHub webhook
+// payload. event is the type of GitHub event this payload describes, as found in the
+// X-GitHub-Event header of the requests sent by GitHub (e.g. "push"). threadID is
+// optional and can be set to send the message in a thread of the channel of the webhook.
+func ExecGitHub(ctx context.Context, id, token, event string, payload []byte, threadID string) error {
+	return noAuth(id, token).execGitHub(ctx, event, payload, threadID)
+}
+
+// GetMessage returns a message previously sent by the webhook with the id id given its
+// token. threadID is optional and must be set if the message was sent in a thread.
+func GetMessage(ctx context.Context, id, token, messageID, threadID string) (*discord.Message, error) {
+	return noAuth(id, token).GetMessage(ctx, messageID, threadID)
+}
+
+// EditMessage edits a message previously sent by the webhook with the id id given its
+// token. Only the content, embeds, files, allowed mentions and thread parameters are
+// used, the thread must be set if the message was sent in one. Files are added to the
+// existing attachments of the message. Set the content to optional.NewNilString() to
+// remove it.
+func EditMessage(ctx context.Context, id, token, messageID string, p *discord.WebhookParameters) (*discord.Message, error) {
+	return noAuth(id, token).EditMessage(ctx, messageID, p)
+}
+
+// DeleteMessage deletes a message previously sent by the webhook with the id id given its
+// token. threadID is optional and must be set if the message was sent in a thread.
+func DeleteMessage(ctx context.Context, id, token, messageID, threadID string) error {
+	return noAuth(id, token).DeleteMessage(ctx, messageID, threadID)
+}
+
+// Get returns the webhook. The user field in the returned webhook will be nil.
+func (c *Client) Get(ctx context.Context) (*discord.Webhook, error) {
+	e := endpoint.GetWebhookWithToken(c.id, c.token)
+	resp, err := c.doWithRetries(ctx, e, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -34,17 +103,16 @@ func GetWithToken(ctx context.Context, id, token string) (*discord.Webhook, erro
 	return &w, nil
 }
 
-// ModifyWithToken is like Modify on a Webhook resource except this call does not require
-// authentication, does not allow to change the channel_id parameter in the webhook settings,
-// and does not return a user in the webhook.
-func ModifyWithToken(ctx context.Context, id, token string, s *discord.WebhookSettings) (*discord.Webhook, error) {
+// Modify modifies the webhook. It does not allow to change the channel_id parameter
+// in the webhook settings and does not return a user in the webhook.
+func (c *Client) Modify(ctx context.Context, s *discord.WebhookSettings) (*discord.Webhook, error) {
 	b, err := json.Marshal(s)
 	if err != nil {
 		return nil, err
 	}
 
-	e := endpoint.ModifyWebhookWithToken(id, token)
-	resp, err := rest.Do(ctx, e, rest.JSONPayload(b))
+	e := endpoint.ModifyWebhookWithToken(c.id, c.token)
+	resp, err := c.doWithRetries(ctx, e, rest.JSONPayload(b), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +129,10 @@ func ModifyWithToken(ctx context.Context, id, token string, s *discord.WebhookSe
 	return &w, nil
 }
 
-// DeleteWithToken is like Delete on a webhook resource except it does not require authentication.
-func DeleteWithToken(ctx context.Context, id, token string) error {
-	e := endpoint.DeleteWebhookWithToken(id, token)
-	resp, err := rest.Do(ctx, e, nil)
+// Delete deletes the webhook.
+func (c *Client) Delete(ctx context.Context) error {
+	e := endpoint.DeleteWebhookWithToken(c.id, c.token)
+	resp, err := c.doWithRetries(ctx, e, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -76,34 +144,39 @@ func DeleteWithToken(ctx context.Context, id, token string) error {
 	return nil
 }
 
-// Exec executes the webhook with the id id given its token and some
-// execution parameters. wait indicates if we should wait for server confirmation
-// of message send before response. If wait is set to false, the returned Message
-// will be nil even if there is no error.
-func Exec(ctx context.Context, id, token string, p *discord.WebhookParameters, wait bool) (*discord.Message, error) {
+// Exec executes the webhook with some execution parameters. wait indicates if we
+// should wait for server confirmation of message send before response. If wait is
+// set to false, the returned Message will be nil even if there is no error. Use
+// WithWebhookThread to send the message in a thread of the channel of the webhook.
+// Messages are sent in the order Exec, ExecSlack and ExecGitHub are called.
+func (c *Client) Exec(ctx context.Context, p *discord.WebhookParameters, wait bool) (*discord.Message, error) {
+	var (
+		msg *discord.Message
+		err error
+	)
+	if qErr := c.inOrder(ctx, func() { msg, err = c.exec(ctx, p, wait) }); qErr != nil {
+		return nil, qErr
+	}
+	return msg, err
+}
+
+func (c *Client) exec(ctx context.Context, p *discord.WebhookParameters, wait bool) (*discord.Message, error) {
 	if p == nil {
 		return nil, errors.New("nil webhook parameters")
 	}
 
-	var payload *rest.Payload
-	if len(p.Files) > 0 {
-		b, contentType, err := rest.MultipartFromFiles(p, p.Files...)
-		if err != nil {
-			return nil, err
-		}
-		payload = rest.CustomPayload(b, contentType)
-	} else {
-		b, err := json.Marshal(p)
-		if err != nil {
-			return nil, err
-		}
-		payload = rest.JSONPayload(b)
+	payload, err := webhookPayload(p)
+	if err != nil {
+		return nil, err
 	}
 
 	q := url.Values{}
 	q.Set("wait", strconv.FormatBool(wait))
-	e := endpoint.ExecuteWebhook(id, token, q.Encode())
-	resp, err := rest.Do(ctx, e, payload)
+	if p.ThreadID != "" {
+		q.Set("thread_id", p.ThreadID)
+	}
+	e := endpoint.ExecuteWebhook(c.id, c.token, q.Encode())
+	resp, err := c.doWithRetries(ctx, e, payload, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -126,3 +199,174 @@ func Exec(ctx context.Context, id, token string, p *discord.WebhookParameters, w
 	}
 	return &m, nil
 }
+
+// ExecSlack executes the webhook with a Slack compatible payload, see
+// https://api.slack.com/messaging/webhooks for more information about its format.
+// threadID is optional and can be set to send the message in a thread of the
+// channel of the webhook.
+// Messages are sent in the order Exec, ExecSlack and ExecGitHub are called.
+func (c *Client) ExecSlack(ctx context.Context, payload []byte, threadID string) error {
+	var err error
+	if qErr := c.inOrder(ctx, func() { err = c.execSlack(ctx, payload, threadID) }); qErr != nil {
+		return qErr
+	}
+	return err
+}
+
+func (c *Client) execSlack(ctx context.Context, payload []byte, threadID string) error {
+	q := url.Values{}
+	q.Set("wait", "true")
+	if threadID != "" {
+		q.Set("thread_id", threadID)
+	}
+	e := endpoint.ExecuteSlackCompatibleWebhook(c.id, c.token, q.Encode())
+	resp, err := c.doWithRetries(ctx, e, rest.JSONPayload(payload), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Discord replies with a plain "ok" instead of the message that was sent.
+	if resp.StatusCode != http.StatusOK {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
+
+// ExecGitHub executes the webhook with a GitHub webhook payload. event is the type
+// of GitHub event this payload describes, as found in the X-GitHub-Event header of
+// the requests sent by GitHub (e.g. "push"). threadID is optional and can be set to
+// send the message in a thread of the channel of the webhook.
+// Messages are sent in the order Exec, ExecSlack and ExecGitHub are called.
+func (c *Client) ExecGitHub(ctx context.Context, event string, payload []byte, threadID string) error {
+	var err error
+	if qErr := c.inOrder(ctx, func() { err = c.execGitHub(ctx, event, payload, threadID) }); qErr != nil {
+		return qErr
+	}
+	return err
+}
+
+func (c *Client) execGitHub(ctx context.Context, event string, payload []byte, threadID string) error {
+	q := url.Values{}
+	q.Set("wait", "true")
+	if threadID != "" {
+		q.Set("thread_id", threadID)
+	}
+	h := http.Header{}
+	h.Set("X-GitHub-Event", event)
+
+	e := endpoint.ExecuteGitHubCompatibleWebhook(c.id, c.token, q.Encode())
+	resp, err := c.doWithRetries(ctx, e, rest.JSONPayload(payload), h)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Events that are not supported by Discord are accepted but ignored.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
+
+// GetMessage returns a message previously sent by the webhook. threadID is
+// optional and must be set if the message was sent in a thread.
+func (c *Client) GetMessage(ctx context.Context, messageID, threadID string) (*discord.Message, error) {
+	e := endpoint.GetWebhookMessage(c.id, c.token, messageID, threadQuery(threadID))
+	resp, err := c.doWithRetries(ctx, e, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var m discord.Message
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// EditMessage edits a message previously sent by the webhook. Only the content, embeds,
+// files, allowed mentions and thread parameters are used, the thread must be set if the
+// message was sent in one. Files are added to the existing attachments of the message.
+// Set the content to optional.NewNilString() to remove it.
+func (c *Client) EditMessage(ctx context.Context, messageID string, p *discord.WebhookParameters) (*discord.Message, error) {
+	if p == nil {
+		return nil, errors.New("nil webhook parameters")
+	}
+
+	edit := &discord.WebhookParameters{
+		Content:         p.Content,
+		Embeds:          p.Embeds,
+		Files:           p.Files,
+		AllowedMentions: p.AllowedMentions,
+	}
+	payload, err := webhookPayload(edit)
+	if err != nil {
+		return nil, err
+	}
+
+	e := endpoint.EditWebhookMessage(c.id, c.token, messageID, threadQuery(p.ThreadID))
+	resp, err := c.doWithRetries(ctx, e, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var m discord.Message
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// DeleteMessage deletes a message previously sent by the webhook. threadID is
+// optional and must be set if the message was sent in a thread.
+func (c *Client) DeleteMessage(ctx context.Context, messageID, threadID string) error {
+	e := endpoint.DeleteWebhookMessage(c.id, c.token, messageID, threadQuery(threadID))
+	resp, err := c.doWithRetries(ctx, e, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return discord.NewAPIError(resp)
+	}
+	return nil
+}
+
+// webhookPayload returns the payload to send given webhook parameters,
+// using a multipart body if it has files.
+func webhookPayload(p *discord.WebhookParameters) (*rest.Payload, error) {
+	if len(p.Files) > 0 {
+		b, contentType, err := rest.MultipartFromFiles(p, p.Files...)
+		if err != nil {
+			return nil, err
+		}
+		return rest.CustomPayload(b, contentType), nil
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return rest.JSONPayload(b), nil
+}
+
+// threadQuery returns the encoded query selecting the given thread, if any.
+func threadQuery(threadID string) string {
+	q := url.Values{}
+	if threadID != "" {
+		q.Set("thread_id", threadID)
+	}
+	return q.Encode()
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/log"
 	"github.com/skwair/harmony/voice"
 )
 
@@ -13,17 +14,33 @@ import (
 // Objects returned by State methods are snapshots of original objects used
 // internally by the State. This means they are safe to be used and modified
 // but they won't be updated as new events are received.
+// Entities are kept in a StateStore, see WithStateStore for more information.
+// Errors returned by the StateStore are logged, the State then behaves as if
+// the requested object was not found.
 type State struct {
 	mu sync.RWMutex
 
+	store StateStore
+	// raw is the store wrapped by store, to access entities without
+	// updating the index, accounting or subscribers.
+	raw    StateStore
+	logger log.Logger
+
+	// See WithStateCache, WithMemberCachePolicy
+	// and WithStateGuilds for more information.
+	policy   cachePolicy
+	activity memberActivity
+
+	// See Subscribe for more information.
+	subscribers []*stateSubscriber
+	// Secondary indexes and accounting, kept up to date by the store.
+	index      *stateIndex
+	accounting *stateAccounting
+
 	me                *discord.User
-	users             map[string]*discord.User
-	guilds            map[string]*discord.Guild
-	presences         map[string]*discord.Presence // Presence by user ID.
-	channels          map[string]*discord.Channel
-	dms               map[string]*discord.Channel
-	groups            map[string]*discord.Channel
 	unavailableGuilds map[string]*discord.UnavailableGuild
+	// Optional, nil if the message cache is disabled.
+	messages *messageCache
 
 	rtt time.Duration
 
@@ -32,16 +49,31 @@ type State struct {
 }
 
 // newState returns a new initialized state, ready to be used.
-func newState() *State {
-	return &State{
-		users:             make(map[string]*discord.User),
-		guilds:            make(map[string]*discord.Guild),
-		presences:         make(map[string]*discord.Presence),
-		channels:          make(map[string]*discord.Channel),
-		dms:               make(map[string]*discord.Channel),
-		groups:            make(map[string]*discord.Channel),
+func newState(store StateStore, policy cachePolicy, logger log.Logger) *State {
+	s := &State{
+		raw:               store,
+		logger:            logger,
+		policy:            policy,
+		activity:          memberActivity{last: make(map[string]map[string]time.Time)},
+		index:             newStateIndex(),
+		accounting:        newStateAccounting(),
 		unavailableGuilds: make(map[string]*discord.UnavailableGuild),
 	}
+	s.store = &trackingStore{StateStore: store, s: s}
+
+	// The store may already hold entities, e.g. when kept on disk.
+	s.storeError(s.build(store))
+	return s
+}
+
+// storeError logs an error returned by the store, if any.
+// It reports whether there was an error.
+func (s *State) storeError(err error) bool {
+	if err != nil {
+		s.logger.Errorf("state store: %v", err)
+		return true
+	}
+	return false
 }
 
 // Me returns the current user from the state.
@@ -57,7 +89,11 @@ func (s *State) User(id string) *discord.User {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.users[id].Clone()
+	u, err := s.store.User(id)
+	if s.storeError(err) {
+		return nil
+	}
+	return u
 }
 
 // Guild returns a guild given its ID from the state.
@@ -65,7 +101,15 @@ func (s *State) Guild(id string) *discord.Guild {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.guilds[id].Clone()
+	g, err := s.store.Guild(id)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+
+	if s.storeError(s.fillGuild(g)) {
+		return nil
+	}
+	return g
 }
 
 // Channel returns a channel given its ID from the state.
@@ -73,23 +117,27 @@ func (s *State) Channel(id string) *discord.Channel {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.channels[id].Clone()
+	ch, err := s.store.Channel(id)
+	if s.storeError(err) {
+		return nil
+	}
+	return ch
 }
 
 // GroupDM returns a group DM given its ID from the state.
 func (s *State) GroupDM(id string) *discord.Channel {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	return s.groups[id].Clone()
+	if ch := s.Channel(id); ch != nil && ch.Type == discord.ChannelTypeGroupDM {
+		return ch
+	}
+	return nil
 }
 
 // DM returns a DM given its ID from the state.
 func (s *State) DM(id string) *discord.Channel {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	return s.dms[id].Clone()
+	if ch := s.Channel(id); ch != nil && ch.Type == discord.ChannelTypeDM {
+		return ch
+	}
+	return nil
 }
 
 // Presence returns a presence given a user ID from the state.
@@ -97,7 +145,11 @@ func (s *State) Presence(userID string) *discord.Presence {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.presences[userID].Clone()
+	p, err := s.store.Presence(userID)
+	if s.storeError(err) {
+		return nil
+	}
+	return p
 }
 
 // UnavailableGuild returns an unavailable guild given its ID from the state.
@@ -113,12 +165,11 @@ func (s *State) Users() map[string]*discord.User {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	newMap := make(map[string]*discord.User)
-	for k, v := range s.users {
-		newMap[k] = v.Clone()
+	users, err := s.store.Users()
+	if s.storeError(err) {
+		return nil
 	}
-
-	return newMap
+	return users
 }
 
 // Guilds returns a map of guild ID to guild from the state.
@@ -126,12 +177,17 @@ func (s *State) Guilds() map[string]*discord.Guild {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	newMap := make(map[string]*discord.Guild)
-	for k, v := range s.guilds {
-		newMap[k] = v.Clone()
+	guilds, err := s.store.Guilds()
+	if s.storeError(err) {
+		return nil
 	}
 
-	return newMap
+	for _, g := range guilds {
+		if s.storeError(s.fillGuild(g)) {
+			return nil
+		}
+	}
+	return guilds
 }
 
 // Channels returns a map of channels ID to channels from the state.
@@ -139,37 +195,31 @@ func (s *State) Channels() map[string]*discord.Channel {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	newMap := make(map[string]*discord.Channel)
-	for k, v := range s.channels {
-		newMap[k] = v.Clone()
+	channels, err := s.store.Channels()
+	if s.storeError(err) {
+		return nil
 	}
-
-	return newMap
+	return channels
 }
 
 // GroupDMs returns a map of group DM ID to group DM from the state.
 func (s *State) GroupDMs() map[string]*discord.Channel {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	newMap := make(map[string]*discord.Channel)
-	for k, v := range s.groups {
-		newMap[k] = v.Clone()
-	}
-
-	return newMap
+	return s.channelsOfType(discord.ChannelTypeGroupDM)
 }
 
 // DMs returns a map of DM ID to DM from the state.
 func (s *State) DMs() map[string]*discord.Channel {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	return s.channelsOfType(discord.ChannelTypeDM)
+}
 
+// channelsOfType returns a map of channel ID to channels of the given type from the state.
+func (s *State) channelsOfType(typ discord.ChannelType) map[string]*discord.Channel {
 	newMap := make(map[string]*discord.Channel)
-	for k, v := range s.dms {
-		newMap[k] = v.Clone()
+	for k, v := range s.Channels() {
+		if v.Type == typ {
+			newMap[k] = v
+		}
 	}
-
 	return newMap
 }
 
@@ -178,12 +228,11 @@ func (s *State) Presences() map[string]*discord.Presence {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	newMap := make(map[string]*discord.Presence)
-	for k, v := range s.presences {
-		newMap[k] = v.Clone()
+	presences, err := s.store.Presences()
+	if s.storeError(err) {
+		return nil
 	}
-
-	return newMap
+	return presences
 }
 
 // UnavailableGuilds returns a map of guild ID to unavailable guild from the state.
@@ -209,87 +258,269 @@ func (s *State) RTT() time.Duration {
 	return s.rtt
 }
 
+// fillGuild sets the roles, members, channels, presences and voice states
+// of the given guild from the store. Like members, presences are not marked
+// as used for the memory budget, since the whole guild is read.
+func (s *State) fillGuild(g *discord.Guild) error {
+	var err error
+	if g.Roles, err = s.store.Roles(g.ID); err != nil {
+		return err
+	}
+	if g.Channels, err = s.store.GuildChannels(g.ID); err != nil {
+		return err
+	}
+	if g.VoiceStates, err = s.store.VoiceStates(g.ID); err != nil {
+		return err
+	}
+	if g.Members, err = s.store.Members(g.ID); err != nil {
+		return err
+	}
+
+	g.Presences = nil
+	for i := 0; i < len(g.Members); i++ {
+		m := &g.Members[i]
+
+		// Users are kept up to date independently of members.
+		u, err := s.store.User(m.User.ID)
+		if err != nil {
+			return err
+		}
+		if u != nil {
+			m.User = u
+		}
+
+		// Not a use of the presence, it must not delay its eviction.
+		p, err := s.raw.Presence(m.User.ID)
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			g.Presences = append(g.Presences, *p)
+		}
+	}
+
+	return nil
+}
+
 // setInitialState initializes the state with a Ready event received from the gateway.
 func (s *State) setInitialState(r *Ready) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.notifySetMe(s.me, r.User)
 	s.me = r.User
 	for i := 0; i < len(r.Guilds); i++ {
 		g := &r.Guilds[i]
 
-		if g.Unavailable == nil {
-			// We were removed from this guild.
+		if g.Unavailable == nil || !s.policy.cacheGuild(g.ID) {
+			// We were removed from this guild or it is not cached.
 			continue
 		}
 
-		s.guilds[g.ID] = &discord.Guild{
+		s.storeError(s.store.SetGuild(&discord.Guild{
 			ID:          g.ID,
 			Unavailable: *g.Unavailable,
-		}
+		}))
 	}
 }
 
 // updateGuild adds the given guild to the state. If it already
 // exists, it merges its content with the existing guild.
 // It also removes this guild from the UnavailableGuilds map if
-// it was present.
-func (s *State) updateGuild(g *discord.Guild) {
+// it was present. It returns the guild as it was before the update
+// with its roles, or nil if it was not in the state.
+func (s *State) updateGuild(g *discord.Guild) *discord.Guild {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Make sure we do not overwrite fields
-	// that were set before but not anymore.
-	old := s.guilds[g.ID]
+	old, err := s.setGuild(g)
+	s.storeError(err)
+	return old
+}
+
+// setGuild is like updateGuild but stops at the first store error and
+// returns it. It must be called with the State lock held.
+func (s *State) setGuild(g *discord.Guild) (*discord.Guild, error) {
+	for i := 0; i < len(g.Channels); i++ {
+		g.Channels[i].GuildID = g.ID
+	}
+	for i := 0; i < len(g.VoiceStates); i++ {
+		g.VoiceStates[i].GuildID = g.ID
+	}
+
+	if !s.policy.cacheGuild(g.ID) {
+		return nil, nil
+	}
+
+	old, err := s.store.Guild(g.ID)
+	if err != nil {
+		return nil, err
+	}
 	if old != nil {
-		if g.Roles == nil {
-			g.Roles = old.Roles
+		if old.Roles, err = s.store.Roles(g.ID); err != nil {
+			return nil, err
 		}
+
+		// Make sure we do not overwrite fields
+		// that were set before but not anymore.
 		if g.Emojis == nil {
 			g.Emojis = old.Emojis
 		}
-		if g.VoiceStates == nil {
-			g.VoiceStates = old.VoiceStates
+		if g.Stickers == nil {
+			g.Stickers = old.Stickers
 		}
-		if g.Members == nil {
-			g.Members = old.Members
+		if g.ScheduledEvents == nil {
+			g.ScheduledEvents = old.ScheduledEvents
 		}
-		if g.Channels == nil {
-			g.Channels = old.Channels
+		if g.StageInstances == nil {
+			g.StageInstances = old.StageInstances
 		}
-		if g.Presences == nil {
-			g.Presences = old.Presences
+	}
+
+	if g.Roles != nil && s.policy.flags.Has(CacheRoles) {
+		if err = s.replaceRoles(g.ID, g.Roles); err != nil {
+			return old, err
 		}
 	}
 
-	for i := 0; i < len(g.Channels); i++ {
-		ch := &g.Channels[i]
-		ch.GuildID = g.ID
-		s.channels[ch.ID] = ch
+	if g.Channels != nil && s.policy.flags.Has(CacheChannels) {
+		if err = s.replaceChannels(g.ID, g.Channels); err != nil {
+			return old, err
+		}
+	}
+
+	// Voice states must be set before members for the MemberCacheVoice policy to apply.
+	if g.VoiceStates != nil && s.policy.flags.Has(CacheVoiceStates) {
+		if err = s.replaceVoiceStates(g.ID, g.VoiceStates); err != nil {
+			return old, err
+		}
 	}
 
 	for i := 0; i < len(g.Members); i++ {
-		m := &g.Members[i]
-		s.users[m.User.ID] = m.User
+		if err = s.setMember(g.ID, &g.Members[i]); err != nil {
+			return old, err
+		}
+	}
+
+	if s.policy.flags.Has(CachePresences) {
+		for i := 0; i < len(g.Presences); i++ {
+			if err = s.store.SetPresence(&g.Presences[i]); err != nil {
+				return old, err
+			}
+		}
+	}
+
+	// Roles, members, etc. are stored on their own.
+	guild := *g
+	if !s.policy.flags.Has(CacheEmojis) {
+		guild.Emojis = nil
+	}
+	if !s.policy.flags.Has(CacheStickers) {
+		guild.Stickers = nil
+	}
+	if !s.policy.flags.Has(CacheScheduledEvents) {
+		guild.ScheduledEvents = nil
+	}
+	if !s.policy.flags.Has(CacheStageInstances) {
+		guild.StageInstances = nil
+	}
+	guild.Roles = nil
+	guild.Channels = nil
+	guild.VoiceStates = nil
+	guild.Members = nil
+	guild.Presences = nil
+
+	if err = s.store.SetGuild(&guild); err != nil {
+		return old, err
+	}
+	s.deleteUnavailableGuild(g.ID)
+
+	return old, nil
+}
+
+// replaceRoles replaces all roles of a guild in the store with the given roles.
+func (s *State) replaceRoles(guildID string, roles []discord.Role) error {
+	old, err := s.store.Roles(guildID)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(roles))
+	for i := 0; i < len(roles); i++ {
+		keep[roles[i].ID] = true
+		if err = s.store.SetRole(guildID, &roles[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range old {
+		if !keep[r.ID] {
+			if err = s.store.DeleteRole(guildID, r.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replaceChannels replaces all channels of a guild in the store with the given channels.
+func (s *State) replaceChannels(guildID string, channels []discord.Channel) error {
+	old, err := s.store.GuildChannels(guildID)
+	if err != nil {
+		return err
 	}
 
-	for i := 0; i < len(g.Presences); i++ {
-		p := &g.Presences[i]
-		s.presences[p.User.ID] = p
+	keep := make(map[string]bool, len(channels))
+	for i := 0; i < len(channels); i++ {
+		keep[channels[i].ID] = true
+		if err = s.store.SetChannel(&channels[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, ch := range old {
+		if !keep[ch.ID] {
+			if err = s.store.DeleteChannel(ch.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replaceVoiceStates replaces all voice states of a guild in the store with the given voice states.
+func (s *State) replaceVoiceStates(guildID string, states []voice.State) error {
+	old, err := s.store.VoiceStates(guildID)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(states))
+	for i := 0; i < len(states); i++ {
+		keep[states[i].UserID] = true
+		if err = s.store.SetVoiceState(&states[i]); err != nil {
+			return err
+		}
 	}
 
-	s.guilds[g.ID] = g
-	delete(s.unavailableGuilds, g.ID)
+	for _, vs := range old {
+		if !keep[vs.UserID] {
+			if err = s.store.DeleteVoiceState(guildID, vs.UserID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// removeGuild removes a guild from the Guilds map, adding it to
+// removeGuild removes a guild from the state, adding it to
 // the UnavailableGuilds map.
 func (s *State) removeGuild(g *discord.UnavailableGuild) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.guilds, g.ID)
-	s.unavailableGuilds[g.ID] = g
+	s.storeError(s.store.DeleteGuild(g.ID))
+	s.setUnavailableGuild(g)
 }
 
 // updateGuildEmojis updates the emojis available in a guild if it
@@ -298,230 +529,252 @@ func (s *State) updateGuildEmojis(guildID string, emojis []discord.Emoji) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.guilds[guildID] != nil {
-		s.guilds[guildID].Emojis = emojis
+	if !s.policy.flags.Has(CacheEmojis) {
+		return
+	}
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return
 	}
+
+	g.Emojis = emojis
+	s.storeError(s.store.SetGuild(g))
 }
 
-// updateGuildVoiceStates updates the voice states in a guild if it is
-// already tracked by the state, does nothing otherwise.
-func (s *State) updateGuildVoiceStates(vsu *voice.StateUpdate) {
+// updateGuildStickers updates the stickers available in a guild if it
+// is already tracked by the state, does nothing otherwise.
+func (s *State) updateGuildStickers(guildID string, stickers []discord.Sticker) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	g := s.guilds[vsu.GuildID]
-	if g == nil {
+	if !s.policy.flags.Has(CacheStickers) {
 		return
 	}
 
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return
+	}
+
+	g.Stickers = stickers
+	s.storeError(s.store.SetGuild(g))
+}
+
+// updateGuildVoiceStates updates the voice states in a guild if it is
+// already tracked by the state, does nothing otherwise. member is the
+// guild member the voice state update is for, if any. It returns the
+// previous voice state of the user, or nil if they were not connected
+// to a voice channel. tracked reports whether voice states of this guild
+// are tracked, in which case old is known to be the previous voice state.
+func (s *State) updateGuildVoiceStates(vsu *voice.StateUpdate, member *discord.GuildMember) (old *voice.State, tracked bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.policy.flags.Has(CacheVoiceStates) {
+		return nil, false
+	}
+
+	g, err := s.store.Guild(vsu.GuildID)
+	if s.storeError(err) || g == nil {
+		return nil, false
+	}
+
+	old, err = s.store.VoiceState(vsu.GuildID, vsu.UserID)
+	if s.storeError(err) {
+		return nil, false
+	}
+
 	// If we have a channel ID, then it means it is either a new voice
 	// state or an update to an existing one.
 	if vsu.ChannelID != nil {
-		index := -1
-		// Check if we already have a voice state for this user.
-		// If we do, save the index of the voice state.
-		for i, state := range g.VoiceStates {
-			if state.UserID == vsu.UserID {
-				index = i
-			}
-		}
-
-		// This state is already tracked, update it.
-		if index != -1 {
-			g.VoiceStates[index] = vsu.State
-		} else { // This is a new voice state, append it.
-			g.VoiceStates = append(g.VoiceStates, vsu.State)
-		}
+		s.storeError(s.store.SetVoiceState(&vsu.State))
 	} else { // We have no channel ID, the user left the channel, remove it from the state.
-		// Find the index of the voice state update to remove.
-		var toRemove int
-		for i, update := range g.VoiceStates {
-			if update.UserID == vsu.UserID {
-				toRemove = i
-			}
-		}
+		s.storeError(s.store.DeleteVoiceState(vsu.GuildID, vsu.UserID))
+	}
+
+	if !s.policy.members.Has(MemberCacheVoice) {
+		return old, true
+	}
 
-		// Remove it, without preserving the order of the slice.
-		g.VoiceStates[toRemove] = g.VoiceStates[len(g.VoiceStates)-1]
-		g.VoiceStates = g.VoiceStates[:len(g.VoiceStates)-1]
+	// Members that join a voice channel may need to be cached, and those
+	// that leave may need to be evicted.
+	if member == nil {
+		member, err = s.store.Member(vsu.GuildID, vsu.UserID)
+		if s.storeError(err) || member == nil {
+			return old, true
+		}
 	}
+	if member.User == nil {
+		member.User = &discord.User{ID: vsu.UserID}
+	}
+	s.storeError(s.setMember(vsu.GuildID, member))
+
+	return old, true
 }
 
-// updatePresence updates a presence both in the presences map as
-// well as in the Guilds map.
-func (s *State) updatePresence(p *discord.Presence) {
+// updatePresence updates a presence in the state. It returns
+// the previous presence, or nil if it was not in the state.
+func (s *State) updatePresence(p *discord.Presence) *discord.Presence {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check that the concerned user exists in the state.
-	if s.users[p.User.ID] == nil {
-		return
+	if !s.policy.flags.Has(CachePresences) || !s.policy.cacheGuild(p.GuildID) {
+		return nil
 	}
 
-	// NOTE: consider removing the presence from the presence map
-	// if the user goes offline.
-	s.presences[p.User.ID] = p
-
-	// Check that his guild exists in the state.
-	if s.guilds[p.GuildID] == nil {
-		return
+	// Check that the concerned user exists in the state.
+	if s.policy.flags.Has(CacheUsers) {
+		u, err := s.store.User(p.User.ID)
+		if s.storeError(err) || u == nil {
+			return nil
+		}
 	}
 
-	for i := 0; i < len(s.guilds[p.GuildID].Presences); i++ {
-		if s.guilds[p.GuildID].Presences[i].User.ID == p.User.ID {
-			s.guilds[p.GuildID].Presences[i] = *p
-		}
+	old, err := s.store.Presence(p.User.ID)
+	if s.storeError(err) {
+		return nil
 	}
+
+	// NOTE: consider removing the presence from the store
+	// if the user goes offline.
+	s.storeError(s.store.SetPresence(p))
+
+	return old
 }
 
-// updateUser updates a user in the Users map (or the User) as well
-// as in all the guilds this user is.
-func (s *State) updateUser(u *discord.User) {
+// updateUser updates a user in the state (or the current user).
+// Guild members always reflect the latest version of their user.
+// It returns the previous user, or nil if it was not in the state.
+func (s *State) updateUser(u *discord.User) *discord.User {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if u.ID == s.me.ID {
-		s.me = u
-	} else {
-		s.users[u.ID] = u
+		old := s.me
+		s.notifySetMe(old, u)
+		s.me = u.Clone()
+		return old
 	}
 
-	for id := range s.guilds {
-		g := s.guilds[id]
-		for i := 0; i < len(g.Members); i++ {
-			if g.Members[i].User.ID == u.ID {
-				g.Members[i].User = u
-			}
-		}
+	if !s.policy.flags.Has(CacheUsers) {
+		return nil
+	}
+
+	old, err := s.store.User(u.ID)
+	if s.storeError(err) {
+		return nil
 	}
+	s.storeError(s.store.SetUser(u))
+	return old
 }
 
-// updateChannel updates a channel in the channel map as well as in
-// the guild this channel is for guild text, voice and category channels.
+// updateChannel updates a channel in the state.
 // If the channel does not exist yet, it is added.
-func (s *State) updateChannel(c *discord.Channel) {
+// It returns the previous channel, or nil if it was not in the state.
+func (s *State) updateChannel(c *discord.Channel) *discord.Channel {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	switch c.Type {
-	case discord.ChannelTypeDM:
-		s.dms[c.ID] = c
-
-	case discord.ChannelTypeGroupDM:
-		s.groups[c.ID] = c
-
-	case discord.ChannelTypeGuildText, discord.ChannelTypeGuildVoice, discord.ChannelTypeGuildCategory,
-		discord.ChannelTypeGuildNews, discord.ChannelTypeGuildStore:
-		guild := s.guilds[c.GuildID]
-		var found bool
-		for i := 0; i < len(guild.Channels); i++ {
-			if guild.Channels[i].ID == c.ID {
-				guild.Channels[i] = *c
-				found = true
-				break
-			}
-		}
-		if !found {
-			guild.Channels = append(guild.Channels, *c)
-		}
+	if !s.policy.flags.Has(CacheChannels) || !s.policy.cacheGuild(c.GuildID) {
+		return nil
 	}
 
-	s.channels[c.ID] = c
+	old, err := s.store.Channel(c.ID)
+	if s.storeError(err) {
+		return nil
+	}
+	s.storeError(s.store.SetChannel(c))
+	return old
 }
 
-// removeChannel removes the given channel from the channels map as
-// well as the guild it was in for guild text, voice and category channels.
+// removeChannel removes the given channel from the state, as
+// well as cached messages of this channel.
 func (s *State) removeChannel(c *discord.Channel) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	switch c.Type {
-	case discord.ChannelTypeDM:
-		delete(s.dms, c.ID)
-
-	case discord.ChannelTypeGroupDM:
-		delete(s.groups, c.ID)
-
-	case discord.ChannelTypeGuildText, discord.ChannelTypeGuildVoice, discord.ChannelTypeGuildCategory:
-		g := s.guilds[c.GuildID]
-		if g == nil {
-			return
-		}
-		chs := g.Channels
-		for i := 0; i < len(chs); i++ {
-			if chs[i].ID == c.ID {
-				chs = append(chs[:i], chs[i+1:]...)
-				s.guilds[c.GuildID].Channels = chs
-				break
-			}
-		}
+	s.storeError(s.store.DeleteChannel(c.ID))
+	if s.messages != nil {
+		s.messages.deleteChannel(c.ID)
 	}
-
-	delete(s.channels, c.ID)
 }
 
-// updatePins updates the LastPinTimestamp of a channel in the Channel map
-// and in the DM, group DM or guild this channel is in.
+// updatePins updates the LastPinTimestamp of a channel.
 func (s *State) updatePins(p *ChannelPinsUpdate) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	ch := s.channels[p.ChannelID]
-	if ch == nil {
+	if !s.policy.flags.Has(CacheChannels) {
 		return
 	}
 
-	ch.LastPinTimestamp = p.LastPinTimestamp
-	switch ch.Type {
-	case discord.ChannelTypeDM:
-		s.dms[p.ChannelID].LastPinTimestamp = p.LastPinTimestamp
-
-	case discord.ChannelTypeGroupDM:
-		s.groups[p.ChannelID].LastPinTimestamp = p.LastPinTimestamp
-
-	case discord.ChannelTypeGuildText:
-		chs := s.guilds[ch.GuildID].Channels
-		for i := 0; i < len(chs); i++ {
-			if chs[i].ID == p.ChannelID {
-				chs[i].LastPinTimestamp = p.LastPinTimestamp
-				break
-			}
-		}
+	ch, err := s.store.Channel(p.ChannelID)
+	if s.storeError(err) || ch == nil {
+		return
 	}
+
+	ch.LastPinTimestamp = p.LastPinTimestamp
+	s.storeError(s.store.SetChannel(ch))
 }
 
 func (s *State) guildMemberAdd(m *GuildMemberAdd) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// This is a new user, add it to the users map.
-	if s.users[m.User.ID] == nil {
-		s.users[m.User.ID] = m.User
+	if !s.policy.cacheGuild(m.GuildID) {
+		return
 	}
 
-	g := s.guilds[m.GuildID]
-	if g == nil {
+	g, err := s.store.Guild(m.GuildID)
+	if s.storeError(err) || g == nil {
 		return
 	}
 
 	g.MemberCount++
-	g.Members = append(g.Members, *m.GuildMember)
+	s.storeError(s.store.SetGuild(g))
+	s.storeError(s.setMember(m.GuildID, m.GuildMember))
 }
 
-func (s *State) guildMemberUpdate(m *GuildMemberUpdate) {
+// guildMemberUpdate updates a guild member in the state. It returns
+// the previous member, or nil if it was not in the state.
+func (s *State) guildMemberUpdate(m *GuildMemberUpdate) *discord.GuildMember {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	g := s.guilds[m.GuildID]
-	if g == nil {
+	member, err := s.store.Member(m.GuildID, m.User.ID)
+	if s.storeError(err) || member == nil {
+		return nil
+	}
+	old := member.Clone()
+
+	member.Roles = m.Roles
+	member.User = m.User
+	member.Nick = m.Nick
+	member.CommunicationDisabledUntil = m.CommunicationDisabledUntil
+	s.storeError(s.store.SetMember(m.GuildID, member))
+
+	return old
+}
+
+// guildMembersChunk adds members and presences received in a guild members chunk
+// to the guild they belong to. Members already tracked are updated.
+func (s *State) guildMembersChunk(chunk *GuildMembersChunk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.store.Guild(chunk.GuildID)
+	if s.storeError(err) || g == nil {
 		return
 	}
 
-	for i := 0; i < len(g.Members); i++ {
-		if g.Members[i].User.ID == m.User.ID {
-			g.Members[i].Roles = m.Roles
-			g.Members[i].User = m.User
-			g.Members[i].Nick = m.Nick
+	for i := 0; i < len(chunk.Members); i++ {
+		s.storeError(s.setMember(chunk.GuildID, &chunk.Members[i]))
+	}
+
+	if s.policy.flags.Has(CachePresences) {
+		for i := 0; i < len(chunk.Presences); i++ {
+			s.storeError(s.store.SetPresence(&chunk.Presences[i]))
 		}
 	}
 }
@@ -530,61 +783,52 @@ func (s *State) guildMemberRemove(r *GuildMemberRemove) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	g := s.guilds[r.GuildID]
-	if g == nil {
+	g, err := s.store.Guild(r.GuildID)
+	if s.storeError(err) || g == nil {
 		return
 	}
 
 	g.MemberCount--
-	for i := 0; i < len(g.Members); i++ {
-		if g.Members[i].User.ID == r.User.ID {
-			g.Members = append(g.Members[:i], g.Members[i+1:]...)
-			break
-		}
-	}
+	s.storeError(s.store.SetGuild(g))
+	s.storeError(s.store.DeleteMember(r.GuildID, r.User.ID))
 
-	var found bool
-	for id := range s.guilds {
-		g := s.guilds[id]
-		for _, m := range g.Members {
-			if m.User.ID == r.User.ID {
-				found = true
-				break
-			}
-		}
-	}
-	// If this user is in no other guild, remove it from the state.
-	if !found {
-		delete(s.users, r.User.ID)
+	// The index knows which guilds a user is a member of, so
+	// there is no need to look into every guild of the store.
+	if len(s.index.userGuilds[r.User.ID]) > 0 {
+		return
 	}
+
+	// This user is in no other guild, remove it from the state.
+	s.storeError(s.store.DeleteUser(r.User.ID))
+	s.storeError(s.store.DeletePresence(r.User.ID))
 }
 
 // guildRoleCreate adds a role to a guild.
 func (s *State) guildRoleCreate(gr *GuildRole) {
+	s.guildRoleUpdate(gr)
+}
+
+// guildRoleUpdate updates a role in a guild. It returns the
+// previous role, or nil if it was not in the state.
+func (s *State) guildRoleUpdate(gr *GuildRole) *discord.Role {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	g := s.guilds[gr.GuildID]
-	if g == nil {
-		return
+	if !s.policy.flags.Has(CacheRoles) {
+		return nil
 	}
 
-	g.Roles = append(g.Roles, *gr.Role)
-}
-
-// guildRoleUpdate updates a role in a guild.
-func (s *State) guildRoleUpdate(gr *GuildRole) {
-	g := s.guilds[gr.GuildID]
-	if g == nil {
-		return
+	g, err := s.store.Guild(gr.GuildID)
+	if s.storeError(err) || g == nil {
+		return nil
 	}
 
-	for i := 0; i < len(g.Roles); i++ {
-		if g.Roles[i].ID == gr.Role.ID {
-			g.Roles[i] = *gr.Role
-			break
-		}
+	old, err := s.store.Role(gr.GuildID, gr.Role.ID)
+	if s.storeError(err) {
+		return nil
 	}
+	s.storeError(s.store.SetRole(gr.GuildID, gr.Role))
+	return old
 }
 
 // guildRoleRemove removes a role from a guild.
@@ -592,17 +836,7 @@ func (s *State) guildRoleRemove(gr *GuildRoleDelete) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	g := s.guilds[gr.GuildID]
-	if g == nil {
-		return
-	}
-
-	for i := 0; i < len(g.Roles); i++ {
-		if g.Roles[i].ID == gr.RoleID {
-			g.Roles = append(g.Roles[:i], g.Roles[i+1:]...)
-			break
-		}
-	}
+	s.storeError(s.store.DeleteRole(gr.GuildID, gr.RoleID))
 }
 
 // setRTT sets the Round Trip Time. See the RTT method for more information
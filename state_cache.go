@@ -0,0 +1,224 @@
+package harmony
+
+import (
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// CacheFlag selects which kind of entities are cached by the State.
+// Guilds are always cached.
+type CacheFlag int
+
+// Valid cache flags.
+const (
+	CacheUsers CacheFlag = 1 << iota
+	CacheMembers
+	CachePresences
+	CacheVoiceStates
+	CacheEmojis
+	CacheChannels
+	CacheRoles
+	CacheStickers
+	CacheScheduledEvents
+	CacheStageInstances
+
+	CacheNone CacheFlag = 0
+	CacheAll            = CacheUsers | CacheMembers | CachePresences | CacheVoiceStates |
+		CacheEmojis | CacheChannels | CacheRoles | CacheStickers | CacheScheduledEvents |
+		CacheStageInstances
+)
+
+// Has returns whether f has the given flags set.
+func (f CacheFlag) Has(flags CacheFlag) bool {
+	return f&flags == flags
+}
+
+// MemberCachePolicy selects which guild members are cached by the State when
+// members are cached. Policies can be combined, in which case a member is cached
+// as soon as one of the policies applies.
+type MemberCachePolicy int
+
+// Valid member cache policies.
+const (
+	// Cache all members.
+	MemberCacheAll MemberCachePolicy = 1 << iota
+	// Cache the current user only.
+	MemberCacheSelf
+	// Cache members while they are connected to a voice channel.
+	// Voice states must be cached for this policy to apply.
+	MemberCacheVoice
+	// Cache members that recently sent a message. How long they are kept
+	// is configured with WithMemberCachePolicy.
+	MemberCacheActive
+)
+
+// Has returns whether p has the given policies set.
+func (p MemberCachePolicy) Has(policies MemberCachePolicy) bool {
+	return p&policies == policies
+}
+
+// defaultActiveMemberTTL is the default duration members are cached after
+// sending a message when using the MemberCacheActive policy.
+const defaultActiveMemberTTL = 10 * time.Minute
+
+// cachePolicy decides what the State caches.
+type cachePolicy struct {
+	flags   CacheFlag
+	members MemberCachePolicy
+	// How long members are kept after sending
+	// a message, for MemberCacheActive.
+	activeTTL time.Duration
+	// If not nil, only those guilds are cached.
+	guilds map[string]struct{}
+	// Approximate maximum number of bytes used
+	// by the State, 0 if there is no limit.
+	memoryBudget int64
+}
+
+// defaultCachePolicy caches everything.
+func defaultCachePolicy() cachePolicy {
+	return cachePolicy{
+		flags:     CacheAll,
+		members:   MemberCacheAll,
+		activeTTL: defaultActiveMemberTTL,
+	}
+}
+
+// cacheGuild reports whether entities of the given guild are cached.
+// Entities that do not belong to a guild have an empty guild ID and
+// are always cached.
+func (p *cachePolicy) cacheGuild(id string) bool {
+	if p.guilds == nil || id == "" {
+		return true
+	}
+	_, ok := p.guilds[id]
+	return ok
+}
+
+// memberActivity tracks when members last sent a message,
+// for the MemberCacheActive policy.
+type memberActivity struct {
+	// Last activity by guild ID and user ID.
+	last map[string]map[string]time.Time
+	// When expired members were last evicted.
+	lastSweep time.Time
+}
+
+// cacheMember reports whether the given member of the given guild should be cached
+// according to the cache policy. It must be called with the State lock held.
+func (s *State) cacheMember(guildID string, m *discord.GuildMember) bool {
+	p := &s.policy
+	if !p.flags.Has(CacheMembers) || !p.cacheGuild(guildID) || m.User == nil {
+		return false
+	}
+
+	if p.members.Has(MemberCacheAll) {
+		return true
+	}
+	if p.members.Has(MemberCacheSelf) && s.me != nil && m.User.ID == s.me.ID {
+		return true
+	}
+	if p.members.Has(MemberCacheVoice) && p.flags.Has(CacheVoiceStates) {
+		vs, err := s.store.VoiceState(guildID, m.User.ID)
+		if !s.storeError(err) && vs != nil && vs.ChannelID != nil {
+			return true
+		}
+	}
+	if p.members.Has(MemberCacheActive) {
+		if last, ok := s.activity.last[guildID][m.User.ID]; ok && time.Since(last) < p.activeTTL {
+			return true
+		}
+	}
+	return false
+}
+
+// setMember caches the given member if the cache policy allows it, removing it
+// from the cache otherwise. The user of a member that is not cached is only
+// stored if it is still a member of another cached guild, so users do not pile
+// up when most members are filtered out. It must be called with the State lock held.
+func (s *State) setMember(guildID string, m *discord.GuildMember) error {
+	cacheUser := s.policy.flags.Has(CacheUsers)
+
+	if s.cacheMember(guildID, m) {
+		if cacheUser {
+			if err := s.store.SetUser(m.User); err != nil {
+				return err
+			}
+		}
+		return s.store.SetMember(guildID, m)
+	}
+
+	if !s.policy.flags.Has(CacheMembers) {
+		// Members are not cached at all, users are on their own.
+		if cacheUser {
+			return s.store.SetUser(m.User)
+		}
+		return nil
+	}
+
+	if err := s.store.DeleteMember(guildID, m.User.ID); err != nil {
+		return err
+	}
+	if !cacheUser {
+		return nil
+	}
+	if len(s.index.userGuilds[m.User.ID]) > 0 {
+		return s.store.SetUser(m.User)
+	}
+	// The user may have been stored along with this member before
+	// it got evicted, and nothing references it anymore.
+	if err := s.store.DeleteUser(m.User.ID); err != nil {
+		return err
+	}
+	return s.store.DeletePresence(m.User.ID)
+}
+
+// memberActive records that a member sent a message, caching it if needed
+// and evicting members that have not been active for too long.
+// It must be called with the State lock held.
+func (s *State) memberActive(msg *discord.Message) {
+	if !s.policy.members.Has(MemberCacheActive) || msg.GuildID == "" || msg.WebhookID != "" {
+		return
+	}
+	// Some guild messages, such as ephemeral or interaction ones, come without
+	// their member, caching it would replace the cached member with an empty one.
+	if msg.Author.ID == "" || msg.Member.JoinedAt.IsZero() {
+		return
+	}
+
+	now := time.Now()
+	if s.activity.last[msg.GuildID] == nil {
+		s.activity.last[msg.GuildID] = make(map[string]time.Time)
+	}
+	s.activity.last[msg.GuildID][msg.Author.ID] = now
+
+	m := msg.Member.Clone()
+	m.User = msg.Author.Clone()
+	s.storeError(s.setMember(msg.GuildID, m))
+
+	if now.Sub(s.activity.lastSweep) < s.policy.activeTTL/2 {
+		return
+	}
+	s.activity.lastSweep = now
+
+	for guildID, users := range s.activity.last {
+		for userID, last := range users {
+			if now.Sub(last) < s.policy.activeTTL {
+				continue
+			}
+			delete(users, userID)
+
+			member, err := s.store.Member(guildID, userID)
+			if s.storeError(err) || member == nil {
+				continue
+			}
+			if !s.cacheMember(guildID, member) {
+				s.storeError(s.store.DeleteMember(guildID, userID))
+			}
+		}
+		if len(users) == 0 {
+			delete(s.activity.last, guildID)
+		}
+	}
+}
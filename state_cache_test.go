@@ -0,0 +1,103 @@
+package harmony
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+func TestSetMemberUsers(t *testing.T) {
+	policy := defaultCachePolicy()
+	policy.members = MemberCacheSelf
+	s := newState(newMemoryStore(), policy, testLogger)
+	s.me = &discord.User{ID: "me"}
+
+	member := func(id string) *discord.GuildMember {
+		return &discord.GuildMember{User: &discord.User{ID: id, Username: id}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(s.setMember("g1", member("me")))
+	must(s.setMember("g1", member("u1")))
+
+	if u, _ := s.store.User("me"); u == nil {
+		t.Error("expected user of a cached member to be stored")
+	}
+	if u, _ := s.store.User("u1"); u != nil {
+		t.Error("expected user of a member that is not cached not to be stored")
+	}
+
+	// Users still referenced by another cached member are kept up to date.
+	must(s.setMember("g2", member("me")))
+	if u, _ := s.store.User("me"); u == nil {
+		t.Error("expected user of a member cached in another guild to be stored")
+	}
+
+	// Users of evicted members are removed once nothing references them.
+	s.policy.members = MemberCacheAll
+	must(s.setMember("g1", member("u1")))
+	if u, _ := s.store.User("u1"); u == nil {
+		t.Fatal("expected user of a cached member to be stored")
+	}
+	s.policy.members = MemberCacheSelf
+	must(s.setMember("g1", member("u1")))
+	if u, _ := s.store.User("u1"); u != nil {
+		t.Error("expected user of an evicted member to be removed")
+	}
+}
+
+func TestMemberActive(t *testing.T) {
+	policy := defaultCachePolicy()
+	policy.members = MemberCacheActive
+	s := newState(newMemoryStore(), policy, testLogger)
+
+	joinedAt := discord.Time{Time: time.Now()}
+	tests := []struct {
+		name   string
+		msg    *discord.Message
+		cached bool
+	}{
+		{
+			name:   "member",
+			msg:    &discord.Message{GuildID: "g1", Author: discord.User{ID: "u1"}, Member: discord.GuildMember{JoinedAt: joinedAt}},
+			cached: true,
+		},
+		{
+			name: "no member",
+			msg:  &discord.Message{GuildID: "g1", Author: discord.User{ID: "u2"}},
+		},
+		{
+			name: "webhook",
+			msg:  &discord.Message{GuildID: "g1", WebhookID: "w1", Author: discord.User{ID: "u3"}, Member: discord.GuildMember{JoinedAt: joinedAt}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.addMessage(tt.msg)
+
+			m, err := s.store.Member("g1", tt.msg.Author.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached := m != nil; cached != tt.cached {
+				t.Errorf("expected member to be cached: %t; got %t", tt.cached, cached)
+			}
+		})
+	}
+
+	// A message without its member does not replace the cached member.
+	s.addMessage(&discord.Message{GuildID: "g1", Author: discord.User{ID: "u1"}})
+	if m, _ := s.store.Member("g1", "u1"); m == nil || m.JoinedAt.IsZero() {
+		t.Errorf("expected cached member to be kept; got %+v", m)
+	}
+}
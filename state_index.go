@@ -0,0 +1,417 @@
+package harmony
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// stateIndex holds secondary indexes of the State, so common lookups
+// do not need to scan and clone every entity of a guild.
+// Names are indexed in lower case.
+type stateIndex struct {
+	// Channel IDs by guild ID and name.
+	channelNames map[string]map[string]map[string]struct{}
+	// Role IDs by guild ID and name.
+	roleNames map[string]map[string]map[string]struct{}
+	// Member names by guild ID and user ID.
+	memberNames map[string]map[string]*memberNames
+	// Member usernames and nicknames by guild ID, sorted for prefix lookups.
+	sortedMemberNames map[string]*sortedMemberNames
+	// sortMu guards sorting member names, which happens lazily
+	// during lookups, with only the State read lock held.
+	sortMu sync.Mutex
+	// Guild IDs of members by user ID, to keep usernames up to date.
+	userGuilds map[string]map[string]struct{}
+	// Member IDs by guild ID and role ID.
+	roleMembers map[string]map[string]map[string]struct{}
+}
+
+// memberNames are the names a member can be looked up by.
+type memberNames struct {
+	username string
+	nick     string
+}
+
+// memberName is a username or nickname of a member.
+type memberName struct {
+	name   string
+	userID string
+}
+
+// less orders member names by name, then by user ID.
+func (n memberName) less(o memberName) bool {
+	if n.name != o.name {
+		return n.name < o.name
+	}
+	return n.userID < o.userID
+}
+
+// sortedMemberNames are the member names of a guild, sorted lazily so
+// adding names stays cheap when a lot of members are received at once.
+type sortedMemberNames struct {
+	// Names that are currently indexed.
+	names map[memberName]struct{}
+	// Names as of the last sort. Names that were removed since are skipped.
+	sorted []memberName
+	// Names added since the last sort.
+	added []memberName
+	// Whether sorted must be rebuilt from names, because
+	// too many names were added since the last sort.
+	rebuild bool
+}
+
+// add adds a name, unless it is already there.
+func (s *sortedMemberNames) add(n memberName) {
+	if _, ok := s.names[n]; ok {
+		return
+	}
+	s.names[n] = struct{}{}
+
+	if s.rebuild {
+		return
+	}
+	// Names are re-added each time a member is updated, do not keep track
+	// of more of them than there are names and rebuild everything instead.
+	if len(s.added) >= len(s.names) {
+		s.sorted, s.added, s.rebuild = nil, nil, true
+		return
+	}
+	s.added = append(s.added, n)
+}
+
+// remove removes a name. It is only removed from the sorted names on the next sort.
+func (s *sortedMemberNames) remove(n memberName) {
+	delete(s.names, n)
+}
+
+// sort sorts names added since the last sort, merging them with the previously
+// sorted ones and dropping names that were removed in the meantime.
+func (s *sortedMemberNames) sort() {
+	if s.rebuild {
+		s.sorted = make([]memberName, 0, len(s.names))
+		for n := range s.names {
+			s.sorted = append(s.sorted, n)
+		}
+		sort.Slice(s.sorted, func(i, j int) bool { return s.sorted[i].less(s.sorted[j]) })
+		s.rebuild = false
+		return
+	}
+	if len(s.added) == 0 {
+		return
+	}
+
+	sort.Slice(s.added, func(i, j int) bool { return s.added[i].less(s.added[j]) })
+	merged := make([]memberName, 0, len(s.names))
+	i, j := 0, 0
+	for i < len(s.sorted) || j < len(s.added) {
+		var n memberName
+		if j == len(s.added) || (i < len(s.sorted) && s.sorted[i].less(s.added[j])) {
+			n = s.sorted[i]
+			i++
+		} else {
+			n = s.added[j]
+			j++
+		}
+		// A name that was removed and added back can be in both.
+		if _, ok := s.names[n]; !ok || (len(merged) > 0 && merged[len(merged)-1] == n) {
+			continue
+		}
+		merged = append(merged, n)
+	}
+	s.sorted, s.added = merged, nil
+}
+
+func newStateIndex() *stateIndex {
+	return &stateIndex{
+		channelNames:      make(map[string]map[string]map[string]struct{}),
+		roleNames:         make(map[string]map[string]map[string]struct{}),
+		memberNames:       make(map[string]map[string]*memberNames),
+		sortedMemberNames: make(map[string]*sortedMemberNames),
+		userGuilds:        make(map[string]map[string]struct{}),
+		roleMembers:       make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+// addKey adds id to m[guildID][key].
+func addKey(m map[string]map[string]map[string]struct{}, guildID, key, id string) {
+	if m[guildID] == nil {
+		m[guildID] = make(map[string]map[string]struct{})
+	}
+	if m[guildID][key] == nil {
+		m[guildID][key] = make(map[string]struct{})
+	}
+	m[guildID][key][id] = struct{}{}
+}
+
+// removeKey removes id from m[guildID][key].
+func removeKey(m map[string]map[string]map[string]struct{}, guildID, key, id string) {
+	delete(m[guildID][key], id)
+	if len(m[guildID][key]) == 0 {
+		delete(m[guildID], key)
+	}
+	if len(m[guildID]) == 0 {
+		delete(m, guildID)
+	}
+}
+
+// setChannel indexes a channel, old being its previous version if any.
+func (idx *stateIndex) setChannel(old, ch *discord.Channel) {
+	if old != nil {
+		idx.deleteChannel(old)
+	}
+	if ch.GuildID != "" {
+		addKey(idx.channelNames, ch.GuildID, strings.ToLower(ch.Name), ch.ID)
+	}
+}
+
+// deleteChannel removes a channel from the index.
+func (idx *stateIndex) deleteChannel(ch *discord.Channel) {
+	if ch.GuildID != "" {
+		removeKey(idx.channelNames, ch.GuildID, strings.ToLower(ch.Name), ch.ID)
+	}
+}
+
+// setRole indexes a role of a guild, old being its previous version if any.
+func (idx *stateIndex) setRole(guildID string, old, r *discord.Role) {
+	if old != nil {
+		idx.deleteRole(guildID, old)
+	}
+	addKey(idx.roleNames, guildID, strings.ToLower(r.Name), r.ID)
+}
+
+// deleteRole removes a role of a guild from the index.
+func (idx *stateIndex) deleteRole(guildID string, r *discord.Role) {
+	removeKey(idx.roleNames, guildID, strings.ToLower(r.Name), r.ID)
+}
+
+// setMember indexes a member of a guild, old being its previous version if any.
+func (idx *stateIndex) setMember(guildID string, old, m *discord.GuildMember) {
+	if old != nil {
+		idx.deleteMember(guildID, old)
+	}
+
+	if idx.memberNames[guildID] == nil {
+		idx.memberNames[guildID] = make(map[string]*memberNames)
+	}
+	names := &memberNames{
+		username: strings.ToLower(m.User.Username),
+		nick:     strings.ToLower(m.Nick),
+	}
+	idx.memberNames[guildID][m.User.ID] = names
+	idx.addMemberName(guildID, names.username, m.User.ID)
+	idx.addMemberName(guildID, names.nick, m.User.ID)
+
+	if idx.userGuilds[m.User.ID] == nil {
+		idx.userGuilds[m.User.ID] = make(map[string]struct{})
+	}
+	idx.userGuilds[m.User.ID][guildID] = struct{}{}
+
+	for _, roleID := range m.Roles {
+		addKey(idx.roleMembers, guildID, roleID, m.User.ID)
+	}
+}
+
+// deleteMember removes a member of a guild from the index.
+func (idx *stateIndex) deleteMember(guildID string, m *discord.GuildMember) {
+	if names := idx.memberNames[guildID][m.User.ID]; names != nil {
+		idx.removeMemberName(guildID, names.username, m.User.ID)
+		idx.removeMemberName(guildID, names.nick, m.User.ID)
+	}
+	delete(idx.memberNames[guildID], m.User.ID)
+	if len(idx.memberNames[guildID]) == 0 {
+		delete(idx.memberNames, guildID)
+	}
+
+	delete(idx.userGuilds[m.User.ID], guildID)
+	if len(idx.userGuilds[m.User.ID]) == 0 {
+		delete(idx.userGuilds, m.User.ID)
+	}
+
+	for _, roleID := range m.Roles {
+		removeKey(idx.roleMembers, guildID, roleID, m.User.ID)
+	}
+}
+
+// setUser updates the username of a user in every guild it is a member of.
+// Members received in events do not always hold the latest version of their user.
+func (idx *stateIndex) setUser(u *discord.User) {
+	if u.Username == "" {
+		return
+	}
+
+	username := strings.ToLower(u.Username)
+	for guildID := range idx.userGuilds[u.ID] {
+		if names := idx.memberNames[guildID][u.ID]; names != nil && names.username != username {
+			idx.removeMemberName(guildID, names.username, u.ID)
+			names.username = username
+			idx.addMemberName(guildID, names.username, u.ID)
+		}
+	}
+}
+
+// addMemberName adds a name of a member to the sorted member names of a guild.
+// Empty names are ignored.
+func (idx *stateIndex) addMemberName(guildID, name, userID string) {
+	if name == "" {
+		return
+	}
+
+	names := idx.sortedMemberNames[guildID]
+	if names == nil {
+		names = &sortedMemberNames{names: make(map[memberName]struct{})}
+		idx.sortedMemberNames[guildID] = names
+	}
+	names.add(memberName{name: name, userID: userID})
+}
+
+// removeMemberName removes a name of a member from the sorted member names of a guild.
+func (idx *stateIndex) removeMemberName(guildID, name, userID string) {
+	if name == "" {
+		return
+	}
+
+	names := idx.sortedMemberNames[guildID]
+	if names == nil {
+		return
+	}
+	names.remove(memberName{name: name, userID: userID})
+	if len(names.names) == 0 {
+		delete(idx.sortedMemberNames, guildID)
+	}
+}
+
+// deleteGuild removes all channels, roles and members of a guild from the index.
+func (idx *stateIndex) deleteGuild(guildID string) {
+	for userID := range idx.memberNames[guildID] {
+		delete(idx.userGuilds[userID], guildID)
+		if len(idx.userGuilds[userID]) == 0 {
+			delete(idx.userGuilds, userID)
+		}
+	}
+
+	delete(idx.channelNames, guildID)
+	delete(idx.roleNames, guildID)
+	delete(idx.memberNames, guildID)
+	delete(idx.sortedMemberNames, guildID)
+	delete(idx.roleMembers, guildID)
+}
+
+// GuildChannelsByName returns channels of the given guild with the given name
+// from the state. Names are compared case-insensitively.
+func (s *State) GuildChannelsByName(guildID, name string) []discord.Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var channels []discord.Channel
+	for id := range s.index.channelNames[guildID][strings.ToLower(name)] {
+		ch, err := s.store.Channel(id)
+		if s.storeError(err) {
+			return nil
+		}
+		if ch != nil {
+			channels = append(channels, *ch)
+		}
+	}
+	return channels
+}
+
+// GuildRolesByName returns roles of the given guild with the given name
+// from the state. Names are compared case-insensitively.
+func (s *State) GuildRolesByName(guildID, name string) []discord.Role {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var roles []discord.Role
+	for id := range s.index.roleNames[guildID][strings.ToLower(name)] {
+		r, err := s.store.Role(guildID, id)
+		if s.storeError(err) {
+			return nil
+		}
+		if r != nil {
+			roles = append(roles, *r)
+		}
+	}
+	return roles
+}
+
+// GuildMembersByPrefix returns members of the given guild whose username or
+// nickname starts with the given prefix from the state. Names are compared
+// case-insensitively. Members are sorted by the first of their names that
+// matches, then by user ID. If limit is greater than 0, at most limit members
+// are returned.
+func (s *State) GuildMembersByPrefix(guildID, prefix string, limit int) []discord.GuildMember {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	sorted := s.index.sortedMemberNames[guildID]
+	if sorted == nil {
+		return nil
+	}
+
+	s.index.sortMu.Lock()
+	defer s.index.sortMu.Unlock()
+	sorted.sort()
+	names := sorted.sorted
+
+	var (
+		ids  []string
+		seen = make(map[string]bool)
+	)
+	i := sort.Search(len(names), func(i int) bool { return names[i].name >= prefix })
+	for ; i < len(names) && strings.HasPrefix(names[i].name, prefix); i++ {
+		if limit > 0 && len(ids) >= limit {
+			break
+		}
+		// Names removed since the last sort are still there.
+		if _, ok := sorted.names[names[i]]; !ok {
+			continue
+		}
+		// Both the username and the nickname of a member may match.
+		if id := names[i].userID; !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return s.guildMembers(guildID, ids)
+}
+
+// RoleMembers returns members of the given guild that have the given role from the state.
+func (s *State) RoleMembers(guildID, roleID string) []discord.GuildMember {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []string
+	for id := range s.index.roleMembers[guildID][roleID] {
+		ids = append(ids, id)
+	}
+	return s.guildMembers(guildID, ids)
+}
+
+// guildMembers returns members of the given guild with the given user IDs, with their
+// latest version of their user. It must be called with the State lock held.
+func (s *State) guildMembers(guildID string, userIDs []string) []discord.GuildMember {
+	var members []discord.GuildMember
+	for _, id := range userIDs {
+		m, err := s.store.Member(guildID, id)
+		if s.storeError(err) {
+			return nil
+		}
+		if m == nil {
+			continue
+		}
+
+		u, err := s.store.User(id)
+		if s.storeError(err) {
+			return nil
+		}
+		if u != nil {
+			m.User = u
+		}
+		members = append(members, *m)
+	}
+	return members
+}
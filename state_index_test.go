@@ -0,0 +1,154 @@
+package harmony
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/skwair/harmony/discord"
+)
+
+func TestGuildMembersByPrefix(t *testing.T) {
+	s := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+
+	members := []discord.GuildMember{
+		{User: &discord.User{ID: "1", Username: "Alice"}},
+		{User: &discord.User{ID: "2", Username: "bob"}, Nick: "Alfred"},
+		{User: &discord.User{ID: "3", Username: "alan"}, Nick: "Al"},
+		{User: &discord.User{ID: "4", Username: "carol"}},
+		{User: &discord.User{ID: "5", Username: "alice"}},
+	}
+	for i := range members {
+		if err := s.store.SetUser(members[i].User); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.store.SetMember("g1", &members[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Members of other guilds are not returned.
+	if err := s.store.SetMember("g2", &discord.GuildMember{User: &discord.User{ID: "6", Username: "alex"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		guildID  string
+		prefix   string
+		limit    int
+		expected []string
+	}{
+		{name: "all", guildID: "g1", prefix: "", expected: []string{"3", "2", "1", "5", "4"}},
+		{name: "case insensitive", guildID: "g1", prefix: "ALI", expected: []string{"1", "5"}},
+		{name: "nickname", guildID: "g1", prefix: "alf", expected: []string{"2"}},
+		{name: "username and nickname", guildID: "g1", prefix: "al", expected: []string{"3", "2", "1", "5"}},
+		{name: "limit", guildID: "g1", prefix: "al", limit: 2, expected: []string{"3", "2"}},
+		{name: "no match", guildID: "g1", prefix: "dave"},
+		{name: "other guild", guildID: "g2", prefix: "al", expected: []string{"6"}},
+		{name: "unknown guild", guildID: "g3", prefix: "al"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Results must not depend on map iteration order.
+			for i := 0; i < 10; i++ {
+				got := memberIDs(s.GuildMembersByPrefix(tt.guildID, tt.prefix, tt.limit))
+				if !reflect.DeepEqual(got, tt.expected) {
+					t.Fatalf("expected members %v; got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGuildMembersByPrefixUpdates(t *testing.T) {
+	s := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+
+	m := &discord.GuildMember{User: &discord.User{ID: "1", Username: "alice"}, Nick: "ally"}
+	if err := s.store.SetMember("g1", m); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new nickname replaces the old one.
+	m = &discord.GuildMember{User: &discord.User{ID: "1", Username: "alice"}, Nick: "bee"}
+	if err := s.store.SetMember("g1", m); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberIDs(s.GuildMembersByPrefix("g1", "ally", 0)); got != nil {
+		t.Errorf("expected old nickname not to match; got %v", got)
+	}
+	if got := memberIDs(s.GuildMembersByPrefix("g1", "bee", 0)); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("expected new nickname to match; got %v", got)
+	}
+
+	// Users can be renamed independently of their members.
+	if err := s.store.SetUser(&discord.User{ID: "1", Username: "carol"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberIDs(s.GuildMembersByPrefix("g1", "alice", 0)); got != nil {
+		t.Errorf("expected old username not to match; got %v", got)
+	}
+	if got := memberIDs(s.GuildMembersByPrefix("g1", "carol", 0)); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("expected new username to match; got %v", got)
+	}
+
+	if err := s.store.DeleteMember("g1", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.index.sortedMemberNames) != 0 {
+		t.Errorf("expected no member names left; got %v", s.index.sortedMemberNames)
+	}
+}
+
+func TestSortedMemberNames(t *testing.T) {
+	tests := []struct {
+		name string
+		// Operations to apply, "+" adding and "-" removing a name,
+		// "?" checking names are sorted as expected.
+		ops      []string
+		expected []string
+	}{
+		{name: "sorted on lookup", ops: []string{"+c", "+a", "+b"}, expected: []string{"a", "b", "c"}},
+		{name: "merged with previous lookup", ops: []string{"+c", "+a", "?", "+b", "+d"}, expected: []string{"a", "b", "c", "d"}},
+		{name: "removed after lookup", ops: []string{"+a", "+b", "?", "-a"}, expected: []string{"b"}},
+		{name: "removed before lookup", ops: []string{"+a", "+b", "-a"}, expected: []string{"b"}},
+		{name: "added back", ops: []string{"+a", "+b", "?", "-a", "+a"}, expected: []string{"a", "b"}},
+		{name: "rebuilt", ops: []string{"+a", "+b", "-a", "+a", "-a", "+a", "-b", "+b", "+c"}, expected: []string{"a", "b", "c"}},
+		{name: "rebuilt after lookup", ops: []string{"+a", "+b", "?", "-a", "+a", "-b", "+b", "-a", "+a", "-c"}, expected: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := &sortedMemberNames{names: make(map[memberName]struct{})}
+			for _, op := range tt.ops {
+				n := memberName{name: op[1:], userID: "1"}
+				switch op[0] {
+				case '+':
+					names.add(n)
+				case '-':
+					names.remove(n)
+				case '?':
+					names.sort()
+				}
+			}
+			names.sort()
+
+			var got []string
+			for _, n := range names.sorted {
+				if _, ok := names.names[n]; ok {
+					got = append(got, n.name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected names %v; got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func memberIDs(members []discord.GuildMember) []string {
+	var ids []string
+	for _, m := range members {
+		ids = append(ids, m.User.ID)
+	}
+	return ids
+}
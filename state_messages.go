@@ -0,0 +1,280 @@
+package harmony
+
+import (
+	"container/list"
+	"encoding/json"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// cachedMessage is a message stored in the message cache.
+type cachedMessage struct {
+	msg   *discord.Message
+	added time.Time
+
+	// Elements of this message in the global and per-channel lists,
+	// so it can be removed from both in constant time.
+	global  *list.Element
+	channel *list.Element
+}
+
+// messageCache is a bounded cache of messages. Each channel has its own buffer of
+// at most perChannel messages and the cache holds at most max messages overall.
+// When a buffer is full, the oldest message is evicted first. Messages older
+// than ttl are evicted as well.
+type messageCache struct {
+	perChannel int
+	max        int
+	ttl        time.Duration
+
+	byID     map[string]*cachedMessage
+	channels map[string]*list.List
+	// all holds every cached message, from the oldest to the newest.
+	all *list.List
+
+	// Number of messages evicted because the cache was full or they expired.
+	evictions int64
+}
+
+// newMessageCache returns a new message cache. A max or ttl of 0 or less means
+// there is no global limit on the number of messages or no time limit.
+func newMessageCache(perChannel, max int, ttl time.Duration) *messageCache {
+	return &messageCache{
+		perChannel: perChannel,
+		max:        max,
+		ttl:        ttl,
+		byID:       make(map[string]*cachedMessage),
+		channels:   make(map[string]*list.List),
+		all:        list.New(),
+	}
+}
+
+// get returns the cached message with the given ID, or nil if there is none.
+func (mc *messageCache) get(id string) *discord.Message {
+	cm := mc.byID[id]
+	if cm == nil || mc.expired(cm) {
+		return nil
+	}
+	return cm.msg
+}
+
+// channel returns cached messages of the given channel, from the oldest to the newest.
+func (mc *messageCache) channel(id string) []*discord.Message {
+	l := mc.channels[id]
+	if l == nil {
+		return nil
+	}
+
+	var msgs []*discord.Message
+	for e := l.Front(); e != nil; e = e.Next() {
+		cm := e.Value.(*cachedMessage)
+		if !mc.expired(cm) {
+			msgs = append(msgs, cm.msg)
+		}
+	}
+	return msgs
+}
+
+// add adds a message to the cache, evicting older messages if needed.
+func (mc *messageCache) add(m *discord.Message) {
+	if cm := mc.byID[m.ID]; cm != nil {
+		cm.msg = m
+		return
+	}
+
+	mc.evictExpired()
+
+	l := mc.channels[m.ChannelID]
+	if l == nil {
+		l = list.New()
+		mc.channels[m.ChannelID] = l
+	}
+
+	cm := &cachedMessage{msg: m, added: time.Now()}
+	cm.global = mc.all.PushBack(cm)
+	cm.channel = l.PushBack(cm)
+	mc.byID[m.ID] = cm
+
+	if l.Len() > mc.perChannel {
+		mc.remove(l.Front().Value.(*cachedMessage))
+		mc.evictions++
+	}
+	if mc.max > 0 && mc.all.Len() > mc.max {
+		mc.remove(mc.all.Front().Value.(*cachedMessage))
+		mc.evictions++
+	}
+}
+
+// update replaces a message in the cache if it is present.
+func (mc *messageCache) update(m *discord.Message) {
+	if cm := mc.byID[m.ID]; cm != nil {
+		cm.msg = m
+	}
+}
+
+// delete removes a message from the cache and returns it,
+// or nil if it was not cached.
+func (mc *messageCache) delete(id string) *discord.Message {
+	cm := mc.byID[id]
+	if cm == nil {
+		return nil
+	}
+
+	mc.remove(cm)
+	if mc.expired(cm) {
+		return nil
+	}
+	return cm.msg
+}
+
+// deleteChannel removes all messages of a channel from the cache.
+func (mc *messageCache) deleteChannel(id string) {
+	l := mc.channels[id]
+	if l == nil {
+		return
+	}
+
+	for l.Len() > 0 {
+		mc.remove(l.Front().Value.(*cachedMessage))
+	}
+}
+
+// remove removes the given cached message from the cache.
+func (mc *messageCache) remove(cm *cachedMessage) {
+	mc.all.Remove(cm.global)
+
+	l := mc.channels[cm.msg.ChannelID]
+	l.Remove(cm.channel)
+	if l.Len() == 0 {
+		delete(mc.channels, cm.msg.ChannelID)
+	}
+
+	delete(mc.byID, cm.msg.ID)
+}
+
+// evictExpired removes all messages that expired from the cache.
+func (mc *messageCache) evictExpired() {
+	if mc.ttl <= 0 {
+		return
+	}
+
+	for e := mc.all.Front(); e != nil; e = mc.all.Front() {
+		cm := e.Value.(*cachedMessage)
+		if !mc.expired(cm) {
+			return
+		}
+		mc.remove(cm)
+		mc.evictions++
+	}
+}
+
+// expired reports whether the given cached message expired.
+func (mc *messageCache) expired(cm *cachedMessage) bool {
+	return mc.ttl > 0 && time.Since(cm.added) > mc.ttl
+}
+
+// Message returns a message given its ID from the state's message cache.
+// It returns nil if the message is not cached or if the message cache is not
+// enabled. See WithMessageCache for more information.
+func (s *State) Message(id string) *discord.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.messages == nil {
+		return nil
+	}
+
+	return s.messages.get(id).Clone()
+}
+
+// ChannelMessages returns cached messages of the given channel, from the oldest
+// to the newest. See WithMessageCache for more information.
+func (s *State) ChannelMessages(channelID string) []discord.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.messages == nil {
+		return nil
+	}
+
+	var msgs []discord.Message
+	for _, m := range s.messages.channel(channelID) {
+		msgs = append(msgs, *m.Clone())
+	}
+	return msgs
+}
+
+// addMessage adds a newly created message to the message cache.
+// Its author is also cached if the MemberCacheActive policy is used.
+func (s *State) addMessage(m *discord.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memberActive(m)
+
+	if s.messages == nil {
+		return
+	}
+
+	s.messages.add(m.Clone())
+}
+
+// updateMessage applies a message update to the cached message, if any. Since
+// message updates can be partial, the raw update is applied on top of the cached
+// message. It returns the message as it was before the update, or nil if it was
+// not cached.
+func (s *State) updateMessage(m *discord.Message, data json.RawMessage) *discord.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.messages == nil {
+		return nil
+	}
+
+	old := s.messages.get(m.ID)
+	if old == nil {
+		return nil
+	}
+
+	updated := old.Clone()
+	if err := json.Unmarshal(data, updated); err != nil {
+		// Should not happen since this payload was already decoded once.
+		updated = m.Clone()
+	}
+	s.messages.update(updated)
+
+	return old.Clone()
+}
+
+// removeMessage removes a message from the message cache, returning it
+// or nil if it was not cached.
+func (s *State) removeMessage(id string) *discord.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.messages == nil {
+		return nil
+	}
+
+	return s.messages.delete(id)
+}
+
+// removeMessages removes multiple messages from the message cache,
+// returning those that were cached.
+func (s *State) removeMessages(ids []string) []discord.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.messages == nil {
+		return nil
+	}
+
+	var msgs []discord.Message
+	for _, id := range ids {
+		if m := s.messages.delete(id); m != nil {
+			msgs = append(msgs, *m)
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,141 @@
+package harmony
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+func TestMessageCacheEviction(t *testing.T) {
+	tests := []struct {
+		name       string
+		perChannel int
+		max        int
+		// Messages to add, as channel IDs. The message ID is its index.
+		add []string
+		// Expected message IDs in each channel after all messages are added.
+		expected  map[string][]string
+		evictions int64
+	}{
+		{
+			name:       "per channel limit",
+			perChannel: 2,
+			add:        []string{"c1", "c1", "c1", "c2"},
+			expected:   map[string][]string{"c1": {"1", "2"}, "c2": {"3"}},
+			evictions:  1,
+		},
+		{
+			name:       "global limit",
+			perChannel: 10,
+			max:        3,
+			add:        []string{"c1", "c2", "c1", "c2", "c3"},
+			expected:   map[string][]string{"c1": {"2"}, "c2": {"3"}, "c3": {"4"}},
+			evictions:  2,
+		},
+		{
+			name:       "both limits",
+			perChannel: 2,
+			max:        3,
+			add:        []string{"c1", "c1", "c1", "c2", "c2"},
+			expected:   map[string][]string{"c1": {"2"}, "c2": {"3", "4"}},
+			evictions:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newMessageCache(tt.perChannel, tt.max, 0)
+			for i, ch := range tt.add {
+				mc.add(&discord.Message{ID: strconv.Itoa(i), ChannelID: ch})
+			}
+
+			for ch, ids := range tt.expected {
+				if got := messageIDs(mc.channel(ch)); !reflect.DeepEqual(got, ids) {
+					t.Errorf("channel %q: expected messages %v; got %v", ch, ids, got)
+				}
+			}
+			if len(mc.byID) != mc.all.Len() {
+				t.Errorf("cache holds %d messages by ID but %d overall", len(mc.byID), mc.all.Len())
+			}
+			if mc.evictions != tt.evictions {
+				t.Errorf("expected %d evictions; got %d", tt.evictions, mc.evictions)
+			}
+		})
+	}
+}
+
+func TestMessageCacheTTL(t *testing.T) {
+	mc := newMessageCache(10, 0, time.Minute)
+	mc.add(&discord.Message{ID: "1", ChannelID: "c1"})
+	mc.add(&discord.Message{ID: "2", ChannelID: "c1"})
+
+	// Make the first message expire.
+	mc.byID["1"].added = time.Now().Add(-2 * time.Minute)
+
+	if m := mc.get("1"); m != nil {
+		t.Error("expected expired message not to be returned")
+	}
+	if m := mc.get("2"); m == nil {
+		t.Error("expected message 2 to be returned")
+	}
+	if got := messageIDs(mc.channel("c1")); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Errorf("expected messages [2]; got %v", got)
+	}
+	if m := mc.delete("1"); m != nil {
+		t.Error("expected deleting an expired message to return nil")
+	}
+
+	// Expired messages are evicted when new messages are added.
+	mc.byID["2"].added = time.Now().Add(-2 * time.Minute)
+	mc.add(&discord.Message{ID: "3", ChannelID: "c2"})
+
+	if mc.all.Len() != 1 {
+		t.Errorf("expected 1 message in the cache; got %d", mc.all.Len())
+	}
+	if _, ok := mc.channels["c1"]; ok {
+		t.Error("expected empty channel to be removed")
+	}
+	if mc.evictions != 1 {
+		t.Errorf("expected 1 eviction; got %d", mc.evictions)
+	}
+}
+
+func TestMessageCacheUpdateAndDelete(t *testing.T) {
+	mc := newMessageCache(10, 0, 0)
+	mc.add(&discord.Message{ID: "1", ChannelID: "c1", Content: "foo"})
+	mc.add(&discord.Message{ID: "2", ChannelID: "c1"})
+
+	mc.update(&discord.Message{ID: "1", ChannelID: "c1", Content: "bar"})
+	if m := mc.get("1"); m == nil || m.Content != "bar" {
+		t.Errorf("expected updated message; got %+v", m)
+	}
+
+	// Updates of messages that are not cached are ignored.
+	mc.update(&discord.Message{ID: "3", ChannelID: "c1"})
+	if m := mc.get("3"); m != nil {
+		t.Error("expected update not to add message 3")
+	}
+
+	if m := mc.delete("1"); m == nil || m.Content != "bar" {
+		t.Errorf("expected deleted message to be returned; got %+v", m)
+	}
+	if m := mc.delete("1"); m != nil {
+		t.Error("expected second delete to return nil")
+	}
+
+	mc.deleteChannel("c1")
+	if mc.all.Len() != 0 || len(mc.byID) != 0 || len(mc.channels) != 0 {
+		t.Error("expected cache to be empty after deleting the channel")
+	}
+}
+
+func messageIDs(msgs []*discord.Message) []string {
+	var ids []string
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
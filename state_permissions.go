@@ -0,0 +1,131 @@
+package harmony
+
+import (
+	"fmt"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// Permissions returns the permissions of a guild member in a channel of this guild,
+// computed from roles and permission overwrites cached in the state. If channelID
+// is empty, it returns the guild-wide permissions of this member.
+// It returns an error wrapping discord.ErrNotInState if the guild, its roles, the
+// channel or the member are not cached. See WithStateCache and WithMemberCachePolicy.
+func (s *State) Permissions(guildID, channelID, userID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, ch, m, err := s.permissionsObjects(guildID, channelID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if ch == nil {
+		return m.Permissions(g), nil
+	}
+	return m.PermissionsIn(g, ch), nil
+}
+
+// MyPermissions returns the permissions of the current user in the given guild channel,
+// computed from roles and permission overwrites cached in the state.
+// See Permissions for more information.
+func (s *State) MyPermissions(channelID string) (int64, error) {
+	guildID, userID, err := s.myPermissionsIDs(channelID)
+	if err != nil {
+		return 0, err
+	}
+	return s.Permissions(guildID, channelID, userID)
+}
+
+// ExplainPermissions is like Permissions but also explains which role or permission
+// overwrite granted or denied each permission. It can be used to tell users why a
+// command can not be used, for instance:
+//
+//	e, err := client.State.ExplainPermissions(guildID, channelID, userID)
+//	if err != nil {
+//		// Handle error.
+//	}
+//	for _, d := range e.Missing(discord.PermissionSendMessages | discord.PermissionEmbedLinks) {
+//		fmt.Println(d) // Send Messages: denied by role overwrite 123
+//	}
+func (s *State) ExplainPermissions(guildID, channelID, userID string) (*discord.PermissionsExplanation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, ch, m, err := s.permissionsObjects(guildID, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return m.ExplainPermissionsIn(g, ch), nil
+}
+
+// ExplainMyPermissions is like MyPermissions but also explains which role or
+// permission overwrite granted or denied each permission.
+// See ExplainPermissions for more information.
+func (s *State) ExplainMyPermissions(channelID string) (*discord.PermissionsExplanation, error) {
+	guildID, userID, err := s.myPermissionsIDs(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExplainPermissions(guildID, channelID, userID)
+}
+
+// myPermissionsIDs returns the guild ID of the given channel and the current user ID.
+func (s *State) myPermissionsIDs(channelID string) (guildID, userID string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.me == nil {
+		return "", "", fmt.Errorf("current user: %w", discord.ErrNotInState)
+	}
+
+	ch, err := s.store.Channel(channelID)
+	if err != nil {
+		return "", "", err
+	}
+	if ch == nil || ch.GuildID == "" {
+		return "", "", fmt.Errorf("guild channel %q: %w", channelID, discord.ErrNotInState)
+	}
+
+	return ch.GuildID, s.me.ID, nil
+}
+
+// permissionsObjects returns the guild (with its roles), the channel and the member
+// needed to compute permissions. The channel is nil if channelID is empty.
+// It must be called with the State lock held.
+func (s *State) permissionsObjects(guildID, channelID, userID string) (*discord.Guild, *discord.Channel, *discord.GuildMember, error) {
+	g, err := s.store.Guild(guildID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if g == nil {
+		return nil, nil, nil, fmt.Errorf("guild %q: %w", guildID, discord.ErrNotInState)
+	}
+
+	if g.Roles, err = s.store.Roles(guildID); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(g.Roles) == 0 {
+		return nil, nil, nil, fmt.Errorf("roles of guild %q: %w", guildID, discord.ErrNotInState)
+	}
+
+	var ch *discord.Channel
+	if channelID != "" {
+		if ch, err = s.store.Channel(channelID); err != nil {
+			return nil, nil, nil, err
+		}
+		if ch == nil || ch.GuildID != guildID {
+			return nil, nil, nil, fmt.Errorf("channel %q of guild %q: %w", channelID, guildID, discord.ErrNotInState)
+		}
+	}
+
+	m, err := s.store.Member(guildID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if m == nil {
+		return nil, nil, nil, fmt.Errorf("member %q of guild %q: %w", userID, guildID, discord.ErrNotInState)
+	}
+
+	return g, ch, m, nil
+}
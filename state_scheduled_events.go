@@ -0,0 +1,118 @@
+package harmony
+
+import "github.com/skwair/harmony/discord"
+
+// ScheduledEvents returns scheduled events of the given guild from the state.
+// Their user count is updated as users subscribe and unsubscribe, but it is not
+// sent along with guilds so it is only accurate for events created while connected.
+func (s *State) ScheduledEvents(guildID string) []discord.ScheduledEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+	return g.ScheduledEvents
+}
+
+// ScheduledEvent returns a scheduled event of the given guild from the state.
+func (s *State) ScheduledEvent(guildID, eventID string) *discord.ScheduledEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+
+	for i := 0; i < len(g.ScheduledEvents); i++ {
+		if g.ScheduledEvents[i].ID == eventID {
+			return &g.ScheduledEvents[i]
+		}
+	}
+	return nil
+}
+
+// setScheduledEvent adds or updates a scheduled event of a guild if this guild
+// is already tracked by the state, does nothing otherwise. It returns the
+// previous version of the event, if any.
+func (s *State) setScheduledEvent(e *discord.ScheduledEvent) *discord.ScheduledEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.policy.flags.Has(CacheScheduledEvents) {
+		return nil
+	}
+
+	g, err := s.store.Guild(e.GuildID)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+
+	var old *discord.ScheduledEvent
+	for i := 0; i < len(g.ScheduledEvents); i++ {
+		if g.ScheduledEvents[i].ID == e.ID {
+			old = &g.ScheduledEvents[i]
+			break
+		}
+	}
+
+	if old == nil {
+		g.ScheduledEvents = append(g.ScheduledEvents, *e.Clone())
+		s.storeError(s.store.SetGuild(g))
+		return nil
+	}
+
+	prev := old.Clone()
+	*old = *e.Clone()
+	// The user count is only sent when explicitly requested.
+	if e.UserCount == 0 {
+		old.UserCount = prev.UserCount
+	}
+	s.storeError(s.store.SetGuild(g))
+
+	return prev
+}
+
+// deleteScheduledEvent removes a scheduled event of a guild from the state.
+func (s *State) deleteScheduledEvent(guildID, eventID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return
+	}
+
+	for i := 0; i < len(g.ScheduledEvents); i++ {
+		if g.ScheduledEvents[i].ID == eventID {
+			g.ScheduledEvents = append(g.ScheduledEvents[:i], g.ScheduledEvents[i+1:]...)
+			s.storeError(s.store.SetGuild(g))
+			return
+		}
+	}
+}
+
+// updateScheduledEventUserCount adds delta to the number of users
+// subscribed to a scheduled event of a guild, if it is in the state.
+func (s *State) updateScheduledEventUserCount(guildID, eventID string, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return
+	}
+
+	for i := 0; i < len(g.ScheduledEvents); i++ {
+		if e := &g.ScheduledEvents[i]; e.ID == eventID {
+			e.UserCount += delta
+			if e.UserCount < 0 {
+				e.UserCount = 0
+			}
+			s.storeError(s.store.SetGuild(g))
+			return
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package harmony
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+)
+
+// StateSnapshotVersion is the version of the StateSnapshot format. It is
+// incremented each time a change that breaks compatibility with previous
+// snapshots is made.
+const StateSnapshotVersion = 1
+
+// StateSnapshot is a serializable copy of the whole State.
+// See State.Snapshot and State.Restore.
+type StateSnapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Gateway session the State was captured in. Only set by
+	// Client.SaveStateSnapshot, so a restarted Client can resume it.
+	SessionID string `json:"session_id,omitempty"`
+	Sequence  int64  `json:"sequence,omitempty"`
+
+	CurrentUser       *discord.User                        `json:"current_user"`
+	Users             map[string]*discord.User             `json:"users"`
+	Guilds            map[string]*discord.Guild            `json:"guilds"`
+	Presences         map[string]*discord.Presence         `json:"presences"`
+	Channels          map[string]*discord.Channel          `json:"channels"`
+	UnavailableGuilds map[string]*discord.UnavailableGuild `json:"unavailable_guilds"`
+}
+
+// Snapshot returns a consistent copy of the whole State that can be saved
+// and later restored with Restore. The State is locked while the snapshot
+// is taken, meaning events are not applied in the meantime.
+func (s *State) Snapshot() (*StateSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &StateSnapshot{
+		Version:           StateSnapshotVersion,
+		CreatedAt:         time.Now(),
+		CurrentUser:       s.me.Clone(),
+		UnavailableGuilds: make(map[string]*discord.UnavailableGuild, len(s.unavailableGuilds)),
+	}
+
+	var err error
+	if snap.Users, err = s.store.Users(); err != nil {
+		return nil, err
+	}
+	if snap.Guilds, err = s.store.Guilds(); err != nil {
+		return nil, err
+	}
+	for _, g := range snap.Guilds {
+		if err = s.fillGuild(g); err != nil {
+			return nil, err
+		}
+	}
+	if snap.Presences, err = s.store.Presences(); err != nil {
+		return nil, err
+	}
+	if snap.Channels, err = s.store.Channels(); err != nil {
+		return nil, err
+	}
+	for id, g := range s.unavailableGuilds {
+		snap.UnavailableGuilds[id] = g.Clone()
+	}
+
+	return snap, nil
+}
+
+// Restore loads the given snapshot into the State. Objects already in the State
+// that are also in the snapshot are replaced, others are kept. It is meant to be
+// called before connecting to the Gateway, so the State can be used right away
+// instead of waiting for Discord to send guilds again.
+// If the snapshot can not be fully restored, objects of the snapshot that were
+// already restored are removed from the State before returning the error, so it
+// does not hold partially restored guilds.
+func (s *State) Restore(snap *StateSnapshot) error {
+	if snap.Version != StateSnapshotVersion {
+		return fmt.Errorf("unsupported state snapshot version %d (expected %d)", snap.Version, StateSnapshotVersion)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	me := s.me
+	if err := s.restore(snap); err != nil {
+		s.unrestore(snap, me)
+		return err
+	}
+	return nil
+}
+
+// restore loads the given snapshot into the State, stopping at the first error.
+// It must be called with the State lock held.
+func (s *State) restore(snap *StateSnapshot) error {
+	for _, g := range snap.Guilds {
+		if _, err := s.setGuild(g); err != nil {
+			return fmt.Errorf("guild %s: %w", g.ID, err)
+		}
+	}
+
+	if snap.CurrentUser != nil {
+		s.notifySetMe(s.me, snap.CurrentUser)
+		s.me = snap.CurrentUser
+	}
+
+	for _, u := range snap.Users {
+		if err := s.store.SetUser(u); err != nil {
+			return fmt.Errorf("user %s: %w", u.ID, err)
+		}
+	}
+
+	for _, p := range snap.Presences {
+		if err := s.store.SetPresence(p); err != nil {
+			return fmt.Errorf("presence of %s: %w", p.User.ID, err)
+		}
+	}
+
+	for _, ch := range snap.Channels {
+		if err := s.store.SetChannel(ch); err != nil {
+			return fmt.Errorf("channel %s: %w", ch.ID, err)
+		}
+	}
+
+	for _, g := range snap.UnavailableGuilds {
+		s.setUnavailableGuild(g)
+	}
+
+	return nil
+}
+
+// unrestore removes objects of the given snapshot from the State after
+// it failed to be restored. me is the current user before the restoration.
+// It must be called with the State lock held.
+func (s *State) unrestore(snap *StateSnapshot, me *discord.User) {
+	for id := range snap.Guilds {
+		s.storeError(s.store.DeleteGuild(id))
+	}
+	for id := range snap.Users {
+		s.storeError(s.store.DeleteUser(id))
+	}
+	for id := range snap.Presences {
+		s.storeError(s.store.DeletePresence(id))
+	}
+	for id := range snap.Channels {
+		s.storeError(s.store.DeleteChannel(id))
+	}
+	for id := range snap.UnavailableGuilds {
+		s.deleteUnavailableGuild(id)
+	}
+
+	if me != s.me {
+		s.notifySetMe(s.me, me)
+		s.me = me
+	}
+}
+
+// WriteStateSnapshot writes the given snapshot to w as gzipped JSON.
+func WriteStateSnapshot(w io.Writer, snap *StateSnapshot) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		_ = zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadStateSnapshot reads a snapshot written with WriteStateSnapshot from r.
+func ReadStateSnapshot(r io.Reader) (*StateSnapshot, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var snap StateSnapshot
+	if err = json.NewDecoder(zr).Decode(&snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// SaveStateSnapshot saves a snapshot of the State to the file at the given path,
+// along with the current Gateway session so it can be resumed later by a Client
+// loading this snapshot with LoadStateSnapshot.
+// State tracking must be enabled.
+func (c *Client) SaveStateSnapshot(path string) error {
+	if !c.withStateTracking {
+		return discord.ErrStateNotTracked
+	}
+
+	snap, err := c.State.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	snap.SessionID = c.sessionID.Load()
+	snap.Sequence = c.sequence.Load()
+
+	// Write to a temporary file first so a crash
+	// never leaves a partial snapshot behind.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err = WriteStateSnapshot(f, snap); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadStateSnapshot restores the State from a snapshot saved with SaveStateSnapshot.
+// If the snapshot holds a Gateway session, the next call to Connect will try
+// to resume it, falling back to a new session if it expired.
+// It must be called before Connect and state tracking must be enabled.
+func (c *Client) LoadStateSnapshot(path string) error {
+	if !c.withStateTracking {
+		return discord.ErrStateNotTracked
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isConnected() {
+		return discord.ErrGatewayAlreadyConnected
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	snap, err := ReadStateSnapshot(f)
+	if err != nil {
+		return fmt.Errorf("could not read state snapshot: %w", err)
+	}
+
+	if err = c.State.Restore(snap); err != nil {
+		return err
+	}
+
+	if snap.SessionID != "" {
+		c.sessionID.Store(snap.SessionID)
+		c.sequence.Store(snap.Sequence)
+	}
+	if snap.CurrentUser != nil {
+		c.userID = snap.CurrentUser.ID
+	}
+
+	return nil
+}
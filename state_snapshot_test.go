@@ -0,0 +1,233 @@
+package harmony
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+func TestStateSnapshotRoundTrip(t *testing.T) {
+	s := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+	if err := s.Restore(testSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := s.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restore the snapshot in another State, through its serialized form.
+	var buf bytes.Buffer
+	if err = WriteStateSnapshot(&buf, snap); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadStateSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+	if err = restored.Restore(read); err != nil {
+		t.Fatal(err)
+	}
+	got, err := restored.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// Returns the entities of this kind in a snapshot.
+		entities func(snap *StateSnapshot) interface{}
+	}{
+		{name: "current user", entities: func(snap *StateSnapshot) interface{} { return snap.CurrentUser }},
+		{name: "users", entities: func(snap *StateSnapshot) interface{} { return snap.Users }},
+		{name: "guilds", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds }},
+		{name: "roles", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds["g1"].Roles }},
+		{name: "guild channels", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds["g1"].Channels }},
+		{name: "members", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds["g1"].Members }},
+		{name: "voice states", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds["g1"].VoiceStates }},
+		{name: "guild presences", entities: func(snap *StateSnapshot) interface{} { return snap.Guilds["g1"].Presences }},
+		{name: "presences", entities: func(snap *StateSnapshot) interface{} { return snap.Presences }},
+		{name: "channels", entities: func(snap *StateSnapshot) interface{} { return snap.Channels }},
+		{name: "unavailable guilds", entities: func(snap *StateSnapshot) interface{} { return snap.UnavailableGuilds }},
+	}
+
+	sortGuilds(snap)
+	sortGuilds(got)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.entities(snap)
+			if v := reflect.ValueOf(expected); v.IsNil() || (v.Kind() != reflect.Ptr && v.Len() == 0) {
+				t.Fatal("expected entities to be in the snapshot")
+			}
+			if actual := tt.entities(got); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %+v; got %+v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestStateRestoreVersion(t *testing.T) {
+	s := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+
+	snap := testSnapshot()
+	snap.Version = StateSnapshotVersion + 1
+	if err := s.Restore(snap); err == nil || !strings.Contains(err.Error(), "unsupported state snapshot version") {
+		t.Fatalf("expected unsupported version error; got %v", err)
+	}
+	if guilds, _ := s.store.Guilds(); len(guilds) != 0 {
+		t.Errorf("expected nothing to be restored; got %d guilds", len(guilds))
+	}
+}
+
+// failingStore is a StateStore that fails to store channels.
+type failingStore struct {
+	StateStore
+}
+
+var errFailingStore = errors.New("store failure")
+
+func (s failingStore) SetChannel(ch *discord.Channel) error {
+	if ch.GuildID == "" {
+		return errFailingStore
+	}
+	return s.StateStore.SetChannel(ch)
+}
+
+func TestStateRestoreRollback(t *testing.T) {
+	s := newState(failingStore{newMemoryStore()}, defaultCachePolicy(), testLogger)
+
+	// Objects that were already there are kept.
+	me := &discord.User{ID: "previous"}
+	s.me = me
+	if err := s.store.SetUser(&discord.User{ID: "u3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Guilds and users are restored before the DM channel fails to be.
+	if err := s.Restore(testSnapshot()); !errors.Is(err, errFailingStore) {
+		t.Fatalf("expected store error; got %v", err)
+	}
+
+	if s.me != me {
+		t.Errorf("expected current user to be rolled back; got %+v", s.me)
+	}
+	if guilds, _ := s.store.Guilds(); len(guilds) != 0 {
+		t.Errorf("expected guilds to be rolled back; got %d", len(guilds))
+	}
+	if members, _ := s.store.Members("g1"); len(members) != 0 {
+		t.Errorf("expected members to be rolled back; got %d", len(members))
+	}
+	if channels, _ := s.store.Channels(); len(channels) != 0 {
+		t.Errorf("expected channels to be rolled back; got %d", len(channels))
+	}
+	if presences, _ := s.store.Presences(); len(presences) != 0 {
+		t.Errorf("expected presences to be rolled back; got %d", len(presences))
+	}
+	users, _ := s.store.Users()
+	if _, ok := users["u3"]; len(users) != 1 || !ok {
+		t.Errorf("expected only the previous user to be left; got %v", users)
+	}
+}
+
+func TestSaveLoadStateSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.gz")
+
+	c, err := NewClient("token", WithLogger(testLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.State.Restore(testSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	c.sessionID.Store("session")
+	c.sequence.Store(42)
+	if err = c.SaveStateSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewClient("token", WithLogger(testLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = loaded.LoadStateSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, seq := loaded.sessionID.Load(), loaded.sequence.Load(); id != "session" || seq != 42 {
+		t.Errorf("expected session %q at sequence 42; got %q at %d", "session", id, seq)
+	}
+	if loaded.userID != "me" {
+		t.Errorf("expected user ID %q; got %q", "me", loaded.userID)
+	}
+	if g := loaded.State.Guild("g1"); g == nil || g.Name != "guild" || len(g.Members) != 2 {
+		t.Errorf("expected guild to be loaded; got %+v", g)
+	}
+
+	if err = loaded.LoadStateSnapshot(filepath.Join(t.TempDir(), "missing.gz")); err == nil {
+		t.Error("expected an error when loading a missing snapshot")
+	}
+}
+
+// testSnapshot returns a snapshot holding every kind of entity.
+func testSnapshot() *StateSnapshot {
+	voiceChannel := "c2"
+	return &StateSnapshot{
+		Version:     StateSnapshotVersion,
+		CurrentUser: &discord.User{ID: "me", Username: "bot"},
+		Users: map[string]*discord.User{
+			"me": {ID: "me", Username: "bot"},
+			"u1": {ID: "u1", Username: "alice"},
+			"u2": {ID: "u2", Username: "bob"},
+		},
+		Guilds: map[string]*discord.Guild{
+			"g1": {
+				ID:   "g1",
+				Name: "guild",
+				Roles: []discord.Role{
+					{ID: "g1", Name: "@everyone", Permissions: discord.PermissionViewChannel},
+					{ID: "r1", Name: "mod", Permissions: discord.PermissionModerateMembers},
+				},
+				Channels: []discord.Channel{
+					{ID: "c1", Name: "general", Type: discord.ChannelTypeGuildText},
+					{ID: "c2", Name: "voice", Type: discord.ChannelTypeGuildVoice},
+				},
+				Members: []discord.GuildMember{
+					{User: &discord.User{ID: "me", Username: "bot"}},
+					{User: &discord.User{ID: "u1", Username: "alice"}, Nick: "al", Roles: []string{"r1"}},
+				},
+				VoiceStates: []voice.State{
+					{UserID: "u1", ChannelID: &voiceChannel, SessionID: "s1"},
+				},
+			},
+		},
+		Presences: map[string]*discord.Presence{
+			"u1": {User: &discord.User{ID: "u1"}, GuildID: "g1", Status: discord.StatusOnline},
+		},
+		Channels: map[string]*discord.Channel{
+			"dm1": {ID: "dm1", Type: discord.ChannelTypeDM, Recipients: []discord.User{{ID: "u2", Username: "bob"}}},
+		},
+		UnavailableGuilds: map[string]*discord.UnavailableGuild{
+			"g2": {ID: "g2", Unavailable: new(bool)},
+		},
+	}
+}
+
+// sortGuilds sorts the entities of the guilds of a snapshot, so snapshots
+// can be compared regardless of the order they are returned by the store.
+func sortGuilds(snap *StateSnapshot) {
+	for _, g := range snap.Guilds {
+		sort.Slice(g.Roles, func(i, j int) bool { return g.Roles[i].ID < g.Roles[j].ID })
+		sort.Slice(g.Channels, func(i, j int) bool { return g.Channels[i].ID < g.Channels[j].ID })
+		sort.Slice(g.Members, func(i, j int) bool { return g.Members[i].User.ID < g.Members[j].User.ID })
+		sort.Slice(g.VoiceStates, func(i, j int) bool { return g.VoiceStates[i].UserID < g.VoiceStates[j].UserID })
+		sort.Slice(g.Presences, func(i, j int) bool { return g.Presences[i].User.ID < g.Presences[j].User.ID })
+	}
+}
@@ -0,0 +1,79 @@
+package harmony
+
+import "github.com/skwair/harmony/discord"
+
+// StageInstance returns the stage instance of the given stage channel
+// from the state. It returns nil if this channel is not live.
+func (s *State) StageInstance(channelID string) *discord.StageInstance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ch, err := s.store.Channel(channelID)
+	if s.storeError(err) || ch == nil {
+		return nil
+	}
+
+	g, err := s.store.Guild(ch.GuildID)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+
+	for i := 0; i < len(g.StageInstances); i++ {
+		if g.StageInstances[i].ChannelID == channelID {
+			return &g.StageInstances[i]
+		}
+	}
+	return nil
+}
+
+// setStageInstance adds or updates a stage instance of a guild if this guild
+// is already tracked by the state, does nothing otherwise. It returns the
+// previous version of the stage instance, if any.
+func (s *State) setStageInstance(si *discord.StageInstance) *discord.StageInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.policy.flags.Has(CacheStageInstances) {
+		return nil
+	}
+
+	g, err := s.store.Guild(si.GuildID)
+	if s.storeError(err) || g == nil {
+		return nil
+	}
+
+	var old *discord.StageInstance
+	for i := 0; i < len(g.StageInstances); i++ {
+		if g.StageInstances[i].ID == si.ID {
+			prev := g.StageInstances[i]
+			old = &prev
+			g.StageInstances[i] = *si
+			break
+		}
+	}
+	if old == nil {
+		g.StageInstances = append(g.StageInstances, *si)
+	}
+
+	s.storeError(s.store.SetGuild(g))
+	return old
+}
+
+// deleteStageInstance removes a stage instance of a guild from the state.
+func (s *State) deleteStageInstance(guildID, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.store.Guild(guildID)
+	if s.storeError(err) || g == nil {
+		return
+	}
+
+	for i := 0; i < len(g.StageInstances); i++ {
+		if g.StageInstances[i].ID == id {
+			g.StageInstances = append(g.StageInstances[:i], g.StageInstances[i+1:]...)
+			s.storeError(s.store.SetGuild(g))
+			return
+		}
+	}
+}
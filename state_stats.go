@@ -0,0 +1,426 @@
+package harmony
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// StateCount is the number of entities of a kind in the State
+// and the approximate number of bytes they use.
+type StateCount struct {
+	Count int   `json:"count"`
+	Bytes int64 `json:"bytes"`
+}
+
+// StateCounts are entity counts by kind.
+type StateCounts struct {
+	Users       StateCount `json:"users"`
+	Guilds      StateCount `json:"guilds"`
+	Channels    StateCount `json:"channels"`
+	Members     StateCount `json:"members"`
+	Roles       StateCount `json:"roles"`
+	Presences   StateCount `json:"presences"`
+	VoiceStates StateCount `json:"voice_states"`
+	Messages    StateCount `json:"messages"`
+}
+
+// Bytes returns the approximate number of bytes used by all entities.
+func (c *StateCounts) Bytes() int64 {
+	return c.Users.Bytes + c.Guilds.Bytes + c.Channels.Bytes + c.Members.Bytes +
+		c.Roles.Bytes + c.Presences.Bytes + c.VoiceStates.Bytes + c.Messages.Bytes
+}
+
+// count returns the count of the given entity kind.
+func (c *StateCounts) count(entity StateEntity) *StateCount {
+	switch entity {
+	case StateEntityUser:
+		return &c.Users
+	case StateEntityGuild:
+		return &c.Guilds
+	case StateEntityChannel:
+		return &c.Channels
+	case StateEntityMember:
+		return &c.Members
+	case StateEntityRole:
+		return &c.Roles
+	case StateEntityPresence:
+		return &c.Presences
+	case StateEntityVoiceState:
+		return &c.VoiceStates
+	}
+	return nil
+}
+
+// StateEvictions counts entities evicted from the State to respect its limits.
+type StateEvictions struct {
+	// Messages evicted from the message cache, see WithMessageCache.
+	Messages int64 `json:"messages"`
+	// Members and presences evicted to respect the
+	// memory budget, see WithStateMemoryBudget.
+	Members   int64 `json:"members"`
+	Presences int64 `json:"presences"`
+}
+
+// StateStats are statistics about the content of the State.
+type StateStats struct {
+	// Total counts of entities in the State.
+	Total StateCounts `json:"total"`
+	// Counts of entities by guild ID. Users, DM channels and
+	// messages are only counted in the total.
+	Guilds            map[string]StateCounts `json:"guilds"`
+	UnavailableGuilds int                    `json:"unavailable_guilds"`
+	Evictions         StateEvictions         `json:"evictions"`
+	// Memory budget of the State in bytes, 0 if unlimited.
+	MemoryBudget int64 `json:"memory_budget"`
+}
+
+// Stats returns statistics about the content of the State: number of entities by
+// kind and guild, the approximate memory they use and how many were evicted.
+// Sizes are approximate: they are estimated from the content of the entities
+// and do not account for the overhead of the StateStore.
+func (s *State) Stats() *StateStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := &StateStats{
+		Total:             s.accounting.total,
+		Guilds:            make(map[string]StateCounts, len(s.accounting.guilds)),
+		UnavailableGuilds: len(s.unavailableGuilds),
+		Evictions:         s.accounting.evictions,
+		MemoryBudget:      s.policy.memoryBudget,
+	}
+	for id, counts := range s.accounting.guilds {
+		stats.Guilds[id] = *counts
+	}
+
+	if s.messages != nil {
+		for _, cm := range s.messages.byID {
+			stats.Total.Messages.Count++
+			stats.Total.Messages.Bytes += approxSize(cm.msg)
+		}
+		stats.Evictions.Messages = s.messages.evictions
+	}
+	return stats
+}
+
+// stateAccounting keeps track of the number of entities in the State,
+// the memory they use and which members and presences were least
+// recently used, for the memory budget.
+type stateAccounting struct {
+	total  StateCounts
+	guilds map[string]*StateCounts
+
+	evictions StateEvictions
+
+	// Members and presences from the least to the most recently
+	// used, meaning set or read. Since reads happen with the State
+	// read lock held only, lruMu guards the list from concurrent reads.
+	lruMu    sync.Mutex
+	lru      *list.List
+	lruElems map[lruKey]*list.Element
+}
+
+// lruKey identifies a member or a presence in the LRU list.
+type lruKey struct {
+	entity  StateEntity
+	guildID string // Empty for presences.
+	userID  string
+}
+
+func newStateAccounting() *stateAccounting {
+	return &stateAccounting{
+		guilds:   make(map[string]*StateCounts),
+		lru:      list.New(),
+		lruElems: make(map[lruKey]*list.Element),
+	}
+}
+
+// account adds delta entities of the given kind using the given bytes to the
+// accounting. Entities that do not belong to a guild have an empty guildID.
+func (a *stateAccounting) account(entity StateEntity, guildID string, delta int, bytes int64) {
+	c := a.total.count(entity)
+	c.Count += delta
+	c.Bytes += bytes
+
+	if guildID == "" {
+		return
+	}
+	g := a.guilds[guildID]
+	if g == nil {
+		g = &StateCounts{}
+		a.guilds[guildID] = g
+	}
+	c = g.count(entity)
+	c.Count += delta
+	c.Bytes += bytes
+}
+
+// added accounts for an entity that was added to the State.
+func (a *stateAccounting) added(entity StateEntity, guildID string, v interface{}) {
+	a.account(entity, guildID, 1, approxSize(v))
+}
+
+// removed accounts for an entity that was removed from the State.
+func (a *stateAccounting) removed(entity StateEntity, guildID string, v interface{}) {
+	a.account(entity, guildID, -1, -approxSize(v))
+}
+
+// guildDeleted accounts for a guild that was deleted along with its
+// roles, members, channels and voice states.
+func (a *stateAccounting) guildDeleted(guildID string) {
+	g := a.guilds[guildID]
+	if g == nil {
+		return
+	}
+
+	for _, entity := range []StateEntity{StateEntityGuild, StateEntityChannel, StateEntityMember, StateEntityRole, StateEntityVoiceState} {
+		c := g.count(entity)
+		a.account(entity, guildID, -c.Count, -c.Bytes)
+	}
+
+	// Presences are not deleted along with guilds.
+	if g.Presences.Count == 0 {
+		delete(a.guilds, guildID)
+	}
+
+	a.lruMu.Lock()
+	defer a.lruMu.Unlock()
+
+	for key, e := range a.lruElems {
+		if key.entity == StateEntityMember && key.guildID == guildID {
+			a.lru.Remove(e)
+			delete(a.lruElems, key)
+		}
+	}
+}
+
+// touch marks a member or a presence as the most recently used.
+func (a *stateAccounting) touch(key lruKey) {
+	a.lruMu.Lock()
+	defer a.lruMu.Unlock()
+
+	if e := a.lruElems[key]; e != nil {
+		a.lru.MoveToBack(e)
+		return
+	}
+	a.lruElems[key] = a.lru.PushBack(key)
+}
+
+// forget removes a member or a presence from the LRU list.
+func (a *stateAccounting) forget(key lruKey) {
+	a.lruMu.Lock()
+	defer a.lruMu.Unlock()
+
+	if e := a.lruElems[key]; e != nil {
+		a.lru.Remove(e)
+		delete(a.lruElems, key)
+	}
+}
+
+// leastRecentlyUsed returns the least recently used member or presence
+// that is not owned by the given user, if any.
+func (a *stateAccounting) leastRecentlyUsed(except string) (lruKey, bool) {
+	a.lruMu.Lock()
+	defer a.lruMu.Unlock()
+
+	for e := a.lru.Front(); e != nil; e = e.Next() {
+		if key := e.Value.(lruKey); key.userID != except {
+			return key, true
+		}
+	}
+	return lruKey{}, false
+}
+
+// enforceMemoryBudget evicts the least recently used members and presences until
+// the State fits in its memory budget, if any. Members and presences are used when
+// they are set or read from the State. The current user is never evicted.
+// It must be called with the State lock held.
+func (s *State) enforceMemoryBudget() {
+	budget := s.policy.memoryBudget
+	a := s.accounting
+	if budget <= 0 {
+		return
+	}
+
+	var me string
+	if s.me != nil {
+		me = s.me.ID
+	}
+
+	for a.total.Bytes() > budget {
+		key, ok := a.leastRecentlyUsed(me)
+		if !ok {
+			return
+		}
+
+		switch key.entity {
+		case StateEntityMember:
+			if !s.storeError(s.store.DeleteMember(key.guildID, key.userID)) {
+				a.evictions.Members++
+			}
+		case StateEntityPresence:
+			if !s.storeError(s.store.DeletePresence(key.userID)) {
+				a.evictions.Presences++
+			}
+		}
+		a.forget(key)
+	}
+}
+
+// approxSize returns the approximate number of bytes
+// used by the object v, which must be a pointer.
+func approxSize(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return 0
+	}
+	return int64(rv.Elem().Type().Size()) + sizerOf(rv.Type().Elem()).indirect(rv.Elem())
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// sizer computes the number of bytes referenced by values of a type, not
+// counting the size of the values themselves. Sizers are built once per
+// type so computing the size of a value does not need to inspect every
+// field of its type, only those that reference memory.
+type sizer struct {
+	// nil if values of this type do not reference memory.
+	size func(v reflect.Value) int64
+}
+
+// indirect returns the number of bytes referenced by v.
+func (s *sizer) indirect(v reflect.Value) int64 {
+	if s.size == nil {
+		return 0
+	}
+	return s.size(v)
+}
+
+var (
+	sizersMu sync.RWMutex
+	sizers   = make(map[reflect.Type]*sizer)
+)
+
+// sizerOf returns the sizer of the given type.
+func sizerOf(t reflect.Type) *sizer {
+	sizersMu.RLock()
+	s, ok := sizers[t]
+	sizersMu.RUnlock()
+	if ok {
+		return s
+	}
+
+	sizersMu.Lock()
+	defer sizersMu.Unlock()
+	return buildSizer(t)
+}
+
+// buildSizer builds the sizer of the given type. It must be called with sizersMu held.
+func buildSizer(t reflect.Type) *sizer {
+	if s, ok := sizers[t]; ok {
+		return s
+	}
+
+	// Registered before being built for recursive types. Recursion
+	// always goes through pointers, slices or maps so sizers of
+	// structs and arrays are complete when they are returned.
+	s := &sizer{}
+	sizers[t] = s
+
+	switch t.Kind() {
+	case reflect.String:
+		s.size = func(v reflect.Value) int64 { return int64(v.Len()) }
+
+	case reflect.Ptr:
+		elem, elemSize := buildSizer(t.Elem()), int64(t.Elem().Size())
+		s.size = func(v reflect.Value) int64 {
+			if v.IsNil() {
+				return 0
+			}
+			return elemSize + elem.indirect(v.Elem())
+		}
+
+	case reflect.Interface:
+		s.size = func(v reflect.Value) int64 {
+			if v.IsNil() {
+				return 0
+			}
+			e := v.Elem()
+			return int64(e.Type().Size()) + sizerOf(e.Type()).indirect(e)
+		}
+
+	case reflect.Slice:
+		elem, elemSize := buildSizer(t.Elem()), int64(t.Elem().Size())
+		s.size = func(v reflect.Value) int64 {
+			if v.IsNil() {
+				return 0
+			}
+			n := int64(v.Cap()) * elemSize
+			if elem.size != nil {
+				for i := 0; i < v.Len(); i++ {
+					n += elem.size(v.Index(i))
+				}
+			}
+			return n
+		}
+
+	case reflect.Array:
+		if elem := buildSizer(t.Elem()); elem.size != nil {
+			s.size = func(v reflect.Value) int64 {
+				var n int64
+				for i := 0; i < v.Len(); i++ {
+					n += elem.size(v.Index(i))
+				}
+				return n
+			}
+		}
+
+	case reflect.Map:
+		key, keySize := buildSizer(t.Key()), int64(t.Key().Size())
+		value, valueSize := buildSizer(t.Elem()), int64(t.Elem().Size())
+		s.size = func(v reflect.Value) int64 {
+			if v.IsNil() {
+				return 0
+			}
+			n := int64(v.Len()) * (keySize + valueSize)
+			if key.size == nil && value.size == nil {
+				return n
+			}
+			iter := v.MapRange()
+			for iter.Next() {
+				n += key.indirect(iter.Key()) + value.indirect(iter.Value())
+			}
+			return n
+		}
+
+	case reflect.Struct:
+		// Locations of times are shared.
+		if t == timeType {
+			break
+		}
+
+		type field struct {
+			index int
+			s     *sizer
+		}
+		var fields []field
+		for i := 0; i < t.NumField(); i++ {
+			if fs := buildSizer(t.Field(i).Type); fs.size != nil {
+				fields = append(fields, field{index: i, s: fs})
+			}
+		}
+		if len(fields) == 0 {
+			break
+		}
+		s.size = func(v reflect.Value) int64 {
+			var n int64
+			for _, f := range fields {
+				n += f.s.size(v.Field(f.index))
+			}
+			return n
+		}
+	}
+	return s
+}
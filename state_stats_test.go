@@ -0,0 +1,175 @@
+package harmony
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/skwair/harmony/discord"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	member := func(id string) *discord.GuildMember {
+		return &discord.GuildMember{User: &discord.User{ID: id}}
+	}
+	memberSize := approxSize(member("1"))
+
+	tests := []struct {
+		name string
+		// Budget in number of members.
+		budget int
+		// Members to set, in order. Members prefixed with "?" are looked up instead.
+		ops []string
+		// Members expected to be left in the State.
+		expected []string
+		me       string
+	}{
+		{
+			name:     "no eviction",
+			budget:   3,
+			ops:      []string{"1", "2", "3"},
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "least recently set",
+			budget:   2,
+			ops:      []string{"1", "2", "3"},
+			expected: []string{"2", "3"},
+		},
+		{
+			name:     "updates count as use",
+			budget:   2,
+			ops:      []string{"1", "2", "1", "3"},
+			expected: []string{"1", "3"},
+		},
+		{
+			name:     "reads count as use",
+			budget:   2,
+			ops:      []string{"1", "2", "?1", "3"},
+			expected: []string{"1", "3"},
+		},
+		{
+			name:     "current user is never evicted",
+			budget:   2,
+			ops:      []string{"1", "2", "3"},
+			expected: []string{"1", "3"},
+			me:       "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := defaultCachePolicy()
+			policy.memoryBudget = int64(tt.budget) * memberSize
+			s := newState(newMemoryStore(), policy, testLogger)
+			if tt.me != "" {
+				s.me = &discord.User{ID: tt.me}
+			}
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			for _, op := range tt.ops {
+				if op[0] == '?' {
+					if _, err := s.store.Member("g1", op[1:]); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				if err := s.store.SetMember("g1", member(op)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			members, err := s.store.Members("g1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]bool)
+			for _, m := range members {
+				got[m.User.ID] = true
+			}
+			expected := make(map[string]bool)
+			for _, id := range tt.expected {
+				expected[id] = true
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected members %v; got %v", expected, got)
+			}
+
+			if evicted := int64(len(uniq(tt.ops)) - len(tt.expected)); s.accounting.evictions.Members != evicted {
+				t.Errorf("expected %d evictions; got %d", evicted, s.accounting.evictions.Members)
+			}
+			if n := s.accounting.total.Members.Count; n != len(tt.expected) {
+				t.Errorf("expected %d members to be accounted for; got %d", len(tt.expected), n)
+			}
+			if s.accounting.lru.Len() != len(tt.expected) {
+				t.Errorf("expected %d members in the LRU list; got %d", len(tt.expected), s.accounting.lru.Len())
+			}
+		})
+	}
+}
+
+func TestApproxSize(t *testing.T) {
+	type node struct {
+		Name     string
+		Next     *node
+		Children []node
+		Tags     map[string]string
+		Any      interface{}
+	}
+	nodeSize := int64(unsafe.Sizeof(node{}))
+
+	tests := []struct {
+		name     string
+		v        interface{}
+		expected int64
+	}{
+		{
+			name:     "nil",
+			v:        (*discord.User)(nil),
+			expected: 0,
+		},
+		{
+			name:     "strings",
+			v:        &discord.User{ID: "12", Username: "abc"},
+			expected: int64(unsafe.Sizeof(discord.User{})) + 5,
+		},
+		{
+			name:     "no indirect memory",
+			v:        &[4]int{},
+			expected: 32,
+		},
+		{
+			name:     "recursive",
+			v:        &node{Name: "a", Next: &node{Name: "bc"}},
+			expected: 2*nodeSize + 3,
+		},
+		{
+			name:     "slices, maps and interfaces",
+			v:        &node{Children: make([]node, 1, 2), Tags: map[string]string{"k": "vv"}, Any: "abcd"},
+			expected: nodeSize + 2*nodeSize + 2*int64(unsafe.Sizeof("")) + 3 + int64(unsafe.Sizeof("")) + 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The second time, the cached sizer is used.
+			for i := 0; i < 2; i++ {
+				if got := approxSize(tt.v); got != tt.expected {
+					t.Errorf("expected %d bytes; got %d", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func uniq(ops []string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, op := range ops {
+		if op[0] != '?' {
+			ids[op] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,365 @@
+package harmony
+
+import (
+	"sync"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+// StateStore is a storage backend for the State. The State keeps entities
+// normalized in a StateStore: guilds are stored without their roles, members,
+// channels, presences and voice states, which are stored on their own.
+//
+// Implementations must be safe for concurrent use. Objects given to and returned
+// by a StateStore must not be retained or shared by the store: they can be modified
+// by the caller afterwards. Getters return a nil object and a nil error when the
+// requested object is not found. Slices and maps are returned in no particular order.
+//
+// The default StateStore keeps everything in memory, see WithStateStore to use
+// a different one. The diskstore package provides a StateStore that keeps
+// entities on disk instead.
+type StateStore interface {
+	User(id string) (*discord.User, error)
+	Users() (map[string]*discord.User, error)
+	SetUser(u *discord.User) error
+	DeleteUser(id string) error
+
+	Guild(id string) (*discord.Guild, error)
+	Guilds() (map[string]*discord.Guild, error)
+	SetGuild(g *discord.Guild) error
+	// DeleteGuild deletes a guild along with its roles, members,
+	// channels and voice states.
+	DeleteGuild(id string) error
+
+	Member(guildID, userID string) (*discord.GuildMember, error)
+	Members(guildID string) ([]discord.GuildMember, error)
+	SetMember(guildID string, m *discord.GuildMember) error
+	DeleteMember(guildID, userID string) error
+
+	Channel(id string) (*discord.Channel, error)
+	Channels() (map[string]*discord.Channel, error)
+	GuildChannels(guildID string) ([]discord.Channel, error)
+	SetChannel(ch *discord.Channel) error
+	DeleteChannel(id string) error
+
+	Role(guildID, roleID string) (*discord.Role, error)
+	Roles(guildID string) ([]discord.Role, error)
+	SetRole(guildID string, r *discord.Role) error
+	DeleteRole(guildID, roleID string) error
+
+	// Presences are stored by user ID.
+	Presence(userID string) (*discord.Presence, error)
+	Presences() (map[string]*discord.Presence, error)
+	SetPresence(p *discord.Presence) error
+	DeletePresence(userID string) error
+
+	VoiceState(guildID, userID string) (*voice.State, error)
+	VoiceStates(guildID string) ([]voice.State, error)
+	SetVoiceState(vs *voice.State) error
+	DeleteVoiceState(guildID, userID string) error
+}
+
+// memoryStore is the default StateStore, keeping everything in memory.
+type memoryStore struct {
+	mu sync.RWMutex
+
+	users         map[string]*discord.User
+	guilds        map[string]*discord.Guild
+	members       map[string]map[string]*discord.GuildMember // Members by guild ID and user ID.
+	channels      map[string]*discord.Channel
+	guildChannels map[string]map[string]struct{}      // Channel IDs by guild ID.
+	roles         map[string]map[string]*discord.Role // Roles by guild ID and role ID.
+	presences     map[string]*discord.Presence        // Presence by user ID.
+	voiceStates   map[string]map[string]*voice.State  // Voice states by guild ID and user ID.
+}
+
+var _ StateStore = (*memoryStore)(nil)
+
+// newMemoryStore returns a new in-memory StateStore.
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		users:         make(map[string]*discord.User),
+		guilds:        make(map[string]*discord.Guild),
+		members:       make(map[string]map[string]*discord.GuildMember),
+		channels:      make(map[string]*discord.Channel),
+		guildChannels: make(map[string]map[string]struct{}),
+		roles:         make(map[string]map[string]*discord.Role),
+		presences:     make(map[string]*discord.Presence),
+		voiceStates:   make(map[string]map[string]*voice.State),
+	}
+}
+
+func (s *memoryStore) User(id string) (*discord.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.users[id].Clone(), nil
+}
+
+func (s *memoryStore) Users() (map[string]*discord.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	newMap := make(map[string]*discord.User, len(s.users))
+	for k, v := range s.users {
+		newMap[k] = v.Clone()
+	}
+	return newMap, nil
+}
+
+func (s *memoryStore) SetUser(u *discord.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[u.ID] = u.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeleteUser(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, id)
+	return nil
+}
+
+func (s *memoryStore) Guild(id string) (*discord.Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.guilds[id].Clone(), nil
+}
+
+func (s *memoryStore) Guilds() (map[string]*discord.Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	newMap := make(map[string]*discord.Guild, len(s.guilds))
+	for k, v := range s.guilds {
+		newMap[k] = v.Clone()
+	}
+	return newMap, nil
+}
+
+func (s *memoryStore) SetGuild(g *discord.Guild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.guilds[g.ID] = g.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeleteGuild(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for chID := range s.guildChannels[id] {
+		delete(s.channels, chID)
+	}
+	delete(s.guildChannels, id)
+	delete(s.members, id)
+	delete(s.roles, id)
+	delete(s.voiceStates, id)
+	delete(s.guilds, id)
+	return nil
+}
+
+func (s *memoryStore) Member(guildID, userID string) (*discord.GuildMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.members[guildID][userID].Clone(), nil
+}
+
+func (s *memoryStore) Members(guildID string) ([]discord.GuildMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := make([]discord.GuildMember, 0, len(s.members[guildID]))
+	for _, m := range s.members[guildID] {
+		members = append(members, *m.Clone())
+	}
+	return members, nil
+}
+
+func (s *memoryStore) SetMember(guildID string, m *discord.GuildMember) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.members[guildID] == nil {
+		s.members[guildID] = make(map[string]*discord.GuildMember)
+	}
+	s.members[guildID][m.User.ID] = m.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeleteMember(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.members[guildID], userID)
+	return nil
+}
+
+func (s *memoryStore) Channel(id string) (*discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.channels[id].Clone(), nil
+}
+
+func (s *memoryStore) Channels() (map[string]*discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	newMap := make(map[string]*discord.Channel, len(s.channels))
+	for k, v := range s.channels {
+		newMap[k] = v.Clone()
+	}
+	return newMap, nil
+}
+
+func (s *memoryStore) GuildChannels(guildID string) ([]discord.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := make([]discord.Channel, 0, len(s.guildChannels[guildID]))
+	for id := range s.guildChannels[guildID] {
+		channels = append(channels, *s.channels[id].Clone())
+	}
+	return channels, nil
+}
+
+func (s *memoryStore) SetChannel(ch *discord.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[ch.ID] = ch.Clone()
+	if ch.GuildID != "" {
+		if s.guildChannels[ch.GuildID] == nil {
+			s.guildChannels[ch.GuildID] = make(map[string]struct{})
+		}
+		s.guildChannels[ch.GuildID][ch.ID] = struct{}{}
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteChannel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ch := s.channels[id]; ch != nil {
+		delete(s.guildChannels[ch.GuildID], id)
+	}
+	delete(s.channels, id)
+	return nil
+}
+
+func (s *memoryStore) Role(guildID, roleID string) (*discord.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.roles[guildID][roleID].Clone(), nil
+}
+
+func (s *memoryStore) Roles(guildID string) ([]discord.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]discord.Role, 0, len(s.roles[guildID]))
+	for _, r := range s.roles[guildID] {
+		roles = append(roles, *r.Clone())
+	}
+	return roles, nil
+}
+
+func (s *memoryStore) SetRole(guildID string, r *discord.Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roles[guildID] == nil {
+		s.roles[guildID] = make(map[string]*discord.Role)
+	}
+	s.roles[guildID][r.ID] = r.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeleteRole(guildID, roleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.roles[guildID], roleID)
+	return nil
+}
+
+func (s *memoryStore) Presence(userID string) (*discord.Presence, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.presences[userID].Clone(), nil
+}
+
+func (s *memoryStore) Presences() (map[string]*discord.Presence, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	newMap := make(map[string]*discord.Presence, len(s.presences))
+	for k, v := range s.presences {
+		newMap[k] = v.Clone()
+	}
+	return newMap, nil
+}
+
+func (s *memoryStore) SetPresence(p *discord.Presence) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.presences[p.User.ID] = p.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeletePresence(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.presences, userID)
+	return nil
+}
+
+func (s *memoryStore) VoiceState(guildID, userID string) (*voice.State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.voiceStates[guildID][userID].Clone(), nil
+}
+
+func (s *memoryStore) VoiceStates(guildID string) ([]voice.State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]voice.State, 0, len(s.voiceStates[guildID]))
+	for _, vs := range s.voiceStates[guildID] {
+		states = append(states, *vs.Clone())
+	}
+	return states, nil
+}
+
+func (s *memoryStore) SetVoiceState(vs *voice.State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.voiceStates[vs.GuildID] == nil {
+		s.voiceStates[vs.GuildID] = make(map[string]*voice.State)
+	}
+	s.voiceStates[vs.GuildID][vs.UserID] = vs.Clone()
+	return nil
+}
+
+func (s *memoryStore) DeleteVoiceState(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.voiceStates[guildID], userID)
+	return nil
+}
@@ -0,0 +1,256 @@
+package harmony
+
+import (
+	"sync"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/log"
+)
+
+// defaultSubscriberQueueSize is the default maximum number
+// of changes waiting to be handled by a State subscriber.
+const defaultSubscriberQueueSize = 1024
+
+// StateEntity is a kind of entity tracked by the State.
+type StateEntity int
+
+// Valid state entities.
+const (
+	StateEntityUser StateEntity = 1 << iota
+	StateEntityGuild
+	StateEntityUnavailableGuild
+	StateEntityChannel
+	StateEntityMember
+	StateEntityRole
+	StateEntityPresence
+	StateEntityVoiceState
+
+	StateEntityAll = StateEntityUser | StateEntityGuild | StateEntityUnavailableGuild |
+		StateEntityChannel | StateEntityMember | StateEntityRole | StateEntityPresence |
+		StateEntityVoiceState
+)
+
+// StateChangeType is the type of a StateChange.
+type StateChangeType int
+
+// Valid state change types.
+const (
+	StateChangeAdd StateChangeType = iota
+	StateChangeUpdate
+	StateChangeRemove
+)
+
+// StateChange describes a change of an entity in the State.
+type StateChange struct {
+	Type   StateChangeType
+	Entity StateEntity
+	// GuildID is the ID of the guild the entity belongs to, if any.
+	GuildID string
+	// ID of the entity. For members, presences and voice states,
+	// this is the ID of the user.
+	ID string
+
+	// Old is the entity before the change, nil if it was added.
+	// New is the entity after the change, nil if it was removed.
+	// Depending on the entity, they are one of *discord.User, *discord.Guild,
+	// *discord.UnavailableGuild, *discord.Channel, *discord.GuildMember,
+	// *discord.Role, *discord.Presence or *voice.State.
+	// Guilds are stored without their roles, channels, members, presences
+	// and voice states, those are sent as changes on their own.
+	Old, New interface{}
+}
+
+// StateFilter selects which changes are sent to a State subscriber.
+type StateFilter struct {
+	// Entities to receive changes of, e.g. StateEntityChannel | StateEntityRole.
+	// Defaults to StateEntityAll if zero.
+	Entities StateEntity
+	// If set, only changes of entities that belong to this guild are received.
+	// Users and DM channels do not belong to any guild.
+	GuildID string
+	// QueueSize is the maximum number of changes waiting to be handled by the
+	// subscriber. Changes that do not fit are dropped and a warning is logged.
+	// Defaults to 1024 if zero.
+	QueueSize int
+}
+
+// match reports whether a change of the given entity in the given guild matches this filter.
+func (f *StateFilter) match(entity StateEntity, guildID string) bool {
+	if f.Entities != 0 && f.Entities&entity == 0 {
+		return false
+	}
+	return f.GuildID == "" || f.GuildID == guildID
+}
+
+// stateSubscriber receives state changes in its own goroutine, in order.
+type stateSubscriber struct {
+	filter StateFilter
+	f      func(*StateChange)
+	logger log.Logger
+
+	mu    sync.Mutex
+	queue []*StateChange
+	// Number of changes dropped since the queue was last full.
+	dropped int
+
+	// signal is notified when changes are queued.
+	signal chan struct{}
+	done   chan struct{}
+}
+
+// run calls the subscriber function for each queued change until done is closed.
+func (sub *stateSubscriber) run() {
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-sub.signal:
+		}
+
+		sub.mu.Lock()
+		queue := sub.queue
+		sub.queue = nil
+		if sub.dropped > 0 {
+			sub.logger.Warnf("state subscriber was too slow, dropped %d changes", sub.dropped)
+			sub.dropped = 0
+		}
+		sub.mu.Unlock()
+
+		for _, c := range queue {
+			select {
+			case <-sub.done:
+				return
+			default:
+			}
+			sub.f(c)
+		}
+	}
+}
+
+// push queues a change for this subscriber, it never blocks.
+// The change is dropped if the queue is full.
+func (sub *stateSubscriber) push(c *StateChange) {
+	sub.mu.Lock()
+	if len(sub.queue) < sub.filter.QueueSize {
+		sub.queue = append(sub.queue, c)
+	} else {
+		sub.dropped++
+	}
+	sub.mu.Unlock()
+
+	select {
+	case sub.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe registers f to be called for each change of the State that matches the
+// given filter, whether it comes from an event received from the Gateway or from a
+// restored snapshot. Changes are sent in order, in a goroutine dedicated to this
+// subscriber so f can safely query the State. StateChange objects are shared between
+// subscribers and must not be modified. Changes are queued while f runs, up to the
+// queue size of the filter: if f can not keep up, further changes are dropped until
+// the queue is drained again, so a slow subscriber never holds more than a bounded
+// number of changes nor blocks the State. Call the returned function to unsubscribe,
+// f is not called anymore once it returns, though a call may still be in progress.
+func (s *State) Subscribe(filter StateFilter, f func(c *StateChange)) (unsubscribe func()) {
+	if filter.QueueSize <= 0 {
+		filter.QueueSize = defaultSubscriberQueueSize
+	}
+
+	sub := &stateSubscriber{
+		filter: filter,
+		f:      f,
+		logger: s.logger,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.subscribers = append(s.subscribers, sub)
+	s.mu.Unlock()
+
+	go sub.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			for i := 0; i < len(s.subscribers); i++ {
+				if s.subscribers[i] == sub {
+					s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+					break
+				}
+			}
+			s.mu.Unlock()
+
+			close(sub.done)
+		})
+	}
+}
+
+// subscribed reports whether a subscriber is interested in changes of the given
+// entity in the given guild. It must be called with the State lock held.
+func (s *State) subscribed(entity StateEntity, guildID string) bool {
+	for _, sub := range s.subscribers {
+		if sub.filter.match(entity, guildID) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify sends the given change to interested subscribers.
+// It must be called with the State lock held.
+func (s *State) notify(c *StateChange) {
+	for _, sub := range s.subscribers {
+		if sub.filter.match(c.Entity, c.GuildID) {
+			sub.push(c)
+		}
+	}
+}
+
+// notifySetMe notifies subscribers that the current user was set.
+// It must be called with the State lock held.
+func (s *State) notifySetMe(old, u *discord.User) {
+	if !s.subscribed(StateEntityUser, "") {
+		return
+	}
+
+	c := &StateChange{Type: StateChangeAdd, Entity: StateEntityUser, ID: u.ID, New: u.Clone()}
+	if old != nil {
+		c.Type = StateChangeUpdate
+		c.Old = old.Clone()
+	}
+	s.notify(c)
+}
+
+// setUnavailableGuild marks a guild as unavailable, notifying subscribers.
+// It must be called with the State lock held.
+func (s *State) setUnavailableGuild(g *discord.UnavailableGuild) {
+	old := s.unavailableGuilds[g.ID]
+	s.unavailableGuilds[g.ID] = g
+
+	if !s.subscribed(StateEntityUnavailableGuild, g.ID) {
+		return
+	}
+
+	c := &StateChange{Type: StateChangeAdd, Entity: StateEntityUnavailableGuild, GuildID: g.ID, ID: g.ID, New: g.Clone()}
+	if old != nil {
+		c.Type = StateChangeUpdate
+		c.Old = old.Clone()
+	}
+	s.notify(c)
+}
+
+// deleteUnavailableGuild removes a guild from unavailable guilds, notifying
+// subscribers. It must be called with the State lock held.
+func (s *State) deleteUnavailableGuild(id string) {
+	old := s.unavailableGuilds[id]
+	if old == nil {
+		return
+	}
+	delete(s.unavailableGuilds, id)
+
+	s.notify(&StateChange{Type: StateChangeRemove, Entity: StateEntityUnavailableGuild, GuildID: id, ID: id, Old: old.Clone()})
+}
@@ -0,0 +1,58 @@
+package harmony
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStateSubscriberQueue(t *testing.T) {
+	s := newState(newMemoryStore(), defaultCachePolicy(), testLogger)
+
+	var (
+		mu       sync.Mutex
+		received []string
+		wg       sync.WaitGroup
+	)
+	block := make(chan struct{})
+	unsubscribe := s.Subscribe(StateFilter{QueueSize: 2}, func(c *StateChange) {
+		<-block
+		mu.Lock()
+		received = append(received, c.ID)
+		mu.Unlock()
+		wg.Done()
+	})
+	defer unsubscribe()
+
+	// The first change is being handled, the next two are
+	// queued and the last one is dropped.
+	wg.Add(3)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := s.subscribers[0]
+	for _, id := range []string{"1", "2", "3", "4"} {
+		s.notify(&StateChange{Entity: StateEntityUser, ID: id})
+
+		// Wait for the subscriber to pick up the first change.
+		for id == "1" {
+			sub.mu.Lock()
+			n := len(sub.queue)
+			sub.mu.Unlock()
+			if n == 0 {
+				break
+			}
+		}
+	}
+	close(block)
+	waitGroup(t, &wg)
+
+	mu.Lock()
+	defer mu.Unlock()
+	sub.mu.Lock()
+	if sub.dropped != 0 {
+		t.Errorf("expected dropped changes to be reported; %d are not", sub.dropped)
+	}
+	sub.mu.Unlock()
+	if len(received) != 3 || received[0] != "1" || received[1] != "2" || received[2] != "3" {
+		t.Errorf("expected changes [1 2 3]; got %v", received)
+	}
+}
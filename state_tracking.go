@@ -0,0 +1,369 @@
+package harmony
+
+import (
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+// trackingStore wraps the StateStore of a State to keep its indexes and
+// accounting up to date and notify its subscribers of each change made
+// to the store. All its methods must be called with the State lock held.
+type trackingStore struct {
+	StateStore
+	s *State
+}
+
+// build indexes and accounts for all entities already in the given store.
+func (s *State) build(store StateStore) error {
+	users, err := store.Users()
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		s.accounting.added(StateEntityUser, "", u)
+	}
+
+	channels, err := store.Channels()
+	if err != nil {
+		return err
+	}
+	for _, ch := range channels {
+		s.index.setChannel(nil, ch)
+		s.accounting.added(StateEntityChannel, ch.GuildID, ch)
+	}
+
+	presences, err := store.Presences()
+	if err != nil {
+		return err
+	}
+	for _, p := range presences {
+		s.accounting.added(StateEntityPresence, p.GuildID, p)
+		s.accounting.touch(lruKey{entity: StateEntityPresence, userID: p.User.ID})
+	}
+
+	guilds, err := store.Guilds()
+	if err != nil {
+		return err
+	}
+	for guildID, g := range guilds {
+		s.accounting.added(StateEntityGuild, guildID, g)
+
+		roles, err := store.Roles(guildID)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(roles); i++ {
+			s.index.setRole(guildID, nil, &roles[i])
+			s.accounting.added(StateEntityRole, guildID, &roles[i])
+		}
+
+		members, err := store.Members(guildID)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(members); i++ {
+			m := &members[i]
+			s.index.setMember(guildID, nil, m)
+			s.accounting.added(StateEntityMember, guildID, m)
+			s.accounting.touch(lruKey{entity: StateEntityMember, guildID: guildID, userID: m.User.ID})
+		}
+
+		states, err := store.VoiceStates(guildID)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(states); i++ {
+			s.accounting.added(StateEntityVoiceState, guildID, &states[i])
+		}
+	}
+
+	// Members do not always hold the latest version of their user.
+	for _, u := range users {
+		s.index.setUser(u)
+	}
+	return nil
+}
+
+// set accounts for an entity that was set and notifies subscribers, old being
+// nil if it was added. new is cloned before being sent to subscribers.
+func (t *trackingStore) set(entity StateEntity, oldGuildID, guildID, id string, old, new interface{}, added bool) {
+	if !added {
+		t.s.accounting.removed(entity, oldGuildID, old)
+	}
+	t.s.accounting.added(entity, guildID, new)
+
+	if !t.s.subscribed(entity, guildID) {
+		return
+	}
+
+	c := &StateChange{Type: StateChangeUpdate, Entity: entity, GuildID: guildID, ID: id, Old: old, New: cloneEntity(new)}
+	if added {
+		c.Type = StateChangeAdd
+		c.Old = nil
+	}
+	t.s.notify(c)
+}
+
+// deleted accounts for an entity that was removed and notifies subscribers.
+func (t *trackingStore) deleted(entity StateEntity, guildID, id string, old interface{}) {
+	t.s.accounting.removed(entity, guildID, old)
+
+	if t.s.subscribed(entity, guildID) {
+		t.s.notify(&StateChange{Type: StateChangeRemove, Entity: entity, GuildID: guildID, ID: id, Old: old})
+	}
+}
+
+func (t *trackingStore) SetUser(u *discord.User) error {
+	old, err := t.StateStore.User(u.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetUser(u); err != nil {
+		return err
+	}
+	t.s.index.setUser(u)
+	t.set(StateEntityUser, "", "", u.ID, old, u, old == nil)
+	return nil
+}
+
+func (t *trackingStore) DeleteUser(id string) error {
+	old, err := t.StateStore.User(id)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteUser(id); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.deleted(StateEntityUser, "", id, old)
+	return nil
+}
+
+func (t *trackingStore) SetGuild(g *discord.Guild) error {
+	old, err := t.StateStore.Guild(g.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetGuild(g); err != nil {
+		return err
+	}
+	t.set(StateEntityGuild, g.ID, g.ID, g.ID, old, g, old == nil)
+	return nil
+}
+
+// DeleteGuild deletes a guild along with its roles, members, channels and voice
+// states. Only the removal of the guild itself is sent to subscribers.
+func (t *trackingStore) DeleteGuild(id string) error {
+	old, err := t.StateStore.Guild(id)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteGuild(id); err != nil {
+		return err
+	}
+	t.s.index.deleteGuild(id)
+	t.s.accounting.guildDeleted(id)
+
+	if old != nil && t.s.subscribed(StateEntityGuild, id) {
+		t.s.notify(&StateChange{Type: StateChangeRemove, Entity: StateEntityGuild, GuildID: id, ID: id, Old: old})
+	}
+	return nil
+}
+
+// Member marks the member as used for the memory budget.
+func (t *trackingStore) Member(guildID, userID string) (*discord.GuildMember, error) {
+	m, err := t.StateStore.Member(guildID, userID)
+	if m != nil && t.s.policy.memoryBudget > 0 {
+		t.s.accounting.touch(lruKey{entity: StateEntityMember, guildID: guildID, userID: userID})
+	}
+	return m, err
+}
+
+func (t *trackingStore) SetMember(guildID string, m *discord.GuildMember) error {
+	old, err := t.StateStore.Member(guildID, m.User.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetMember(guildID, m); err != nil {
+		return err
+	}
+	t.s.index.setMember(guildID, old, m)
+	t.set(StateEntityMember, guildID, guildID, m.User.ID, old, m, old == nil)
+
+	t.s.accounting.touch(lruKey{entity: StateEntityMember, guildID: guildID, userID: m.User.ID})
+	t.s.enforceMemoryBudget()
+	return nil
+}
+
+func (t *trackingStore) DeleteMember(guildID, userID string) error {
+	old, err := t.StateStore.Member(guildID, userID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteMember(guildID, userID); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.s.index.deleteMember(guildID, old)
+	t.s.accounting.forget(lruKey{entity: StateEntityMember, guildID: guildID, userID: userID})
+	t.deleted(StateEntityMember, guildID, userID, old)
+	return nil
+}
+
+func (t *trackingStore) SetChannel(ch *discord.Channel) error {
+	old, err := t.StateStore.Channel(ch.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetChannel(ch); err != nil {
+		return err
+	}
+	t.s.index.setChannel(old, ch)
+	var oldGuildID string
+	if old != nil {
+		oldGuildID = old.GuildID
+	}
+	t.set(StateEntityChannel, oldGuildID, ch.GuildID, ch.ID, old, ch, old == nil)
+	return nil
+}
+
+func (t *trackingStore) DeleteChannel(id string) error {
+	old, err := t.StateStore.Channel(id)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteChannel(id); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.s.index.deleteChannel(old)
+	t.deleted(StateEntityChannel, old.GuildID, id, old)
+	return nil
+}
+
+func (t *trackingStore) SetRole(guildID string, r *discord.Role) error {
+	old, err := t.StateStore.Role(guildID, r.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetRole(guildID, r); err != nil {
+		return err
+	}
+	t.s.index.setRole(guildID, old, r)
+	t.set(StateEntityRole, guildID, guildID, r.ID, old, r, old == nil)
+	return nil
+}
+
+func (t *trackingStore) DeleteRole(guildID, roleID string) error {
+	old, err := t.StateStore.Role(guildID, roleID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteRole(guildID, roleID); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.s.index.deleteRole(guildID, old)
+	t.deleted(StateEntityRole, guildID, roleID, old)
+	return nil
+}
+
+// Presence marks the presence as used for the memory budget.
+func (t *trackingStore) Presence(userID string) (*discord.Presence, error) {
+	p, err := t.StateStore.Presence(userID)
+	if p != nil && t.s.policy.memoryBudget > 0 {
+		t.s.accounting.touch(lruKey{entity: StateEntityPresence, userID: userID})
+	}
+	return p, err
+}
+
+func (t *trackingStore) SetPresence(p *discord.Presence) error {
+	old, err := t.StateStore.Presence(p.User.ID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetPresence(p); err != nil {
+		return err
+	}
+	var oldGuildID string
+	if old != nil {
+		oldGuildID = old.GuildID
+	}
+	t.set(StateEntityPresence, oldGuildID, p.GuildID, p.User.ID, old, p, old == nil)
+
+	t.s.accounting.touch(lruKey{entity: StateEntityPresence, userID: p.User.ID})
+	t.s.enforceMemoryBudget()
+	return nil
+}
+
+func (t *trackingStore) DeletePresence(userID string) error {
+	old, err := t.StateStore.Presence(userID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeletePresence(userID); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.s.accounting.forget(lruKey{entity: StateEntityPresence, userID: userID})
+	t.deleted(StateEntityPresence, old.GuildID, userID, old)
+	return nil
+}
+
+func (t *trackingStore) SetVoiceState(vs *voice.State) error {
+	old, err := t.StateStore.VoiceState(vs.GuildID, vs.UserID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.SetVoiceState(vs); err != nil {
+		return err
+	}
+	t.set(StateEntityVoiceState, vs.GuildID, vs.GuildID, vs.UserID, old, vs, old == nil)
+	return nil
+}
+
+func (t *trackingStore) DeleteVoiceState(guildID, userID string) error {
+	old, err := t.StateStore.VoiceState(guildID, userID)
+	if err != nil {
+		return err
+	}
+	if err = t.StateStore.DeleteVoiceState(guildID, userID); err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	t.deleted(StateEntityVoiceState, guildID, userID, old)
+	return nil
+}
+
+// cloneEntity returns a clone of the given entity.
+func cloneEntity(v interface{}) interface{} {
+	switch e := v.(type) {
+	case *discord.User:
+		return e.Clone()
+	case *discord.Guild:
+		return e.Clone()
+	case *discord.GuildMember:
+		return e.Clone()
+	case *discord.Channel:
+		return e.Clone()
+	case *discord.Role:
+		return e.Clone()
+	case *discord.Presence:
+		return e.Clone()
+	case *voice.State:
+		return e.Clone()
+	}
+	return v
+}
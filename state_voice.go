@@ -0,0 +1,156 @@
+package harmony
+
+import "github.com/skwair/harmony/voice"
+
+// Those events are not sent by the Gateway but derived by the Client
+// from Voice State Update events.
+const (
+	eventVoiceChannelJoin  = "VOICE_CHANNEL_JOIN"
+	eventVoiceChannelLeave = "VOICE_CHANNEL_LEAVE"
+	eventVoiceChannelMove  = "VOICE_CHANNEL_MOVE"
+)
+
+// VoiceState returns the voice state of a user in a guild from the state.
+// It returns nil if this user is not connected to a voice channel of this guild.
+func (s *State) VoiceState(guildID, userID string) *voice.State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vs, err := s.store.VoiceState(guildID, userID)
+	if s.storeError(err) {
+		return nil
+	}
+	return vs
+}
+
+// VoiceChannelMembers returns voice states of users that are currently
+// connected to the given voice channel from the state.
+func (s *State) VoiceChannelMembers(channelID string) []voice.State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// If the channel is known, only look into its guild.
+	var guildIDs []string
+	ch, err := s.store.Channel(channelID)
+	if s.storeError(err) {
+		return nil
+	}
+	if ch != nil {
+		guildIDs = append(guildIDs, ch.GuildID)
+	} else {
+		guilds, err := s.store.Guilds()
+		if s.storeError(err) {
+			return nil
+		}
+		for id := range guilds {
+			guildIDs = append(guildIDs, id)
+		}
+	}
+
+	var states []voice.State
+	for _, guildID := range guildIDs {
+		all, err := s.store.VoiceStates(guildID)
+		if s.storeError(err) {
+			return nil
+		}
+
+		for i := 0; i < len(all); i++ {
+			if all[i].ChannelID != nil && *all[i].ChannelID == channelID {
+				states = append(states, all[i])
+			}
+		}
+	}
+	return states
+}
+
+// VoiceChannelJoin is sent when a user joins a voice channel.
+type VoiceChannelJoin struct {
+	// New voice state of the user.
+	*voice.State
+}
+
+// VoiceChannelLeave is sent when a user leaves a voice channel.
+type VoiceChannelLeave struct {
+	// New voice state of the user, its channel ID is nil.
+	*voice.State
+	// Voice state of the user before they left, its
+	// channel ID is the channel they left.
+	Old *voice.State
+}
+
+// VoiceChannelMove is sent when a user moves from a voice channel to another,
+// in the same guild.
+type VoiceChannelMove struct {
+	// New voice state of the user.
+	*voice.State
+	// Voice state of the user before they moved.
+	Old *voice.State
+}
+
+// handleVoiceChannelChange fires the appropriate event if the given voice state
+// update made a user join, leave or move between voice channels.
+func (c *Client) handleVoiceChannelChange(old, vs *voice.State) {
+	var from, to string
+	if old != nil && old.ChannelID != nil {
+		from = *old.ChannelID
+	}
+	if vs.ChannelID != nil {
+		to = *vs.ChannelID
+	}
+
+	switch {
+	case from == to:
+		// Mute, deaf, etc. changes.
+	case from == "":
+		c.handle(eventVoiceChannelJoin, &VoiceChannelJoin{State: vs})
+	case to == "":
+		c.handle(eventVoiceChannelLeave, &VoiceChannelLeave{State: vs, Old: old})
+	default:
+		c.handle(eventVoiceChannelMove, &VoiceChannelMove{State: vs, Old: old})
+	}
+}
+
+type voiceChannelJoinHandler func(*VoiceChannelJoin)
+
+// handle implements the handler interface.
+func (h voiceChannelJoinHandler) handle(v interface{}) {
+	h(v.(*VoiceChannelJoin))
+}
+
+// OnVoiceChannelJoin registers the handler function for the VoiceChannelJoin event.
+// This event is derived from Voice State Update events and is only sent for
+// guilds the State tracks voice states of, meaning the state must be tracked,
+// the guild must be cached and CacheVoiceStates must be set.
+func (c *Client) OnVoiceChannelJoin(f func(j *VoiceChannelJoin)) {
+	c.registerHandler(eventVoiceChannelJoin, voiceChannelJoinHandler(f))
+}
+
+type voiceChannelLeaveHandler func(*VoiceChannelLeave)
+
+// handle implements the handler interface.
+func (h voiceChannelLeaveHandler) handle(v interface{}) {
+	h(v.(*VoiceChannelLeave))
+}
+
+// OnVoiceChannelLeave registers the handler function for the VoiceChannelLeave event.
+// This event is derived from Voice State Update events and is only sent for
+// guilds the State tracks voice states of, meaning the state must be tracked,
+// the guild must be cached and CacheVoiceStates must be set.
+func (c *Client) OnVoiceChannelLeave(f func(l *VoiceChannelLeave)) {
+	c.registerHandler(eventVoiceChannelLeave, voiceChannelLeaveHandler(f))
+}
+
+type voiceChannelMoveHandler func(*VoiceChannelMove)
+
+// handle implements the handler interface.
+func (h voiceChannelMoveHandler) handle(v interface{}) {
+	h(v.(*VoiceChannelMove))
+}
+
+// OnVoiceChannelMove registers the handler function for the VoiceChannelMove event.
+// This event is derived from Voice State Update events and is only sent for
+// guilds the State tracks voice states of, meaning the state must be tracked,
+// the guild must be cached and CacheVoiceStates must be set.
+func (c *Client) OnVoiceChannelMove(f func(m *VoiceChannelMove)) {
+	c.registerHandler(eventVoiceChannelMove, voiceChannelMoveHandler(f))
+}
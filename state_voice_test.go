@@ -0,0 +1,94 @@
+package harmony
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/voice"
+)
+
+func TestVoiceChannelChange(t *testing.T) {
+	tests := []struct {
+		name string
+		// Channel the user was in before the update, empty if none.
+		from   string
+		update string
+		// Derived event, empty if none.
+		expected string
+	}{
+		{
+			name:     "join",
+			update:   `{"guild_id":"g1","user_id":"u1","channel_id":"c1"}`,
+			expected: "join c1",
+		},
+		{
+			name:     "leave",
+			from:     "c1",
+			update:   `{"guild_id":"g1","user_id":"u1","channel_id":null}`,
+			expected: "leave c1",
+		},
+		{
+			name:     "move",
+			from:     "c1",
+			update:   `{"guild_id":"g1","user_id":"u1","channel_id":"c2"}`,
+			expected: "move c1 to c2",
+		},
+		{
+			name:   "same channel",
+			from:   "c1",
+			update: `{"guild_id":"g1","user_id":"u1","channel_id":"c1","self_mute":true}`,
+		},
+		{
+			name:   "unknown previous state",
+			update: `{"guild_id":"g2","user_id":"u1","channel_id":"c1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient("token", WithLogger(testLogger), WithDispatchMode(DispatchModeSync, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Only the voice states of g1 are tracked.
+			if err = c.State.store.SetGuild(&discord.Guild{ID: "g1"}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.from != "" {
+				from := tt.from
+				if err = c.State.store.SetVoiceState(&voice.State{GuildID: "g1", UserID: "u1", ChannelID: &from}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var (
+				update *voice.State
+				got    string
+				state  *voice.State
+			)
+			c.OnVoiceStateUpdate(func(vs *voice.StateUpdate) { update = &vs.State })
+			c.OnVoiceChannelJoin(func(j *VoiceChannelJoin) {
+				got, state = "join "+*j.ChannelID, j.State
+			})
+			c.OnVoiceChannelLeave(func(l *VoiceChannelLeave) {
+				got, state = "leave "+*l.Old.ChannelID, l.State
+			})
+			c.OnVoiceChannelMove(func(m *VoiceChannelMove) {
+				got, state = fmt.Sprintf("move %s to %s", *m.Old.ChannelID, *m.ChannelID), m.State
+			})
+
+			if err = c.dispatch(eventVoiceStateUpdate, json.RawMessage(tt.update)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("expected event %q; got %q", tt.expected, got)
+			}
+			if state != nil && state == update {
+				t.Error("expected derived event not to share the voice state update")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package harmony
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/skwair/harmony/internal/endpoint"
+)
+
+// GetSticker returns a sticker given its ID. It can be a standard sticker or a guild sticker.
+func (c *Client) GetSticker(ctx context.Context, id string) (*discord.Sticker, error) {
+	e := endpoint.GetSticker(id)
+	resp, err := c.restClient.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	var sticker discord.Sticker
+	if err = json.NewDecoder(resp.Body).Decode(&sticker); err != nil {
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// GetStickerPacks returns the list of sticker packs available to Nitro subscribers.
+func (c *Client) GetStickerPacks(ctx context.Context) ([]discord.StickerPack, error) {
+	e := endpoint.ListStickerPacks()
+	resp, err := c.restClient.Do(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, discord.NewAPIError(resp)
+	}
+
+	// The list of packs is wrapped in an object.
+	var body struct {
+		StickerPacks []discord.StickerPack `json:"sticker_packs"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.StickerPacks, nil
+}
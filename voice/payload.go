@@ -3,6 +3,7 @@ package voice
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/skwair/harmony/internal/payload"
 )
@@ -23,7 +24,9 @@ type State struct {
 	SelfDeaf   bool    `json:"self_deaf"`
 	SelfMute   bool    `json:"self_mute"`
 	SelfStream bool    `json:"self_stream"`
-	Suppress   bool    `json:"suppress"` // Whether this user is muted by the current user.
+	Suppress   bool    `json:"suppress"` // Whether this user is not allowed to speak, e.g. in stage channels.
+	// Time at which the user requested to speak in a stage channel, nil if they did not.
+	RequestToSpeakTimestamp *time.Time `json:"request_to_speak_timestamp"`
 }
 
 // Clone returns a clone of this StateUpdate.
@@ -32,17 +35,29 @@ func (v *State) Clone() *State {
 		return nil
 	}
 
-	return &State{
-		GuildID:   v.GuildID,
-		ChannelID: v.ChannelID,
-		UserID:    v.UserID,
-		SessionID: v.SessionID,
-		Deaf:      v.Deaf,
-		Mute:      v.Mute,
-		SelfDeaf:  v.SelfDeaf,
-		SelfMute:  v.SelfMute,
-		Suppress:  v.Suppress,
+	s := &State{
+		GuildID:    v.GuildID,
+		UserID:     v.UserID,
+		SessionID:  v.SessionID,
+		Deaf:       v.Deaf,
+		Mute:       v.Mute,
+		SelfDeaf:   v.SelfDeaf,
+		SelfMute:   v.SelfMute,
+		SelfStream: v.SelfStream,
+		Suppress:   v.Suppress,
 	}
+
+	if v.ChannelID != nil {
+		channelID := *v.ChannelID
+		s.ChannelID = &channelID
+	}
+
+	if v.RequestToSpeakTimestamp != nil {
+		ts := *v.RequestToSpeakTimestamp
+		s.RequestToSpeakTimestamp = &ts
+	}
+
+	return s
 }
 
 // ServerUpdate is the payload describing the update of a voice server.